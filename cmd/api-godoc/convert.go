@@ -0,0 +1,157 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/orchard9/api-godoc/pkg/apigodoc"
+)
+
+// ConvertConfig holds the configuration for the convert subcommand
+type ConvertConfig struct {
+	InputSpec  string
+	OutputFile string
+	To         string
+	Format     string
+	Verbose    bool
+}
+
+// runConvert implements `api-godoc convert`
+func runConvert(args []string) error {
+	config, err := parseConvertFlags(args)
+	if err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+	data, err := apigodoc.Read(ctx, config.InputSpec)
+	if err != nil {
+		return err
+	}
+
+	conversion, err := apigodoc.Convert(ctx, data, config.To)
+	if err != nil {
+		return err
+	}
+
+	for _, warning := range conversion.Warnings {
+		log.Printf("Warning: %s", warning)
+	}
+
+	format := config.Format
+	if format == "" {
+		format = detectOutputFormat(config.OutputFile, data)
+	}
+
+	output, err := conversion.Marshal(format)
+	if err != nil {
+		return err
+	}
+
+	if config.OutputFile == "" {
+		_, err = os.Stdout.Write(output)
+		return err
+	}
+
+	if config.Verbose {
+		log.Printf("Writing %s output to file: %s", format, config.OutputFile)
+	}
+	if err := os.WriteFile(config.OutputFile, output, 0644); err != nil { // #nosec G306 - Spec files should be readable
+		return fmt.Errorf("failed to write output file: %w", err)
+	}
+	fmt.Fprintf(os.Stderr, "Converted specification written to: %s\n", config.OutputFile)
+
+	return nil
+}
+
+// parseConvertFlags parses convert subcommand flags. Flags may appear before
+// or after the input file.
+func parseConvertFlags(args []string) (ConvertConfig, error) {
+	var config ConvertConfig
+
+	fs := flag.NewFlagSet("convert", flag.ContinueOnError)
+	fs.StringVar(&config.To, "to", "", "Target version: 3.0, 3.1, 2.0")
+	fs.StringVar(&config.OutputFile, "output", "", "Output file (default: stdout)")
+	fs.StringVar(&config.OutputFile, "o", "", "Output file (default: stdout)")
+	fs.StringVar(&config.Format, "format", "", "Output format: json, yaml (default: from output extension or input)")
+	fs.StringVar(&config.Format, "f", "", "Output format: json, yaml (default: from output extension or input)")
+	fs.BoolVar(&config.Verbose, "verbose", false, "Enable verbose logging")
+	fs.BoolVar(&config.Verbose, "v", false, "Enable verbose logging")
+	fs.Usage = showConvertHelp
+
+	positional, err := parseInterspersed(fs, args)
+	if err != nil {
+		return config, err
+	}
+	if len(positional) != 1 {
+		showConvertHelp()
+		return config, fmt.Errorf("convert requires exactly one input specification")
+	}
+	config.InputSpec = positional[0]
+
+	if config.To == "" {
+		showConvertHelp()
+		return config, fmt.Errorf("--to is required")
+	}
+
+	if config.Format != "" && config.Format != "json" && config.Format != "yaml" {
+		return config, fmt.Errorf("unsupported output format: %s (expected json or yaml)", config.Format)
+	}
+
+	return config, nil
+}
+
+// parseInterspersed parses flags that may appear before, between or after
+// positional arguments and returns the positional arguments
+func parseInterspersed(fs *flag.FlagSet, args []string) ([]string, error) {
+	var positional []string
+	for {
+		if err := fs.Parse(args); err != nil {
+			return nil, err
+		}
+		if fs.NArg() == 0 {
+			return positional, nil
+		}
+		positional = append(positional, fs.Arg(0))
+		args = fs.Args()[1:]
+	}
+}
+
+// detectOutputFormat picks json or yaml from the output extension, falling back to the input format
+func detectOutputFormat(outputFile string, input []byte) string {
+	switch strings.ToLower(filepath.Ext(outputFile)) {
+	case ".yaml", ".yml":
+		return "yaml"
+	case ".json":
+		return "json"
+	}
+
+	trimmed := strings.TrimSpace(string(input))
+	if strings.HasPrefix(trimmed, "{") {
+		return "json"
+	}
+	return "yaml"
+}
+
+func showConvertHelp() {
+	fmt.Println("API GoDoc - OpenAPI Version Converter")
+	fmt.Println("")
+	fmt.Println("USAGE:")
+	fmt.Println("  api-godoc convert --to <version> [options] <openapi-spec>")
+	fmt.Println("")
+	fmt.Println("OPTIONS:")
+	fmt.Println("      --to <version>     Target version: 3.0, 3.1, 2.0")
+	fmt.Println("  -o, --output <file>    Output file (default: stdout)")
+	fmt.Println("  -f, --format <format>  Output format: json, yaml (default: from output extension or input)")
+	fmt.Println("  -v, --verbose          Enable verbose logging")
+	fmt.Println("")
+	fmt.Println("EXAMPLES:")
+	fmt.Println("  api-godoc convert --to 3.1 api.yaml -o api-3.1.yaml")
+	fmt.Println("  api-godoc convert --to 3.0 swagger.json -o openapi.json")
+	fmt.Println("  api-godoc convert --to 2.0 openapi.yaml -f json")
+}
@@ -0,0 +1,244 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/orchard9/api-godoc/pkg/apigodoc"
+	"github.com/orchard9/api-godoc/pkg/models"
+)
+
+// GraphConfig holds the configuration for the graph subcommand
+type GraphConfig struct {
+	Config
+	Cluster   string
+	Focus     string
+	Hops      int
+	MaxNodes  int
+	MaxFields int
+}
+
+// GraphQueryConfig holds the configuration for the graph query subcommand
+type GraphQueryConfig struct {
+	Config
+	Query apigodoc.GraphQuery
+}
+
+// runGraph implements `api-godoc graph`
+func runGraph(args []string) error {
+	if len(args) > 0 && args[0] == "query" {
+		return runGraphQuery(args[1:])
+	}
+
+	config, err := parseGraphFlags(args)
+	if err != nil {
+		return err
+	}
+	if config.Verbose {
+		log.Printf("Exporting %s graph of %s", config.Format, config.InputSpec)
+	}
+
+	analysis, err := analyzeSpec(config.Config)
+	if err != nil {
+		return err
+	}
+
+	var output bytes.Buffer
+	opts := []apigodoc.GraphOption{apigodoc.WithMaxNodes(config.MaxNodes), apigodoc.WithMaxFields(config.MaxFields)}
+	if config.Focus != "" {
+		opts = append(opts, apigodoc.WithFocus(config.Focus, config.Hops))
+	}
+	if err := apigodoc.ExportGraph(&output, analysis, apigodoc.GraphFormat(config.Format), config.Cluster, opts...); err != nil {
+		return err
+	}
+	return writeGraphOutput(config.OutputFile, output.Bytes())
+}
+
+// runGraphQuery implements `api-godoc graph query`
+func runGraphQuery(args []string) error {
+	config, err := parseGraphQueryFlags(args)
+	if err != nil {
+		return err
+	}
+	analysis, err := analyzeSpec(config.Config)
+	if err != nil {
+		return err
+	}
+
+	result, err := apigodoc.QueryGraph(analysis, config.Query)
+	if err != nil {
+		return err
+	}
+	var output bytes.Buffer
+	if err := apigodoc.RenderGraphQuery(&output, result, apigodoc.Format(config.Format)); err != nil {
+		return err
+	}
+	return writeGraphOutput(config.OutputFile, output.Bytes())
+}
+
+// analyzeSpec loads and analyzes the input specification of a graph command
+func analyzeSpec(config Config) (*models.APIAnalysis, error) {
+	ctx := context.Background()
+	opts, err := analysisOptions(config)
+	if err != nil {
+		return nil, err
+	}
+	spec, err := apigodoc.Load(ctx, config.InputSpec)
+	if err != nil {
+		return nil, err
+	}
+	return apigodoc.Analyze(ctx, spec, opts...)
+}
+
+// writeGraphOutput writes graph command output to a file, or stdout without one
+func writeGraphOutput(outputFile string, output []byte) error {
+	if outputFile == "" {
+		_, err := os.Stdout.Write(output)
+		return err
+	}
+	if err := os.WriteFile(outputFile, output, 0644); err != nil { // #nosec G306 - Graph files should be readable
+		return fmt.Errorf("failed to write output file: %w", err)
+	}
+	fmt.Fprintf(os.Stderr, "Graph written to: %s\n", outputFile)
+	return nil
+}
+
+// parseGraphFlags parses graph subcommand flags. Flags may appear before or
+// after the input file.
+func parseGraphFlags(args []string) (GraphConfig, error) {
+	var config GraphConfig
+
+	fs := flag.NewFlagSet("graph", flag.ContinueOnError)
+	fs.StringVar(&config.Format, "format", string(apigodoc.GraphDOT), "Graph format: dot, plantuml, d2, graphml, cytoscape, mermaid-er, mermaid-class")
+	fs.StringVar(&config.Format, "f", string(apigodoc.GraphDOT), "Graph format: dot, plantuml, d2, graphml, cytoscape, mermaid-er, mermaid-class")
+	fs.StringVar(&config.Cluster, "cluster", apigodoc.ClusterCategory, "Cluster resources by: category, tag, none")
+	fs.StringVar(&config.Focus, "focus", "", "Only this resource and its neighbors")
+	fs.IntVar(&config.Hops, "hops", 1, "Relationships to follow from the --focus resource")
+	fs.IntVar(&config.MaxNodes, "max-nodes", 0, "Keep at most this many resources (0: all)")
+	fs.IntVar(&config.MaxFields, "max-fields", 0, "Keep at most this many fields per resource in Mermaid formats (0: all)")
+	addGraphAnalysisFlags(fs, &config.Config)
+	fs.Usage = showGraphHelp
+
+	positional, err := parseInterspersed(fs, args)
+	if err != nil {
+		return config, err
+	}
+	if len(positional) != 1 {
+		showGraphHelp()
+		return config, fmt.Errorf("graph requires exactly one input specification")
+	}
+	config.InputSpec = positional[0]
+
+	return config, nil
+}
+
+// parseGraphQueryFlags parses graph query subcommand flags. Flags may
+// appear before or after the input file.
+func parseGraphQueryFlags(args []string) (GraphQueryConfig, error) {
+	var config GraphQueryConfig
+
+	fs := flag.NewFlagSet("graph query", flag.ContinueOnError)
+	fs.StringVar(&config.Query.From, "from", "", "Shortest paths from this resource (with --to)")
+	fs.StringVar(&config.Query.To, "to", "", "Shortest paths to this resource (with --from)")
+	fs.StringVar(&config.Query.Dependents, "dependents", "", "Resources that depend on this resource")
+	fs.BoolVar(&config.Query.Cycles, "cycles", false, "Dependency cycles")
+	fs.BoolVar(&config.Query.Orphans, "orphans", false, "Resources without relationships")
+	fs.IntVar(&config.Query.Degrees, "top", 0, "The N most connected resources")
+	fs.StringVar(&config.Format, "format", string(apigodoc.FormatText), "Output format: text, json")
+	fs.StringVar(&config.Format, "f", string(apigodoc.FormatText), "Output format: text, json")
+	addGraphAnalysisFlags(fs, &config.Config)
+	fs.Usage = showGraphQueryHelp
+
+	positional, err := parseInterspersed(fs, args)
+	if err != nil {
+		return config, err
+	}
+	if len(positional) != 1 {
+		showGraphQueryHelp()
+		return config, fmt.Errorf("graph query requires exactly one input specification")
+	}
+	config.InputSpec = positional[0]
+
+	return config, nil
+}
+
+// addGraphAnalysisFlags defines the output and analysis flags the graph
+// commands share
+func addGraphAnalysisFlags(fs *flag.FlagSet, config *Config) {
+	fs.StringVar(&config.OutputFile, "output", "", "Output file (default: stdout)")
+	fs.StringVar(&config.OutputFile, "o", "", "Output file (default: stdout)")
+	fs.StringVar(&config.GroupBy, "group-by", apigodoc.GroupByPath, "Resource grouping: path, tag, operationId, extension, hybrid")
+	fs.StringVar(&config.GroupExtension, "group-extension", apigodoc.DefaultGroupExtension, "Vendor extension read by --group-by extension")
+	fs.StringVar(&config.ConfigFile, "config", "", "YAML configuration file with naming and path segment overrides")
+	fs.BoolVar(&config.SeparateNested, "separate-nested", false, "Keep nested resources apart from same-named top-level resources")
+	fs.Float64Var(&config.MinConfidence, "min-confidence", 0, "Leave out relationships below this confidence (0 to 1)")
+	fs.BoolVar(&config.Verbose, "verbose", false, "Enable verbose logging")
+	fs.BoolVar(&config.Verbose, "v", false, "Enable verbose logging")
+}
+
+func showGraphHelp() {
+	fmt.Println("API GoDoc - Resource Relationship Graph Export")
+	fmt.Println("")
+	fmt.Println("USAGE:")
+	fmt.Println("  api-godoc graph [options] <openapi-spec>")
+	fmt.Println("  api-godoc graph query [queries] [options] <openapi-spec>")
+	fmt.Println("")
+	fmt.Println("OPTIONS:")
+	fmt.Println("  -f, --format <format>  Graph format: dot, plantuml, d2, graphml, cytoscape,")
+	fmt.Println("                         mermaid-er, mermaid-class (default: dot)")
+	fmt.Println("      --cluster <mode>   Cluster resources by: category, tag, none (default: category)")
+	fmt.Println("      --focus <resource> Only this resource and the resources around it")
+	fmt.Println("      --hops <n>         Relationships to follow from the --focus resource (default: 1)")
+	fmt.Println("      --max-nodes <n>    Keep at most n resources, nearest and most connected first (default: all)")
+	fmt.Println("      --max-fields <n>   Keep at most n fields per resource in Mermaid formats (default: all)")
+	fmt.Println("  -o, --output <file>    Output file (default: stdout)")
+	fmt.Println("      --group-by <mode>  Resource grouping: path, tag, operationId, extension, hybrid (default: path)")
+	fmt.Println("      --group-extension <name>")
+	fmt.Println("                         Vendor extension for --group-by extension (default: x-resource)")
+	fmt.Println("      --config <file>    YAML configuration file (naming and path segment rules)")
+	fmt.Println("      --separate-nested  Keep nested resources (/features/{id}/tasks) apart from top-level ones")
+	fmt.Println("      --min-confidence <n>")
+	fmt.Println("                         Leave out relationships below this confidence, from 0 to 1 (default: 0)")
+	fmt.Println("  -v, --verbose          Enable verbose logging")
+	fmt.Println("")
+	fmt.Println("EXAMPLES:")
+	fmt.Println("  api-godoc graph api.yaml | dot -Tsvg -o resources.svg")
+	fmt.Println("  api-godoc graph -f graphml --cluster tag api.yaml -o resources.graphml")
+	fmt.Println("  api-godoc graph -f cytoscape --min-confidence 0.8 api.yaml -o resources.json")
+	fmt.Println("  api-godoc graph -f mermaid-er --focus users --hops 2 --max-fields 8 api.yaml")
+}
+
+func showGraphQueryHelp() {
+	fmt.Println("API GoDoc - Resource Relationship Graph Queries")
+	fmt.Println("")
+	fmt.Println("USAGE:")
+	fmt.Println("  api-godoc graph query [queries] [options] <openapi-spec>")
+	fmt.Println("")
+	fmt.Println("QUERIES:")
+	fmt.Println("      --from <resource> --to <resource>")
+	fmt.Println("                         Shortest paths between two resources and the calls that walk them")
+	fmt.Println("      --dependents <resource>")
+	fmt.Println("                         Resources that belong to or reference a resource, directly or not")
+	fmt.Println("      --cycles           Resources that depend on each other")
+	fmt.Println("      --orphans          Resources without relationships")
+	fmt.Println("      --top <n>          The n most connected resources")
+	fmt.Println("")
+	fmt.Println("OPTIONS:")
+	fmt.Println("  -f, --format <format>  Output format: text, json (default: text)")
+	fmt.Println("  -o, --output <file>    Output file (default: stdout)")
+	fmt.Println("      --group-by <mode>  Resource grouping: path, tag, operationId, extension, hybrid (default: path)")
+	fmt.Println("      --config <file>    YAML configuration file (naming and path segment rules)")
+	fmt.Println("      --separate-nested  Keep nested resources (/features/{id}/tasks) apart from top-level ones")
+	fmt.Println("      --min-confidence <n>")
+	fmt.Println("                         Leave out relationships below this confidence, from 0 to 1 (default: 0)")
+	fmt.Println("  -v, --verbose          Enable verbose logging")
+	fmt.Println("")
+	fmt.Println("EXAMPLES:")
+	fmt.Println("  api-godoc graph query --from Organization --to Invoice api.yaml")
+	fmt.Println("  api-godoc graph query --dependents users -f json api.yaml")
+	fmt.Println("  api-godoc graph query --cycles --orphans --top 10 api.yaml")
+}
@@ -1,18 +1,17 @@
 package main
 
 import (
+	"bytes"
+	"context"
+	"errors"
 	"flag"
 	"fmt"
 	"log"
 	"os"
 	"runtime"
 	"strings"
-	"time"
 
-	"github.com/orchard9/api-godoc/internal/analyzer"
-	"github.com/orchard9/api-godoc/internal/parser"
-	"github.com/orchard9/api-godoc/internal/reporter"
-	"github.com/orchard9/api-godoc/pkg/models"
+	"github.com/orchard9/api-godoc/pkg/apigodoc"
 )
 
 // Build-time variables (set by Makefile)
@@ -25,19 +24,54 @@ var (
 // Config holds the CLI configuration
 type Config struct {
 	InputSpec      string
+	OtherSpecs     []string // further specifications, analyzed with InputSpec as services of one system
 	OutputFile     string
 	Format         string
 	SchemaLevel    string
 	Include        string
 	Exclude        string
 	ResourceFilter string
+	Incomplete     bool
+	IncludeCats    string
+	ExcludeCats    string
+	GroupBy        string
+	GroupExtension string
+	SeparateNested bool
+	MinConfidence  float64
+	ConfigFile     string
 	Verbose        bool
 	ShowVersion    bool
 	ShowHelp       bool
 }
 
 func main() {
-	config := parseFlags()
+	// Subcommands
+	if len(os.Args) > 1 && os.Args[1] == "convert" {
+		if err := runConvert(os.Args[2:]); err != nil {
+			if errors.Is(err, flag.ErrHelp) {
+				return
+			}
+			log.Fatalf("Error converting API: %v", err)
+		}
+		return
+	}
+	if len(os.Args) > 1 && os.Args[1] == "graph" {
+		if err := runGraph(os.Args[2:]); err != nil {
+			if errors.Is(err, flag.ErrHelp) {
+				return
+			}
+			log.Fatalf("Error in graph command: %v", err)
+		}
+		return
+	}
+
+	config, err := parseFlags(os.Args[1:])
+	if err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return
+		}
+		os.Exit(2)
+	}
 
 	if config.ShowVersion {
 		showVersion()
@@ -68,135 +102,107 @@ func main() {
 	}
 }
 
-// parseFlags parses command-line flags and returns configuration
-func parseFlags() Config {
+// parseFlags parses command-line flags and returns configuration. Flags may
+// appear before or after the input files.
+func parseFlags(args []string) (Config, error) {
 	var config Config
 
+	fs := flag.NewFlagSet("api-godoc", flag.ContinueOnError)
+
 	// Define flags
-	flag.StringVar(&config.OutputFile, "output", "", "Output file (default: stdout)")
-	flag.StringVar(&config.OutputFile, "o", "", "Output file (default: stdout)")
-	flag.StringVar(&config.Format, "format", "markdown", "Output format: markdown, json, ai")
-	flag.StringVar(&config.Format, "f", "markdown", "Output format: markdown, json, ai")
-	flag.StringVar(&config.SchemaLevel, "schema", "standard", "Schema detail level: essential, standard, full")
-	flag.StringVar(&config.SchemaLevel, "s", "standard", "Schema detail level: essential, standard, full")
-	flag.StringVar(&config.Include, "include", "", "Comma-separated list of resources to include")
-	flag.StringVar(&config.Include, "i", "", "Comma-separated list of resources to include")
-	flag.StringVar(&config.Exclude, "exclude", "", "Comma-separated list of resources to exclude")
-	flag.StringVar(&config.Exclude, "e", "", "Comma-separated list of resources to exclude")
-	flag.StringVar(&config.ResourceFilter, "filter", "", "Regex pattern to filter resources")
-	flag.BoolVar(&config.Verbose, "verbose", false, "Enable verbose logging")
-	flag.BoolVar(&config.Verbose, "v", false, "Enable verbose logging")
-	flag.BoolVar(&config.ShowVersion, "version", false, "Show version information")
-	flag.BoolVar(&config.ShowHelp, "help", false, "Show help message")
-	flag.BoolVar(&config.ShowHelp, "h", false, "Show help message")
+	fs.StringVar(&config.OutputFile, "output", "", "Output file (default: stdout)")
+	fs.StringVar(&config.OutputFile, "o", "", "Output file (default: stdout)")
+	fs.StringVar(&config.Format, "format", "markdown", "Output format: markdown, json, ai")
+	fs.StringVar(&config.Format, "f", "markdown", "Output format: markdown, json, ai")
+	fs.StringVar(&config.SchemaLevel, "schema", "standard", "Schema detail level: essential, standard, full")
+	fs.StringVar(&config.SchemaLevel, "s", "standard", "Schema detail level: essential, standard, full")
+	fs.StringVar(&config.Include, "include", "", "Comma-separated list of resources to include")
+	fs.StringVar(&config.Include, "i", "", "Comma-separated list of resources to include")
+	fs.StringVar(&config.Exclude, "exclude", "", "Comma-separated list of resources to exclude")
+	fs.StringVar(&config.Exclude, "e", "", "Comma-separated list of resources to exclude")
+	fs.StringVar(&config.ResourceFilter, "filter", "", "Regex pattern to filter resources")
+	fs.BoolVar(&config.Incomplete, "incomplete", false, "Only show resources with capability gaps")
+	fs.StringVar(&config.IncludeCats, "include-category", "", "Comma-separated list of resource categories to include")
+	fs.StringVar(&config.ExcludeCats, "exclude-category", "", "Comma-separated list of resource categories to exclude")
+	fs.StringVar(&config.GroupBy, "group-by", apigodoc.GroupByPath, "Resource grouping: path, tag, operationId, extension, hybrid")
+	fs.StringVar(&config.GroupExtension, "group-extension", apigodoc.DefaultGroupExtension, "Vendor extension read by --group-by extension")
+	fs.StringVar(&config.ConfigFile, "config", "", "YAML configuration file with naming and path segment overrides")
+	fs.BoolVar(&config.SeparateNested, "separate-nested", false, "Keep nested resources apart from same-named top-level resources")
+	fs.Float64Var(&config.MinConfidence, "min-confidence", 0, "Hide relationships below this confidence (0 to 1)")
+	fs.BoolVar(&config.Verbose, "verbose", false, "Enable verbose logging")
+	fs.BoolVar(&config.Verbose, "v", false, "Enable verbose logging")
+	fs.BoolVar(&config.ShowVersion, "version", false, "Show version information")
+	fs.BoolVar(&config.ShowHelp, "help", false, "Show help message")
+	fs.BoolVar(&config.ShowHelp, "h", false, "Show help message")
 
 	// Custom usage function
-	flag.Usage = showHelp
+	fs.Usage = showHelp
 
-	// Parse flags
-	flag.Parse()
+	positional, err := parseInterspersed(fs, args)
+	if err != nil {
+		return config, err
+	}
 
-	// Get positional argument (input spec)
-	if flag.NArg() > 0 {
-		config.InputSpec = flag.Arg(0)
+	// Get positional arguments (input specs)
+	if len(positional) > 0 {
+		config.InputSpec = positional[0]
+		config.OtherSpecs = positional[1:]
 	}
 
-	return config
+	return config, nil
 }
 
 // processAPI processes the OpenAPI specification and generates output
 func processAPI(config Config) error {
+	sources := append([]string{config.InputSpec}, config.OtherSpecs...)
 	if config.Verbose {
-		log.Printf("Processing OpenAPI specification: %s", config.InputSpec)
+		log.Printf("Processing OpenAPI specification: %s", strings.Join(sources, ", "))
 		log.Printf("Output format: %s", config.Format)
 		if config.OutputFile != "" {
 			log.Printf("Output file: %s", config.OutputFile)
 		}
 	}
 
-	// Initialize components
-	p := parser.New()
-	resourceAnalyzer := analyzer.NewResourceAnalyzer()
-	relationshipDetector := analyzer.NewRelationshipDetector()
-	patternDetector := analyzer.NewPatternDetector()
-	schemaReducer := analyzer.NewSchemaReducer()
-	rep := reporter.New()
-
-	// Parse the OpenAPI specification
-	var spec *parser.OpenAPISpec
-	var err error
-
-	// Check if input is a URL or file path
-	if strings.HasPrefix(config.InputSpec, "http://") || strings.HasPrefix(config.InputSpec, "https://") {
-		spec, err = p.ParseURL(config.InputSpec)
-	} else {
-		spec, err = p.ParseFile(config.InputSpec)
-	}
-
+	ctx := context.Background()
+	opts, err := analysisOptions(config)
 	if err != nil {
-		return fmt.Errorf("failed to parse specification: %w", err)
+		return err
 	}
 
-	// Extract resources
-	if config.Verbose {
-		log.Println("Extracting resources from specification")
+	// Parse the OpenAPI specifications
+	var specs []*apigodoc.Spec
+	for _, source := range sources {
+		spec, err := apigodoc.Load(ctx, source)
+		if err != nil {
+			return err
+		}
+		specs = append(specs, spec)
 	}
-	resources := resourceAnalyzer.ExtractResources(spec)
 
-	// Apply resource filtering
-	if config.Include != "" || config.Exclude != "" || config.ResourceFilter != "" {
+	// Generate output; several specifications are analyzed as services
+	var output bytes.Buffer
+	if len(specs) > 1 {
+		system, err := apigodoc.AnalyzeServices(ctx, specs, opts...)
+		if err != nil {
+			return err
+		}
 		if config.Verbose {
-			log.Println("Applying resource filters")
+			log.Printf("Generating %s output for %d services", config.Format, len(system.Services))
+		}
+		if err := apigodoc.RenderSystem(&output, system, apigodoc.Format(config.Format)); err != nil {
+			return err
 		}
-		filter := buildResourceFilter(config)
-		if err := filter.Validate(); err != nil {
-			return fmt.Errorf("invalid resource filter: %w", err)
+	} else {
+		analysis, err := apigodoc.Analyze(ctx, specs[0], opts...)
+		if err != nil {
+			return err
 		}
-		filterer := analyzer.NewResourceFilterer()
-		resources = filterer.FilterResources(resources, filter)
 		if config.Verbose {
-			log.Printf("Filtered to %d resources", len(resources))
+			log.Printf("Generating %s output", config.Format)
+		}
+		if err := apigodoc.Render(&output, analysis, apigodoc.Format(config.Format)); err != nil {
+			return err
 		}
-	}
-
-	// Extract fields for resources
-	if config.Verbose {
-		log.Println("Extracting resource fields with schema reduction")
-	}
-	extractResourceFields(resources, spec, schemaReducer, config.SchemaLevel)
-
-	// Detect relationships
-	if config.Verbose {
-		log.Println("Detecting resource relationships")
-	}
-	relationshipDetector.DetectRelationships(resources, spec)
-
-	// Detect patterns
-	if config.Verbose {
-		log.Println("Detecting API patterns")
-	}
-	patterns := patternDetector.DetectPatterns(spec)
-
-	// Create analysis result
-	analysis := &models.APIAnalysis{
-		Title:       getAPITitle(spec),
-		Version:     getAPIVersion(spec),
-		Description: getAPIDescription(spec),
-		BaseURL:     getBaseURL(spec),
-		SpecType:    getSpecType(spec),
-		GeneratedAt: time.Now(),
-		Resources:   resources,
-		Summary:     calculateSummary(resources, spec),
-		Patterns:    patterns,
-	}
-
-	// Generate output
-	if config.Verbose {
-		log.Printf("Generating %s output", config.Format)
-	}
-	output, err := rep.Generate(analysis, config.Format)
-	if err != nil {
-		return fmt.Errorf("failed to generate output: %w", err)
 	}
 
 	// Write output
@@ -204,71 +210,39 @@ func processAPI(config Config) error {
 		if config.Verbose {
 			log.Printf("Writing output to file: %s", config.OutputFile)
 		}
-		err = os.WriteFile(config.OutputFile, []byte(output), 0644) // #nosec G306 - Documentation files should be readable
+		err = os.WriteFile(config.OutputFile, output.Bytes(), 0644) // #nosec G306 - Documentation files should be readable
 		if err != nil {
 			return fmt.Errorf("failed to write output file: %w", err)
 		}
 		fmt.Printf("Documentation generated: %s\n", config.OutputFile)
 	} else {
-		fmt.Print(output)
+		fmt.Print(output.String())
 	}
 
 	return nil
 }
 
-// Helper functions for extracting API metadata
-func getAPITitle(spec *parser.OpenAPISpec) string {
-	if spec.Info.Title != "" {
-		return spec.Info.Title
-	}
-	return "API Documentation"
-}
-
-func getAPIVersion(spec *parser.OpenAPISpec) string {
-	if spec.Info.Version != "" {
-		return spec.Info.Version
-	}
-	return "1.0.0"
-}
-
-func getAPIDescription(spec *parser.OpenAPISpec) string {
-	return spec.Info.Description
-}
-
-func getBaseURL(spec *parser.OpenAPISpec) string {
-	if len(spec.Servers) > 0 && spec.Servers[0].URL != "" {
-		return spec.Servers[0].URL
-	}
-	return ""
-}
-
-func getSpecType(spec *parser.OpenAPISpec) string {
-	if spec.OpenAPI != "" {
-		return "OpenAPI " + spec.OpenAPI
-	}
-	return "Unknown"
-}
-
-func calculateSummary(resources []models.Resource, spec *parser.OpenAPISpec) models.AnalysisStat {
-	totalOperations := 0
-	totalEndpoints := len(spec.Paths)
-
-	for _, resource := range resources {
-		totalOperations += len(resource.Operations)
-	}
-
-	// Calculate resource coverage (what percentage of endpoints are resource operations)
-	resourceCoverage := 0
-	if totalEndpoints > 0 {
-		resourceCoverage = (totalOperations * 100) / totalEndpoints
+// analysisOptions converts the analysis flags to facade options
+func analysisOptions(config Config) ([]apigodoc.Option, error) {
+	opts := []apigodoc.Option{
+		apigodoc.WithSchemaLevel(apigodoc.SchemaLevel(config.SchemaLevel)),
+		apigodoc.WithFilter(buildResourceFilter(config)),
+		apigodoc.WithGrouping(config.GroupBy),
+		apigodoc.WithGroupExtension(config.GroupExtension),
+		apigodoc.WithSeparateNested(config.SeparateNested),
+		apigodoc.WithMinConfidence(config.MinConfidence),
+	}
+	if config.ConfigFile != "" {
+		cfg, err := apigodoc.LoadConfig(config.ConfigFile)
+		if err != nil {
+			return nil, err
+		}
+		opts = append(opts, apigodoc.WithConfig(cfg))
 	}
-
-	return models.AnalysisStat{
-		TotalResources:   len(resources),
-		TotalOperations:  totalOperations,
-		TotalEndpoints:   totalEndpoints,
-		ResourceCoverage: resourceCoverage,
+	if config.Verbose {
+		opts = append(opts, apigodoc.WithLogger(log.Default()))
 	}
+	return opts, nil
 }
 
 func showVersion() {
@@ -279,71 +253,11 @@ func showVersion() {
 	fmt.Printf("Platform: %s/%s\n", runtime.GOOS, runtime.GOARCH)
 }
 
-// titleCase converts a string to title case (replacement for deprecated strings.Title)
-func titleCase(s string) string {
-	if s == "" {
-		return ""
-	}
-	return strings.ToUpper(s[:1]) + strings.ToLower(s[1:])
-}
-
-// extractResourceFields extracts fields from request/response schemas for resources
-func extractResourceFields(resources []models.Resource, spec *parser.OpenAPISpec, reducer analyzer.SchemaReducer, level string) {
-	// For each resource, try to find associated schemas
-	for i := range resources {
-		resource := &resources[i]
-
-		// Check component schemas that match resource name
-		if spec.Components != nil && spec.Components.Schemas != nil {
-			// Try common schema naming patterns
-			schemaNames := []string{
-				resource.Name,
-				titleCase(strings.ToLower(resource.Name)),
-				resource.Name + "Response",
-				resource.Name + "Request",
-				resource.Name + "Model",
-				resource.Name + "DTO",
-			}
-
-			for _, name := range schemaNames {
-				if schema, ok := spec.Components.Schemas[name]; ok {
-					fields := reducer.SchemaToFields(&schema, level)
-					resource.Fields = mergeFields(resource.Fields, fields)
-				}
-			}
-		}
-	}
-}
-
-// mergeFields merges new fields into existing fields, avoiding duplicates
-func mergeFields(existing, new []models.Field) []models.Field {
-	fieldMap := make(map[string]models.Field)
-
-	// Add existing fields
-	for _, field := range existing {
-		fieldMap[field.Name] = field
-	}
-
-	// Add new fields
-	for _, field := range new {
-		if _, exists := fieldMap[field.Name]; !exists {
-			fieldMap[field.Name] = field
-		}
-	}
-
-	// Convert back to slice
-	result := make([]models.Field, 0, len(fieldMap))
-	for _, field := range fieldMap {
-		result = append(result, field)
-	}
-
-	return result
-}
-
-// buildResourceFilter creates a ResourceFilter from config
-func buildResourceFilter(config Config) *analyzer.ResourceFilter {
-	filter := &analyzer.ResourceFilter{
-		Pattern: config.ResourceFilter,
+// buildResourceFilter creates a resource filter from config
+func buildResourceFilter(config Config) apigodoc.Filter {
+	filter := apigodoc.Filter{
+		Pattern:    config.ResourceFilter,
+		Incomplete: config.Incomplete,
 	}
 
 	// Parse include list
@@ -356,6 +270,14 @@ func buildResourceFilter(config Config) *analyzer.ResourceFilter {
 		filter.Exclude = parseCommaSeparated(config.Exclude)
 	}
 
+	// Parse category lists
+	if config.IncludeCats != "" {
+		filter.IncludeCategories = parseCommaSeparated(config.IncludeCats)
+	}
+	if config.ExcludeCats != "" {
+		filter.ExcludeCategories = parseCommaSeparated(config.ExcludeCats)
+	}
+
 	return filter
 }
 
@@ -376,10 +298,15 @@ func showHelp() {
 	fmt.Println("API GoDoc - OpenAPI Documentation Generator")
 	fmt.Println("")
 	fmt.Println("USAGE:")
-	fmt.Println("  api-godoc [options] <openapi-spec>")
+	fmt.Println("  api-godoc [options] <openapi-spec> [<openapi-spec>...]")
+	fmt.Println("  api-godoc convert --to <version> [options] <openapi-spec>")
+	fmt.Println("  api-godoc graph [options] <openapi-spec>")
+	fmt.Println("  api-godoc graph query [queries] [options] <openapi-spec>")
 	fmt.Println("")
 	fmt.Println("ARGUMENTS:")
 	fmt.Println("  <openapi-spec>    OpenAPI specification file (JSON/YAML) or URL")
+	fmt.Println("                    Several specifications are analyzed as services of one system,")
+	fmt.Println("                    with the dependencies between them")
 	fmt.Println("")
 	fmt.Println("OPTIONS:")
 	fmt.Println("  -o, --output <file>    Output file (default: api-docs.md)")
@@ -388,6 +315,18 @@ func showHelp() {
 	fmt.Println("  -i, --include <list>   Comma-separated list of resources to include")
 	fmt.Println("  -e, --exclude <list>   Comma-separated list of resources to exclude")
 	fmt.Println("      --filter <regex>   Regex pattern to filter resources")
+	fmt.Println("      --incomplete       Only show resources with capability gaps (e.g. create without delete)")
+	fmt.Println("      --include-category <list>")
+	fmt.Println("                         Comma-separated categories to include: core, admin, auth, utility, ops, integration")
+	fmt.Println("      --exclude-category <list>")
+	fmt.Println("                         Comma-separated categories to exclude")
+	fmt.Println("      --group-by <mode>  Resource grouping: path, tag, operationId, extension, hybrid (default: path)")
+	fmt.Println("      --group-extension <name>")
+	fmt.Println("                         Vendor extension for --group-by extension (default: x-resource)")
+	fmt.Println("      --config <file>    YAML configuration file (naming and path segment rules)")
+	fmt.Println("      --separate-nested  Keep nested resources (/features/{id}/tasks) apart from top-level ones")
+	fmt.Println("      --min-confidence <n>")
+	fmt.Println("                         Hide relationships below this confidence, from 0 to 1 (default: 0)")
 	fmt.Println("  -v, --verbose          Enable verbose logging")
 	fmt.Println("      --version          Show version information")
 	fmt.Println("  -h, --help             Show this help message")
@@ -396,6 +335,11 @@ func showHelp() {
 	fmt.Println("  api-godoc api-spec.json")
 	fmt.Println("  api-godoc -f json -o analysis.json api-spec.json")
 	fmt.Println("  api-godoc https://api.example.com/openapi.json")
+	fmt.Println("  api-godoc --group-by hybrid api-spec.json")
+	fmt.Println("  api-godoc -o system.md billing.yaml invoicing.yaml")
+	fmt.Println("  api-godoc convert --to 3.1 api.yaml -o api-3.1.yaml")
+	fmt.Println("  api-godoc graph -f dot api-spec.json | dot -Tsvg -o resources.svg")
+	fmt.Println("  api-godoc graph query --from organizations --to invoices api-spec.json")
 	fmt.Println("")
 	fmt.Println("For more information, visit: https://github.com/orchard9/api-godoc")
 }
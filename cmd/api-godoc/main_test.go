@@ -7,11 +7,24 @@ import (
 	"path/filepath"
 	"strings"
 	"testing"
+
+	"github.com/orchard9/api-godoc/pkg/apigodoc"
 )
 
 func TestParseFlags(t *testing.T) {
-	// Note: flag parsing tests are complex due to global state
-	// This is a simplified test for the core logic
+	t.Run("flags after input", func(t *testing.T) {
+		config, err := parseFlags([]string{"spec.json", "-o", "out.md", "other.yaml", "--format", "json"})
+		if err != nil {
+			t.Fatalf("parseFlags() error = %v", err)
+		}
+		if config.InputSpec != "spec.json" || config.OutputFile != "out.md" || config.Format != "json" {
+			t.Errorf("Unexpected config: %+v", config)
+		}
+		if len(config.OtherSpecs) != 1 || config.OtherSpecs[0] != "other.yaml" {
+			t.Errorf("Expected other specs [other.yaml], got %v", config.OtherSpecs)
+		}
+	})
+
 	t.Run("parseCommaSeparated", func(t *testing.T) {
 		tests := []struct {
 			input string
@@ -82,6 +95,20 @@ func TestBuildResourceFilter(t *testing.T) {
 	}
 }
 
+func TestBuildResourceFilterCategories(t *testing.T) {
+	filter := buildResourceFilter(Config{IncludeCats: "core, auth", ExcludeCats: "ops", Incomplete: true})
+
+	if len(filter.IncludeCategories) != 2 || filter.IncludeCategories[1] != "auth" {
+		t.Errorf("IncludeCategories = %v, want [core auth]", filter.IncludeCategories)
+	}
+	if len(filter.ExcludeCategories) != 1 || filter.ExcludeCategories[0] != "ops" {
+		t.Errorf("ExcludeCategories = %v, want [ops]", filter.ExcludeCategories)
+	}
+	if !filter.Incomplete {
+		t.Error("Expected incomplete filter to be set")
+	}
+}
+
 func TestParseCommaSeparated(t *testing.T) {
 	tests := []struct {
 		name  string
@@ -199,6 +226,42 @@ func TestProcessAPIErrors(t *testing.T) {
 			},
 			wantErr: true,
 		},
+		{
+			name: "Tag grouping",
+			config: Config{
+				InputSpec: createTestSpec(t, tmpDir),
+				Format:    "markdown",
+				GroupBy:   "tag",
+			},
+			wantErr: false,
+		},
+		{
+			name: "Several specs as services",
+			config: Config{
+				InputSpec:  createTestSpec(t, tmpDir),
+				OtherSpecs: []string{createTestSpec(t, t.TempDir())},
+				Format:     "markdown",
+			},
+			wantErr: false,
+		},
+		{
+			name: "Missing second spec",
+			config: Config{
+				InputSpec:  createTestSpec(t, tmpDir),
+				OtherSpecs: []string{filepath.Join(tmpDir, "nonexistent.json")},
+				Format:     "markdown",
+			},
+			wantErr: true,
+		},
+		{
+			name: "Unknown grouping strategy",
+			config: Config{
+				InputSpec: createTestSpec(t, tmpDir),
+				Format:    "markdown",
+				GroupBy:   "bogus",
+			},
+			wantErr: true,
+		},
 	}
 
 	for _, tt := range tests {
@@ -211,27 +274,6 @@ func TestProcessAPIErrors(t *testing.T) {
 	}
 }
 
-func TestTitleCase(t *testing.T) {
-	tests := []struct {
-		input string
-		want  string
-	}{
-		{"hello", "Hello"},
-		{"WORLD", "World"},
-		{"", ""},
-		{"a", "A"},
-		{"ABC", "Abc"},
-	}
-
-	for _, tt := range tests {
-		t.Run(tt.input, func(t *testing.T) {
-			if got := titleCase(tt.input); got != tt.want {
-				t.Errorf("titleCase(%q) = %q, want %q", tt.input, got, tt.want)
-			}
-		})
-	}
-}
-
 // Helper function to create a test OpenAPI spec
 func createTestSpec(t *testing.T, dir string) string {
 	spec := `{
@@ -249,3 +291,125 @@ func createTestSpec(t *testing.T, dir string) string {
 	}
 	return path
 }
+
+func TestParseConvertFlags(t *testing.T) {
+	config, err := parseConvertFlags([]string{"--to", "3.1", "in.yaml", "-o", "out.yaml"})
+	if err != nil {
+		t.Fatalf("parseConvertFlags() error = %v", err)
+	}
+	if config.To != "3.1" || config.InputSpec != "in.yaml" || config.OutputFile != "out.yaml" {
+		t.Errorf("Unexpected config: %+v", config)
+	}
+
+	if _, err := parseConvertFlags([]string{"in.yaml"}); err == nil {
+		t.Error("Expected error when --to is missing")
+	}
+
+	if got := detectOutputFormat("out.yml", nil); got != "yaml" {
+		t.Errorf("detectOutputFormat(out.yml) = %s, want yaml", got)
+	}
+	if got := detectOutputFormat("", []byte(`{"openapi": "3.0.0"}`)); got != "json" {
+		t.Errorf("detectOutputFormat(json input) = %s, want json", got)
+	}
+}
+
+func TestParseGraphFlags(t *testing.T) {
+	config, err := parseGraphFlags([]string{"-f", "graphml", "api.yaml", "--cluster", "tag", "-o", "graph.xml"})
+	if err != nil {
+		t.Fatalf("parseGraphFlags() error = %v", err)
+	}
+	if config.Format != "graphml" || config.Cluster != "tag" || config.InputSpec != "api.yaml" || config.OutputFile != "graph.xml" {
+		t.Errorf("Unexpected config: %+v", config)
+	}
+
+	config, err = parseGraphFlags([]string{"api.yaml"})
+	if err != nil {
+		t.Fatalf("parseGraphFlags() error = %v", err)
+	}
+	if config.Format != "dot" || config.Cluster != "category" || config.GroupBy != "path" {
+		t.Errorf("Expected dot, category and path defaults, got %+v", config)
+	}
+
+	config, err = parseGraphFlags([]string{"-f", "mermaid-er", "--focus", "users", "--hops", "2", "--max-nodes", "20", "--max-fields", "6", "api.yaml"})
+	if err != nil {
+		t.Fatalf("parseGraphFlags() error = %v", err)
+	}
+	if config.Format != "mermaid-er" || config.Focus != "users" || config.Hops != 2 || config.MaxNodes != 20 || config.MaxFields != 6 {
+		t.Errorf("Unexpected size controls: %+v", config)
+	}
+
+	if _, err := parseGraphFlags(nil); err == nil {
+		t.Error("Expected error without an input specification")
+	}
+}
+
+func TestRunGraph(t *testing.T) {
+	tmpDir := t.TempDir()
+	spec := createTestSpec(t, tmpDir)
+	output := filepath.Join(tmpDir, "graph.dot")
+
+	if err := runGraph([]string{"-o", output, spec}); err != nil {
+		t.Fatalf("runGraph() error = %v", err)
+	}
+	data, err := os.ReadFile(output)
+	if err != nil {
+		t.Fatalf("Failed to read graph: %v", err)
+	}
+	if !strings.HasPrefix(string(data), `digraph "Test" {`) {
+		t.Errorf("Expected a Graphviz digraph, got:\n%s", data)
+	}
+
+	if err := runGraph([]string{"-f", "svg", spec}); err == nil {
+		t.Error("Expected error for an unsupported graph format")
+	}
+	if err := runGraph([]string{"-f", "mermaid-class", "--focus", "missing", spec}); err == nil {
+		t.Error("Expected error for an unknown focus resource")
+	}
+}
+
+func TestParseGraphQueryFlags(t *testing.T) {
+	config, err := parseGraphQueryFlags([]string{"--from", "users", "api.yaml", "--to", "posts", "--cycles", "--top", "5", "-f", "json"})
+	if err != nil {
+		t.Fatalf("parseGraphQueryFlags() error = %v", err)
+	}
+	expected := apigodoc.GraphQuery{From: "users", To: "posts", Cycles: true, Degrees: 5}
+	if config.Query != expected || config.Format != "json" || config.InputSpec != "api.yaml" {
+		t.Errorf("Unexpected config: %+v", config)
+	}
+
+	config, err = parseGraphQueryFlags([]string{"--orphans", "api.yaml"})
+	if err != nil {
+		t.Fatalf("parseGraphQueryFlags() error = %v", err)
+	}
+	if config.Format != "text" || !config.Query.Orphans {
+		t.Errorf("Expected text output of orphans, got %+v", config)
+	}
+
+	if _, err := parseGraphQueryFlags([]string{"--orphans"}); err == nil {
+		t.Error("Expected error without an input specification")
+	}
+}
+
+func TestRunGraphQuery(t *testing.T) {
+	tmpDir := t.TempDir()
+	spec := createTestSpec(t, tmpDir)
+	output := filepath.Join(tmpDir, "query.json")
+
+	if err := runGraph([]string{"query", "--orphans", "-f", "json", "-o", output, spec}); err != nil {
+		t.Fatalf("runGraph() error = %v", err)
+	}
+	data, err := os.ReadFile(output)
+	if err != nil {
+		t.Fatalf("Failed to read query result: %v", err)
+	}
+	if !strings.Contains(string(data), `"orphans": [`) {
+		t.Errorf("Expected orphan resources, got:\n%s", data)
+	}
+
+	if err := runGraph([]string{"query", spec}); err == nil {
+		t.Error("Expected error without a query")
+	}
+	if err := runGraph([]string{"query", "--dependents", "missing", spec}); err == nil {
+		t.Error("Expected error for an unknown resource")
+	}
+}
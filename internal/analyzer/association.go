@@ -0,0 +1,217 @@
+package analyzer
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/orchard9/api-godoc/internal/parser"
+	"github.com/orchard9/api-godoc/pkg/models"
+)
+
+// maxAssociationAttributes is how many properties besides the two foreign
+// keys, the identifier and timestamps a join schema may carry (e.g. a role)
+const maxAssociationAttributes = 2
+
+// association is a many-to-many link between two resources through a join
+// resource or link path
+type association struct {
+	name       string    // join resource or path segment, e.g. members
+	sides      [2]string // associated resources
+	vias       []string  // vias of the chained relationships it replaces
+	operations []string  // endpoints that manage the association
+}
+
+// detectAssociations collapses join resources into many-to-many
+// relationships. A link path such as /groups/{groupId}/members/{userId}
+// whose last identifier refers to another resource, and a schema made of
+// two foreign keys such as userId and projectId, associate two resources
+// instead of forming two unrelated has_many chains.
+func (rd *RelationshipDetector) detectAssociations(resourceMap map[string]*models.Resource, spec *parser.OpenAPISpec) {
+	for _, a := range append(rd.pathAssociations(resourceMap, spec), rd.schemaAssociations(resourceMap, spec)...) {
+		rd.collapseAssociation(resourceMap, a)
+	}
+}
+
+// pathAssociations finds link paths of the form
+// /parent/{parentId}/join/{targetId} where the target is neither the parent
+// nor the join resource itself
+func (rd *RelationshipDetector) pathAssociations(resourceMap map[string]*models.Resource, spec *parser.OpenAPISpec) []association {
+	var associations []association
+	seen := make(map[string]bool)
+	for _, path := range sortedPaths(spec.Paths) {
+		segments := rd.extractPathSegments(path)
+		for i := 0; i+3 < len(segments); i++ {
+			parentSegment, parentParam, joinSegment, targetParam := segments[i], segments[i+1], segments[i+2], segments[i+3]
+			if parentSegment.IsParameter || !parentParam.IsParameter || joinSegment.IsParameter || !targetParam.IsParameter {
+				continue
+			}
+			matches := rd.foreignKeyRegex.FindStringSubmatch(targetParam.ParamName)
+			if len(matches) < 2 {
+				continue
+			}
+			parent := rd.findResource(resourceMap, parentSegment.Value)
+			target := rd.findResource(resourceMap, matches[1])
+			if parent == nil || target == nil || parent.Name == target.Name {
+				continue
+			}
+			name := joinSegment.Value
+			if join := rd.findResource(resourceMap, joinSegment.Value); join != nil {
+				if join.Name == target.Name {
+					continue // plain nesting such as /users/{userId}/posts/{postId}
+				}
+				name = join.Name
+			}
+
+			key := name + "|" + parent.Name + "|" + target.Name
+			if seen[key] {
+				continue
+			}
+			seen[key] = true
+
+			var values []string
+			for _, segment := range segments[:i+3] {
+				values = append(values, segment.Value)
+			}
+			collection := "/" + strings.Join(values, "/")
+			var operations []string
+			for _, p := range []string{collection, collection + "/" + targetParam.Value} {
+				for _, op := range pathItemOperations(spec.Paths[p]) {
+					operations = append(operations, op.method+" "+p)
+				}
+			}
+
+			associations = append(associations, association{
+				name:       name,
+				sides:      [2]string{parent.Name, target.Name},
+				vias:       []string{pathHierarchyVia, parentParam.ParamName, targetParam.ParamName},
+				operations: operations,
+			})
+		}
+	}
+	return associations
+}
+
+// schemaAssociations finds join schemas whose only references are foreign
+// keys to two other resources. Schemas with collections or that other
+// schemas refer to are entities in their own right.
+func (rd *RelationshipDetector) schemaAssociations(resourceMap map[string]*models.Resource, spec *parser.OpenAPISpec) []association {
+	if spec.Components == nil {
+		return nil
+	}
+
+	referenced := make(map[string]bool)
+	for _, schema := range spec.Components.Schemas {
+		for _, property := range schema.Properties {
+			if property.Type == "array" && property.Items != nil {
+				property = *property.Items
+			}
+			if ref := schemaRef(property); ref != "" {
+				referenced[rd.extractSchemaNameFromRef(ref)] = true
+			}
+		}
+	}
+
+	var associations []association
+	seen := make(map[string]bool)
+	for _, schemaName := range sortedKeys(spec.Components.Schemas) {
+		join := rd.findResource(resourceMap, rd.schemaToResourceName(schemaName))
+		if join == nil || seen[join.Name] || referenced[schemaName] {
+			continue
+		}
+
+		schema := spec.Components.Schemas[schemaName]
+		var keys, targets []string
+		attributes, collections := 0, false
+		for _, propertyName := range sortedKeys(schema.Properties) {
+			property := schema.Properties[propertyName]
+			if propertyName == "id" || isTimestampProperty(propertyName) {
+				continue
+			}
+			if property.Type == "array" {
+				collections = true
+				break
+			}
+			if target := rd.foreignKeyTarget(resourceMap, propertyName, property); target != nil && target.Name != join.Name {
+				keys = append(keys, propertyName)
+				if !slices.Contains(targets, target.Name) {
+					targets = append(targets, target.Name)
+				}
+				continue
+			}
+			attributes++
+		}
+		if collections || len(keys) != 2 || len(targets) != 2 || attributes > maxAssociationAttributes {
+			continue
+		}
+		seen[join.Name] = true
+
+		// List the join operations in path and method order
+		var paths, joined []string
+		for _, op := range join.Operations {
+			paths = append(paths, op.Path)
+			joined = append(joined, op.Method+" "+op.Path)
+		}
+		slices.Sort(paths)
+		var operations []string
+		for _, path := range slices.Compact(paths) {
+			for _, op := range pathItemOperations(spec.Paths[path]) {
+				if slices.Contains(joined, op.method+" "+path) {
+					operations = append(operations, op.method+" "+path)
+				}
+			}
+		}
+		associations = append(associations, association{
+			name:       join.Name,
+			sides:      [2]string{targets[0], targets[1]},
+			vias:       keys,
+			operations: operations,
+		})
+	}
+	return associations
+}
+
+// foreignKeyTarget returns the resource a property refers to by $ref or by
+// foreign key naming
+func (rd *RelationshipDetector) foreignKeyTarget(resourceMap map[string]*models.Resource, propertyName string, property parser.Schema) *models.Resource {
+	if ref := schemaRef(property); ref != "" {
+		return rd.findResource(resourceMap, rd.schemaToResourceName(rd.extractSchemaNameFromRef(ref)))
+	}
+	if matches := rd.foreignKeyRegex.FindStringSubmatch(propertyName); len(matches) > 1 {
+		return rd.findResource(resourceMap, matches[1])
+	}
+	return nil
+}
+
+// collapseAssociation replaces the relationships chained through a join
+// with a many-to-many relationship in each direction that keeps their
+// evidence
+func (rd *RelationshipDetector) collapseAssociation(resourceMap map[string]*models.Resource, a association) {
+	involved := []string{a.sides[0], a.sides[1], a.name}
+	var evidence []models.Evidence
+	for _, name := range involved {
+		resource := rd.findResource(resourceMap, name)
+		if resource == nil || resource.Name != name {
+			continue
+		}
+		resource.Relationships = slices.DeleteFunc(resource.Relationships, func(rel models.Relationship) bool {
+			chained := slices.Contains(involved, rel.Resource) && slices.Contains(a.vias, rel.Via)
+			if chained {
+				evidence = append(evidence, rel.Evidence...)
+			}
+			return chained
+		})
+	}
+
+	for _, pair := range [][2]string{a.sides, {a.sides[1], a.sides[0]}} {
+		if rel := rd.addRelationship(resourceMap, pair[0], pair[1], "associated_with", a.name, models.CardinalityManyToMany, false, evidence...); rel != nil {
+			rel.Association = a.name
+			rel.Operations = a.operations
+		}
+	}
+}
+
+// isTimestampProperty reports whether a property records when something
+// happened, e.g. createdAt or updated_at
+func isTimestampProperty(name string) bool {
+	return strings.HasSuffix(name, "At") || strings.HasSuffix(strings.ToLower(name), "_at")
+}
@@ -0,0 +1,329 @@
+package analyzer
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/orchard9/api-godoc/internal/config"
+	"github.com/orchard9/api-godoc/internal/parser"
+	"github.com/orchard9/api-godoc/pkg/models"
+)
+
+// Binding evidence sources
+const (
+	BindingResponse = "response"  // returned by a get, create or update operation
+	BindingListItem = "list-item" // item of a list response
+	BindingRequest  = "request"   // accepted by a create or update operation
+	BindingName     = "name"      // component name matches the resource name
+)
+
+// Evidence weights: what an API returns for an item defines the resource
+// better than what it accepts or what a schema happens to be called
+var bindingWeights = map[string]int{
+	BindingResponse: 3,
+	BindingListItem: 3,
+	BindingRequest:  1,
+	BindingName:     2,
+}
+
+// inlineSchema names bindings to schemas without a component name
+const inlineSchema = "inline"
+
+// envelopeNames are properties that wrap a single item, e.g. {"data": {...}}
+var envelopeNames = map[string]bool{"data": true, "item": true, "result": true, "resource": true}
+
+// responseMetadataNames are response properties reported next to the resource
+var responseMetadataNames = map[string]bool{"status": true, "message": true, "success": true, "warnings": true}
+
+// SchemaBinder binds resources to the schemas that represent them
+type SchemaBinder struct {
+	names *NameNormalizer
+}
+
+// NewSchemaBinder creates a new schema binder
+func NewSchemaBinder() *SchemaBinder {
+	return &SchemaBinder{names: NewNameNormalizer(config.NamingConfig{})}
+}
+
+// SetNameNormalizer changes how schema names are matched to resource names
+func (b *SchemaBinder) SetNameNormalizer(normalizer *NameNormalizer) {
+	if normalizer == nil {
+		normalizer = NewNameNormalizer(config.NamingConfig{})
+	}
+	b.names = normalizer
+}
+
+// bindingCandidate is a schema that may represent a resource
+type bindingCandidate struct {
+	name     string
+	schema   *parser.Schema
+	score    int
+	returned bool // seen in a response rather than only in requests or by name
+	evidence []models.BindingEvidence
+}
+
+// Bind sets the schema binding of every resource from what its operations
+// return and accept, and returns the flattened representation schema of each
+// bound resource keyed by resource name
+func (b *SchemaBinder) Bind(resources []models.Resource, spec *parser.OpenAPISpec) map[string]*parser.Schema {
+	resolver := newRefResolver(spec)
+	schemas := make(map[string]*parser.Schema)
+
+	for i := range resources {
+		resource := &resources[i]
+		resource.Schema = nil
+
+		candidates := b.candidates(*resource, spec, resolver)
+		if len(candidates) == 0 {
+			continue
+		}
+
+		sort.SliceStable(candidates, func(i, j int) bool {
+			if candidates[i].score != candidates[j].score {
+				return candidates[i].score > candidates[j].score
+			}
+			return candidates[i].name < candidates[j].name
+		})
+		best := candidates[0]
+
+		binding := &models.SchemaBinding{Schema: best.name, Evidence: best.evidence}
+		if strings.HasPrefix(best.name, inlineSchema) {
+			binding.Schema = inlineSchema
+		}
+		// Other schemas returned for items of the same resource conflict
+		for _, other := range candidates[1:] {
+			if other.returned && !strings.HasPrefix(other.name, inlineSchema) {
+				binding.Conflicts = appendUnique(binding.Conflicts, other.name)
+			}
+		}
+		resource.Schema = binding
+
+		properties, required := collectProperties(best.schema, resolver)
+		flattened := &parser.Schema{Type: "object", Properties: properties}
+		for _, name := range sortedKeys(required) {
+			flattened.Required = append(flattened.Required, name)
+		}
+		schemas[resource.Name] = flattened
+	}
+
+	return schemas
+}
+
+// candidates collects the schemas a resource's operations return and accept,
+// plus components whose name matches the resource
+func (b *SchemaBinder) candidates(resource models.Resource, spec *parser.OpenAPISpec, resolver refResolver) []*bindingCandidate {
+	byName := make(map[string]*bindingCandidate)
+	var order []string
+	key := b.itemKey(resource.Name)
+
+	add := func(name string, schema *parser.Schema, evidence models.BindingEvidence) {
+		if name == "" {
+			name = inlineSchema + " " + evidence.Method + " " + evidence.Path
+		}
+		candidate := byName[name]
+		if candidate == nil {
+			candidate = &bindingCandidate{name: name, schema: schema}
+			byName[name] = candidate
+			order = append(order, name)
+		}
+		candidate.score += bindingWeights[evidence.Source]
+		candidate.returned = candidate.returned || evidence.Source == BindingResponse || evidence.Source == BindingListItem
+		candidate.evidence = append(candidate.evidence, evidence)
+	}
+
+	for _, op := range resource.Operations {
+		if !op.IsResourceOp {
+			continue
+		}
+		parsed := specOperation(spec, op.Method, op.Path)
+		if parsed == nil {
+			continue
+		}
+		evidence := func(source string) models.BindingEvidence {
+			return models.BindingEvidence{Source: source, Method: op.Method, Path: op.Path}
+		}
+
+		switch op.Kind {
+		case models.KindList:
+			if name, schema := b.unwrap(successSchema(parsed, resolver), key, true, resolver, 0); schema != nil {
+				add(name, schema, evidence(BindingListItem))
+			}
+		case models.KindGet, models.KindCreate, models.KindReplace, models.KindUpdate:
+			if name, schema := b.unwrap(successSchema(parsed, resolver), key, false, resolver, 0); schema != nil {
+				add(name, schema, evidence(BindingResponse))
+			}
+			if op.Kind == models.KindGet {
+				continue
+			}
+			if body := resolver.requestBody(parsed.RequestBody); body != nil {
+				if _, mediaType, ok := chooseContent(body.Content); ok {
+					if name, schema := b.unwrap(mediaType.Schema, key, false, resolver, 0); schema != nil {
+						add(name, schema, evidence(BindingRequest))
+					}
+				}
+			}
+		}
+	}
+
+	// Component names such as v1Feature or FeatureResponse support a binding
+	// and are the only evidence for resources without readable operations
+	if spec.Components != nil {
+		for _, name := range sortedKeys(spec.Components.Schemas) {
+			if b.names.Key(schemaResourceName(name)) != key {
+				continue
+			}
+			schema := spec.Components.Schemas[name]
+			if _, exists := byName[name]; !exists && len(byName) > 0 {
+				continue
+			}
+			add(name, &schema, models.BindingEvidence{Source: BindingName})
+		}
+	}
+
+	candidates := make([]*bindingCandidate, 0, len(order))
+	for _, name := range order {
+		candidates = append(candidates, byName[name])
+	}
+	return candidates
+}
+
+// itemKey returns the normalized singular name of a resource's items; nested
+// resources such as "features/tasks" are matched by their last part
+func (b *SchemaBinder) itemKey(resourceName string) string {
+	return b.names.Key(resourceName[strings.LastIndex(resourceName, nestedSeparator)+1:])
+}
+
+// unwrap finds the item schema inside a response or request schema. Lists are
+// unwrapped from arrays and envelopes such as {items: [...]} or
+// {features: [...]}; single items from envelopes such as {data: {...}} or
+// grpc-gateway's {feature: {...}}. It returns the component name of the item
+// ("" for inline schemas) and the schema, or nil when there is no item.
+func (b *SchemaBinder) unwrap(schema *parser.Schema, key string, list bool, resolver refResolver, depth int) (string, *parser.Schema) {
+	if schema == nil || depth > maxRefDepth {
+		return "", nil
+	}
+	name := ""
+	if schema.Ref != "" {
+		name = refName(schema.Ref)
+	}
+	resolved := resolver.schema(schema)
+	if resolved == nil {
+		return "", nil
+	}
+
+	if resolved.Type == "array" {
+		if !list {
+			return "", nil
+		}
+		return b.unwrap(resolved.Items, key, false, resolver, depth+1)
+	}
+
+	properties, _ := collectProperties(resolved, resolver)
+	if list {
+		var arrays []string
+		for _, prop := range sortedKeys(properties) {
+			p := properties[prop]
+			if items := resolver.schema(&p); items != nil && items.Type == "array" {
+				if listFieldNames[strings.ToLower(prop)] || b.names.Key(prop) == key {
+					return b.unwrap(items.Items, key, false, resolver, depth+1)
+				}
+				arrays = append(arrays, prop)
+			}
+		}
+		// A paginated response with a single array holds the items
+		if len(arrays) == 1 {
+			p := properties[arrays[0]]
+			return b.unwrap(resolver.schema(&p).Items, key, false, resolver, depth+1)
+		}
+		return "", nil
+	}
+
+	if len(properties) <= 3 {
+		for _, prop := range sortedKeys(properties) {
+			if !b.isEnvelope(prop, key) {
+				continue
+			}
+			p := properties[prop]
+			if inner, _ := collectProperties(&p, resolver); len(inner) > 0 {
+				return b.unwrap(&p, key, false, resolver, depth+1)
+			}
+		}
+	}
+
+	// grpc-gateway responses wrap one object, or one array of objects, in
+	// metadata such as {status, task} or {activities: [...], nextPageToken}
+	if inner := b.soleObjectProperty(properties, resolver); inner != nil {
+		return b.unwrap(inner, key, false, resolver, depth+1)
+	}
+
+	if len(properties) == 0 {
+		return "", nil
+	}
+	return name, resolved
+}
+
+// soleObjectProperty returns the object schema, or the object item schema of
+// an array, held by the only property that is not response metadata. It
+// returns nil when another property describes the resource itself.
+func (b *SchemaBinder) soleObjectProperty(properties map[string]parser.Schema, resolver refResolver) *parser.Schema {
+	var sole *parser.Schema
+	for _, prop := range sortedKeys(properties) {
+		if isResponseMetadata(prop) {
+			continue
+		}
+		p := properties[prop]
+		candidate := &p
+		if resolved := resolver.schema(candidate); resolved != nil && resolved.Type == "array" {
+			candidate = resolved.Items
+		}
+		if inner, _ := collectProperties(candidate, resolver); len(inner) == 0 || sole != nil {
+			return nil
+		}
+		sole = candidate
+	}
+	return sole
+}
+
+// isResponseMetadata reports whether a response property describes the
+// response rather than the resource, e.g. status or nextPageToken
+func isResponseMetadata(prop string) bool {
+	return responseMetadataNames[strings.ToLower(prop)] || isPaginationField(prop)
+}
+
+// isEnvelope reports whether a property wraps a single item of the resource:
+// a generic envelope such as "data", the item name itself, or a qualified
+// item name such as "auditRequest" for audits
+func (b *SchemaBinder) isEnvelope(prop, key string) bool {
+	if envelopeNames[strings.ToLower(prop)] {
+		return true
+	}
+	propKey := b.names.Key(prop)
+	return propKey == key || strings.HasPrefix(propKey, key+"-")
+}
+
+// specOperation looks up the parsed operation for a method and path
+func specOperation(spec *parser.OpenAPISpec, method, path string) *parser.Operation {
+	pathItem, ok := spec.Paths[path]
+	if !ok {
+		return nil
+	}
+	for _, mo := range pathItemOperations(pathItem) {
+		if mo.method == method {
+			return mo.operation
+		}
+	}
+	return nil
+}
+
+// successSchema returns the schema of the first 2xx response with content
+func successSchema(op *parser.Operation, resolver refResolver) *parser.Schema {
+	for _, code := range sortedKeys(op.Responses) {
+		if !strings.HasPrefix(code, "2") {
+			continue
+		}
+		if _, mediaType, ok := chooseContent(resolver.response(op.Responses[code]).Content); ok && mediaType.Schema != nil {
+			return mediaType.Schema
+		}
+	}
+	return nil
+}
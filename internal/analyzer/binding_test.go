@@ -0,0 +1,262 @@
+package analyzer
+
+import (
+	"reflect"
+	"sort"
+	"testing"
+
+	"github.com/orchard9/api-godoc/internal/parser"
+	"github.com/orchard9/api-godoc/pkg/models"
+)
+
+// jsonResponse returns a 200 response with a JSON body
+func jsonResponse(schema *parser.Schema) map[string]parser.Response {
+	return map[string]parser.Response{
+		"200": {Description: "OK", Content: parser.Content{"application/json": {Schema: schema}}},
+	}
+}
+
+// jsonBody returns a JSON request body
+func jsonBody(schema *parser.Schema) *parser.RequestBody {
+	return &parser.RequestBody{Content: parser.Content{"application/json": {Schema: schema}}}
+}
+
+// sortedCopy returns a sorted copy of a slice
+func sortedCopy(values []string) []string {
+	sorted := append([]string(nil), values...)
+	sort.Strings(sorted)
+	return sorted
+}
+
+func ref(name string) *parser.Schema {
+	return &parser.Schema{Ref: "#/components/schemas/" + name}
+}
+
+func bindingTestSpec() *parser.OpenAPISpec {
+	str := parser.Schema{Type: "string"}
+	return &parser.OpenAPISpec{
+		Paths: map[string]parser.PathItem{
+			// grpc-gateway style: item inside a {feature: {...}} envelope,
+			// list inside {features: [...], nextPageToken}
+			"/v1/features": {
+				Get:  &parser.Operation{Responses: jsonResponse(ref("v1ListFeaturesResponse"))},
+				Post: &parser.Operation{RequestBody: jsonBody(ref("v1CreateFeatureRequest")), Responses: jsonResponse(ref("v1CreateFeatureResponse"))},
+			},
+			"/v1/features/{id}": {
+				Get: &parser.Operation{Responses: jsonResponse(ref("v1GetFeatureResponse"))},
+			},
+			// Paginated list with {items: [...]} and a summary schema on get
+			"/projects": {
+				Get: &parser.Operation{Responses: jsonResponse(&parser.Schema{Type: "object", Properties: map[string]parser.Schema{
+					"items": {Type: "array", Items: ref("Project")},
+					"total": {Type: "integer"},
+				}})},
+			},
+			"/projects/{id}": {
+				Get: &parser.Operation{Responses: jsonResponse(ref("Project"))},
+				Put: &parser.Operation{Responses: jsonResponse(ref("ProjectSummary"))},
+			},
+			// Inline schemas only
+			"/notes/{id}": {
+				Get: &parser.Operation{Responses: jsonResponse(&parser.Schema{Type: "object", Properties: map[string]parser.Schema{
+					"id": str, "text": str,
+				}})},
+			},
+			// No readable operations; bound by component name
+			"/labels/{id}": {
+				Delete: &parser.Operation{},
+			},
+		},
+		Components: &parser.Components{
+			Schemas: map[string]parser.Schema{
+				"v1Feature": {Type: "object", Properties: map[string]parser.Schema{"id": str, "name": str}},
+				"v1ListFeaturesResponse": {Type: "object", Properties: map[string]parser.Schema{
+					"features":      {Type: "array", Items: ref("v1Feature")},
+					"nextPageToken": str,
+				}},
+				"v1GetFeatureResponse":    {Type: "object", Properties: map[string]parser.Schema{"feature": *ref("v1Feature")}},
+				"v1CreateFeatureResponse": {Type: "object", Properties: map[string]parser.Schema{"feature": *ref("v1Feature")}},
+				"v1CreateFeatureRequest":  {Type: "object", Properties: map[string]parser.Schema{"name": str}},
+				"Project":                 {Type: "object", Required: []string{"id"}, Properties: map[string]parser.Schema{"id": str, "name": str, "owner": str}},
+				"ProjectSummary":          {Type: "object", Properties: map[string]parser.Schema{"id": str, "name": str}},
+				"Label":                   {Type: "object", Properties: map[string]parser.Schema{"id": str, "color": str}},
+			},
+		},
+	}
+}
+
+func TestSchemaBinderBind(t *testing.T) {
+	spec := bindingTestSpec()
+	resources := NewResourceAnalyzer().ExtractResources(spec)
+	schemas := NewSchemaBinder().Bind(resources, spec)
+
+	tests := []struct {
+		resource   string
+		schema     string
+		sources    []string
+		conflicts  []string
+		properties []string
+	}{
+		{
+			resource:   "features",
+			schema:     "v1Feature",
+			sources:    []string{BindingListItem, BindingResponse, BindingResponse, BindingName},
+			properties: []string{"id", "name"},
+		},
+		{
+			resource:   "projects",
+			schema:     "Project",
+			sources:    []string{BindingListItem, BindingResponse, BindingName},
+			conflicts:  []string{"ProjectSummary"},
+			properties: []string{"id", "name", "owner"},
+		},
+		{
+			resource:   "notes",
+			schema:     inlineSchema,
+			sources:    []string{BindingResponse},
+			properties: []string{"id", "text"},
+		},
+		{
+			resource:   "labels",
+			schema:     "Label",
+			sources:    []string{BindingName},
+			properties: []string{"color", "id"},
+		},
+	}
+
+	bound := make(map[string]models.Resource)
+	for _, resource := range resources {
+		bound[resource.Name] = resource
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.resource, func(t *testing.T) {
+			resource, ok := bound[tt.resource]
+			if !ok {
+				t.Fatalf("Resource %s not found", tt.resource)
+			}
+			if resource.Schema == nil {
+				t.Fatalf("Expected %s to be bound to %s", tt.resource, tt.schema)
+			}
+			if resource.Schema.Schema != tt.schema {
+				t.Errorf("Expected schema %s, got %s", tt.schema, resource.Schema.Schema)
+			}
+
+			var sources []string
+			for _, evidence := range resource.Schema.Evidence {
+				sources = append(sources, evidence.Source)
+			}
+			if !reflect.DeepEqual(sortedCopy(sources), sortedCopy(tt.sources)) {
+				t.Errorf("Expected evidence %v, got %v", tt.sources, sources)
+			}
+			if !reflect.DeepEqual(resource.Schema.Conflicts, tt.conflicts) {
+				t.Errorf("Expected conflicts %v, got %v", tt.conflicts, resource.Schema.Conflicts)
+			}
+
+			schema := schemas[tt.resource]
+			if schema == nil {
+				t.Fatalf("Expected a flattened schema for %s", tt.resource)
+			}
+			if properties := sortedKeys(schema.Properties); !reflect.DeepEqual(properties, tt.properties) {
+				t.Errorf("Expected properties %v, got %v", tt.properties, properties)
+			}
+		})
+	}
+}
+
+func TestSchemaBinderRequestOnly(t *testing.T) {
+	spec := &parser.OpenAPISpec{
+		Paths: map[string]parser.PathItem{
+			"/answers": {
+				Post: &parser.Operation{RequestBody: jsonBody(ref("AnswerInput"))},
+			},
+		},
+		Components: &parser.Components{
+			Schemas: map[string]parser.Schema{
+				"AnswerInput": {Type: "object", Properties: map[string]parser.Schema{"text": {Type: "string"}}},
+			},
+		},
+	}
+
+	resources := NewResourceAnalyzer().ExtractResources(spec)
+	NewSchemaBinder().Bind(resources, spec)
+
+	if len(resources) != 1 || resources[0].Schema == nil {
+		t.Fatalf("Expected answers to be bound, got %+v", resources)
+	}
+	binding := resources[0].Schema
+	if binding.Schema != "AnswerInput" {
+		t.Errorf("Expected schema AnswerInput, got %s", binding.Schema)
+	}
+	expected := []models.BindingEvidence{{Source: BindingRequest, Method: "POST", Path: "/answers"}}
+	if !reflect.DeepEqual(binding.Evidence, expected) {
+		t.Errorf("Expected evidence %v, got %v", expected, binding.Evidence)
+	}
+	if len(binding.Conflicts) != 0 {
+		t.Errorf("Expected no conflicts for request-only schemas, got %v", binding.Conflicts)
+	}
+}
+
+func TestSchemaBinderMetadataEnvelopes(t *testing.T) {
+	str := parser.Schema{Type: "string"}
+	status := parser.Schema{Type: "object", Properties: map[string]parser.Schema{"code": {Type: "integer"}, "message": str}}
+	spec := &parser.OpenAPISpec{
+		Paths: map[string]parser.PathItem{
+			// grpc-gateway responses report a status next to the resource
+			"/v1/activities": {
+				Get: &parser.Operation{Responses: jsonResponse(ref("v1ListActivitiesResponse"))},
+			},
+			"/v1/tasks/{taskId}/confidence": {
+				Get: &parser.Operation{Responses: jsonResponse(ref("v1GetTaskConfidenceResponse"))},
+			},
+			"/v1/tasks/{taskId}/blocked-by": {
+				Get: &parser.Operation{Responses: jsonResponse(ref("v1GetBlockingTasksResponse"))},
+			},
+		},
+		Components: &parser.Components{
+			Schemas: map[string]parser.Schema{
+				"v1Activity": {Type: "object", Properties: map[string]parser.Schema{"id": str, "action": str}},
+				"v1Task":     {Type: "object", Properties: map[string]parser.Schema{"id": str, "title": str}},
+				"v1ListActivitiesResponse": {Type: "object", Properties: map[string]parser.Schema{
+					"activities":    {Type: "array", Items: ref("v1Activity")},
+					"nextPageToken": str,
+					"status":        status,
+				}},
+				"v1GetTaskConfidenceResponse": {Type: "object", Properties: map[string]parser.Schema{
+					"status": status,
+					"task":   *ref("v1Task"),
+				}},
+				"v1GetBlockingTasksResponse": {Type: "object", Properties: map[string]parser.Schema{
+					"status": status,
+					"tasks":  {Type: "array", Items: ref("v1Task")},
+				}},
+			},
+		},
+	}
+
+	resources := NewResourceAnalyzer().ExtractResources(spec)
+	NewSchemaBinder().Bind(resources, spec)
+
+	expected := map[string]string{
+		"activities": "v1Activity",
+		"confidence": "v1Task",
+		"blocked-by": "v1Task",
+	}
+	for _, resource := range resources {
+		want, ok := expected[resource.Name]
+		if !ok {
+			continue
+		}
+		delete(expected, resource.Name)
+		if resource.Schema == nil {
+			t.Errorf("Expected %s to be bound to %s", resource.Name, want)
+			continue
+		}
+		if resource.Schema.Schema != want {
+			t.Errorf("Expected %s to be bound to %s, got %s", resource.Name, want, resource.Schema.Schema)
+		}
+	}
+	for name := range expected {
+		t.Errorf("Resource %s not found", name)
+	}
+}
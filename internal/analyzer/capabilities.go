@@ -0,0 +1,153 @@
+package analyzer
+
+import (
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/orchard9/api-godoc/pkg/models"
+)
+
+// searchWords mark paths and custom methods that search a collection
+var searchWords = map[string]bool{"search": true, "query": true, "find": true, "lookup": true}
+
+// searchParameters are query parameters that turn a list into a search
+var searchParameters = map[string]bool{"q": true, "query": true, "search": true, "keyword": true, "keywords": true}
+
+// capabilityGaps are pairs of capabilities where having the first without the
+// second usually means the API is incomplete
+var capabilityGaps = []struct {
+	has, lacks string
+}{
+	{"create", "delete"},
+	{"delete", "create"},
+	{"create", "read"},
+	{"update", "read"},
+}
+
+// CapabilityMatrix builds the capability matrix for resources, sorted by name
+func CapabilityMatrix(resources []models.Resource) []models.Capabilities {
+	matrix := make([]models.Capabilities, 0, len(resources))
+	for _, resource := range resources {
+		matrix = append(matrix, ResourceCapabilities(resource))
+	}
+	sort.Slice(matrix, func(i, j int) bool {
+		return matrix[i].Resource < matrix[j].Resource
+	})
+	return matrix
+}
+
+// ResourceCapabilities derives the capabilities of a resource from the kinds of its operations.
+// List, get, create, update and delete only count on the resource's own
+// collection and item paths, unless it has none because it was grouped by
+// tag or operation ID rather than by path.
+func ResourceCapabilities(resource models.Resource) models.Capabilities {
+	caps := models.Capabilities{Resource: resource.Name}
+	ownPaths := slices.ContainsFunc(resource.Operations, func(op models.Operation) bool {
+		return ownsPath(resource, op.Path)
+	})
+
+	for _, op := range resource.Operations {
+		// Nested paths such as /users/{userId}/posts describe the child
+		if ownPaths && isCRUDKind(op.Kind) && !ownsPath(resource, op.Path) {
+			continue
+		}
+
+		// A list with a free-text query is both a list and a search
+		if isSearchOperation(op) {
+			caps.Search = true
+			if op.Kind == models.KindAction || op.Kind == models.KindBatch {
+				continue
+			}
+		}
+
+		switch op.Kind {
+		case models.KindList:
+			caps.List = true
+		case models.KindGet:
+			caps.Get = true
+		case models.KindCreate:
+			caps.Create = true
+		case models.KindReplace, models.KindUpdate:
+			caps.Update = true
+		case models.KindDelete:
+			caps.Delete = true
+		case models.KindAction, models.KindBatch:
+			caps.Actions = appendUnique(caps.Actions, actionName(op.Path))
+		}
+	}
+	sort.Strings(caps.Actions)
+
+	has := map[string]bool{
+		"list": caps.List, "get": caps.Get, "create": caps.Create,
+		"update": caps.Update, "delete": caps.Delete, "read": caps.List || caps.Get,
+	}
+	for _, gap := range capabilityGaps {
+		if has[gap.has] && !has[gap.lacks] {
+			caps.Gaps = append(caps.Gaps, "can "+gap.has+" but not "+gap.lacks)
+		}
+	}
+
+	return caps
+}
+
+// ownsPath reports whether a path is the collection or item path of a
+// resource: its last literal segment, without a custom method, is the
+// resource name or one of its aliases and at most one identifier follows
+func ownsPath(resource models.Resource, path string) bool {
+	segments := splitPath(path)
+	identifiers := 0
+	for i := len(segments) - 1; i >= 0; i-- {
+		literal, _, _ := strings.Cut(segments[i], ":")
+		if strings.HasPrefix(literal, "{") {
+			identifiers++
+			continue
+		}
+		if identifiers > 1 {
+			return false
+		}
+		for _, name := range append([]string{resource.Name}, resource.Aliases...) {
+			// Separated nested resources are named like "features/tasks"
+			if strings.EqualFold(name[strings.LastIndex(name, nestedSeparator)+1:], literal) {
+				return true
+			}
+		}
+		return false
+	}
+	return false
+}
+
+// isSearchOperation reports whether an operation searches rather than lists,
+// either through a search path or custom method or a free-text query parameter
+func isSearchOperation(op models.Operation) bool {
+	for _, word := range splitWords(actionName(op.Path)) {
+		if searchWords[word] {
+			return true
+		}
+	}
+	if op.Kind != models.KindList {
+		return false
+	}
+	for _, param := range op.Parameters {
+		if param.In == "query" && searchParameters[strings.ToLower(param.Name)] {
+			return true
+		}
+	}
+	return false
+}
+
+// actionName returns the custom method or last literal segment of a path,
+// e.g. "move" for /tasks/{id}:move and "activate" for /users/{id}/activate
+func actionName(path string) string {
+	segments := splitPath(path)
+	for i := len(segments) - 1; i >= 0; i-- {
+		literal := segments[i]
+		if j := strings.LastIndex(literal, ":"); j >= 0 {
+			return literal[j+1:]
+		}
+		if !strings.HasPrefix(literal, "{") {
+			return literal
+		}
+	}
+	return ""
+}
@@ -0,0 +1,112 @@
+package analyzer
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/orchard9/api-godoc/pkg/models"
+)
+
+func TestResourceCapabilities(t *testing.T) {
+	tests := []struct {
+		name     string
+		resource models.Resource
+		expected models.Capabilities
+	}{
+		{
+			name: "complete crud",
+			resource: models.Resource{Name: "users", Operations: []models.Operation{
+				{Method: "GET", Path: "/users", Kind: models.KindList},
+				{Method: "GET", Path: "/users/{id}", Kind: models.KindGet},
+				{Method: "POST", Path: "/users", Kind: models.KindCreate},
+				{Method: "PATCH", Path: "/users/{id}", Kind: models.KindUpdate},
+				{Method: "DELETE", Path: "/users/{id}", Kind: models.KindDelete},
+				{Method: "POST", Path: "/users/{id}:activate", Kind: models.KindAction},
+				{Method: "POST", Path: "/users:batchGet", Kind: models.KindBatch},
+			}},
+			expected: models.Capabilities{
+				Resource: "users", List: true, Get: true, Create: true, Update: true, Delete: true,
+				Actions: []string{"activate", "batchGet"},
+			},
+		},
+		{
+			name: "create without delete",
+			resource: models.Resource{Name: "orders", Operations: []models.Operation{
+				{Method: "POST", Path: "/orders", Kind: models.KindCreate},
+				{Method: "GET", Path: "/orders/{id}", Kind: models.KindGet},
+			}},
+			expected: models.Capabilities{
+				Resource: "orders", Get: true, Create: true,
+				Gaps: []string{"can create but not delete"},
+			},
+		},
+		{
+			name: "write only",
+			resource: models.Resource{Name: "answers", Operations: []models.Operation{
+				{Method: "POST", Path: "/answers", Kind: models.KindCreate},
+				{Method: "DELETE", Path: "/answers/{id}", Kind: models.KindDelete},
+				{Method: "PUT", Path: "/answers/{id}", Kind: models.KindReplace},
+			}},
+			expected: models.Capabilities{
+				Resource: "answers", Create: true, Update: true, Delete: true,
+				Gaps: []string{"can create but not read", "can update but not read"},
+			},
+		},
+		{
+			name: "nested child paths",
+			resource: models.Resource{Name: "users", Aliases: []string{"user"}, Operations: []models.Operation{
+				{Method: "GET", Path: "/users", Kind: models.KindList},
+				{Method: "GET", Path: "/user/{userId}", Kind: models.KindGet},
+				{Method: "POST", Path: "/users/{userId}/posts", Kind: models.KindCreate},
+				{Method: "GET", Path: "/users/{userId}/posts/{postId}", Kind: models.KindGet},
+				{Method: "DELETE", Path: "/users/{userId}/posts/{postId}", Kind: models.KindDelete},
+				{Method: "PATCH", Path: "/groups/{groupId}/users/{userId}", Kind: models.KindUpdate},
+			}},
+			expected: models.Capabilities{Resource: "users", List: true, Get: true, Update: true},
+		},
+		{
+			name: "nested member paths",
+			resource: models.Resource{Name: "groups", Operations: []models.Operation{
+				{Method: "GET", Path: "/groups/{groupId}", Kind: models.KindGet},
+				{Method: "PUT", Path: "/groups/{groupId}/members/{userId}", Kind: models.KindReplace},
+				{Method: "DELETE", Path: "/groups/{groupId}/members/{userId}", Kind: models.KindDelete},
+			}},
+			expected: models.Capabilities{Resource: "groups", Get: true},
+		},
+		{
+			name: "search",
+			resource: models.Resource{Name: "products", Operations: []models.Operation{
+				{Method: "GET", Path: "/products", Kind: models.KindList, Parameters: []models.Parameter{{Name: "q", In: "query"}}},
+				{Method: "POST", Path: "/products:search", Kind: models.KindAction},
+			}},
+			expected: models.Capabilities{Resource: "products", List: true, Search: true},
+		},
+		{
+			name: "search path",
+			resource: models.Resource{Name: "documents", Operations: []models.Operation{
+				{Method: "GET", Path: "/documents/search", Kind: models.KindList},
+			}},
+			expected: models.Capabilities{Resource: "documents", List: true, Search: true},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ResourceCapabilities(tt.resource); !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("Expected %+v, got %+v", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestCapabilityMatrixSorted(t *testing.T) {
+	matrix := CapabilityMatrix([]models.Resource{{Name: "users"}, {Name: "audits"}, {Name: "projects"}})
+
+	var names []string
+	for _, row := range matrix {
+		names = append(names, row.Resource)
+	}
+	if !reflect.DeepEqual(names, []string{"audits", "projects", "users"}) {
+		t.Errorf("Expected matrix sorted by resource, got %v", names)
+	}
+}
@@ -0,0 +1,219 @@
+package analyzer
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/orchard9/api-godoc/internal/config"
+	"github.com/orchard9/api-godoc/pkg/models"
+)
+
+// categoryWords map words in paths, tags and resource names to categories
+var categoryWords = map[string]string{
+	// admin
+	"admin": models.CategoryAdmin, "administration": models.CategoryAdmin, "management": models.CategoryAdmin,
+	"manage": models.CategoryAdmin, "backoffice": models.CategoryAdmin, "internal": models.CategoryAdmin,
+	"quota": models.CategoryAdmin,
+
+	// auth
+	"auth": models.CategoryAuth, "authentication": models.CategoryAuth, "authorization": models.CategoryAuth,
+	"oauth": models.CategoryAuth, "oauth2": models.CategoryAuth, "oidc": models.CategoryAuth, "sso": models.CategoryAuth,
+	"saml": models.CategoryAuth, "login": models.CategoryAuth, "logout": models.CategoryAuth, "signin": models.CategoryAuth,
+	"signup": models.CategoryAuth, "token": models.CategoryAuth, "session": models.CategoryAuth,
+	"password": models.CategoryAuth, "mfa": models.CategoryAuth, "otp": models.CategoryAuth, "credential": models.CategoryAuth,
+	"permission": models.CategoryAuth, "role": models.CategoryAuth, "key": models.CategoryAuth,
+
+	// ops
+	"health": models.CategoryOps, "healthz": models.CategoryOps, "status": models.CategoryOps, "ping": models.CategoryOps,
+	"metrics": models.CategoryOps, "metric": models.CategoryOps, "ready": models.CategoryOps, "readiness": models.CategoryOps,
+	"liveness": models.CategoryOps, "debug": models.CategoryOps, "diagnostics": models.CategoryOps,
+	"monitoring": models.CategoryOps, "version": models.CategoryOps,
+
+	// integration
+	"webhook": models.CategoryIntegration, "hook": models.CategoryIntegration, "callback": models.CategoryIntegration,
+	"integration": models.CategoryIntegration, "connector": models.CategoryIntegration,
+	"slack": models.CategoryIntegration, "github": models.CategoryIntegration, "stripe": models.CategoryIntegration,
+
+	// utility
+	"search": models.CategoryUtility, "upload": models.CategoryUtility, "download": models.CategoryUtility,
+	"import": models.CategoryUtility, "export": models.CategoryUtility, "lookup": models.CategoryUtility,
+	"util": models.CategoryUtility, "utility": models.CategoryUtility, "tool": models.CategoryUtility,
+	"schema": models.CategoryUtility, "meta": models.CategoryUtility, "metadata": models.CategoryUtility,
+}
+
+// Signal weights: a path prefix is the strongest hint, the resource name and
+// tags come next, and words deeper in the path only nudge the result
+const (
+	prefixWeight = 3
+	nameWeight   = 2
+	tagWeight    = 2
+	scopeWeight  = 2
+	pathWeight   = 1
+)
+
+// categoryPriority breaks ties between equally scored categories
+var categoryPriority = []string{
+	models.CategoryAuth, models.CategoryAdmin, models.CategoryOps,
+	models.CategoryIntegration, models.CategoryUtility,
+}
+
+// CategoryClassifier sorts resources into categories
+type CategoryClassifier struct {
+	rules     []resourceRule
+	inflector *Inflector
+}
+
+// resourceRule is a compiled user rule that matches resources by name, path
+// prefix or tag, such as a config.CategoryRule
+type resourceRule struct {
+	name      string
+	resources []*regexp.Regexp
+	paths     []string
+	tags      map[string]bool
+}
+
+// newResourceRule compiles a rule; resource patterns match whole names
+func newResourceRule(name string, resources, paths, tags []string) (resourceRule, error) {
+	rule := resourceRule{name: name, paths: paths, tags: make(map[string]bool)}
+	for _, pattern := range resources {
+		re, err := regexp.Compile("^(?:" + pattern + ")$")
+		if err != nil {
+			return resourceRule{}, fmt.Errorf("invalid resource pattern %q: %w", pattern, err)
+		}
+		rule.resources = append(rule.resources, re)
+	}
+	for _, tag := range tags {
+		rule.tags[strings.ToLower(tag)] = true
+	}
+	return rule, nil
+}
+
+// NewCategoryClassifier creates a classifier. User rules are tried in order
+// before the built-in heuristics.
+func NewCategoryClassifier(rules []config.CategoryRule) (*CategoryClassifier, error) {
+	classifier := &CategoryClassifier{inflector: NewInflector()}
+
+	for _, rule := range rules {
+		if rule.Category == "" {
+			return nil, fmt.Errorf("category rule is missing a category")
+		}
+		compiled, err := newResourceRule(rule.Category, rule.Resources, rule.Paths, rule.Tags)
+		if err != nil {
+			return nil, fmt.Errorf("category %s: %w", rule.Category, err)
+		}
+		classifier.rules = append(classifier.rules, compiled)
+	}
+
+	return classifier, nil
+}
+
+// Classify sets the category of every resource
+func (c *CategoryClassifier) Classify(resources []models.Resource) {
+	for i := range resources {
+		resources[i].Category = c.category(resources[i])
+	}
+}
+
+// category returns the category of a single resource
+func (c *CategoryClassifier) category(resource models.Resource) string {
+	for _, rule := range c.rules {
+		if rule.matches(resource) {
+			return rule.name
+		}
+	}
+
+	scores := make(map[string]int)
+	vote := func(words []string, weight int) {
+		for _, word := range words {
+			if category, ok := categoryWords[c.inflector.Singular(word)]; ok {
+				scores[category] += weight
+			}
+		}
+	}
+
+	vote(splitWords(resource.Name), nameWeight)
+	for _, op := range resource.Operations {
+		segments := splitPath(op.Path)
+		for i, segment := range segments {
+			if strings.HasPrefix(segment, "{") {
+				continue
+			}
+			weight := pathWeight
+			if isPrefixSegment(segments[:i]) {
+				weight = prefixWeight
+			}
+			vote(splitWords(segment), weight)
+		}
+		for _, tag := range op.Tags {
+			vote(splitWords(trimServiceSuffix(tag)), tagWeight)
+		}
+		for _, security := range op.Security {
+			if hasAdminScope(security) {
+				scores[models.CategoryAdmin] += scopeWeight
+			}
+		}
+	}
+
+	best, bestScore := models.CategoryCore, 0
+	for _, category := range categoryPriority {
+		if scores[category] > bestScore {
+			best, bestScore = category, scores[category]
+		}
+	}
+	// A category needs more than a passing mention deep in one path or
+	// across many operations
+	if bestScore < nameWeight || bestScore*2 < len(resource.Operations) {
+		return models.CategoryCore
+	}
+	return best
+}
+
+// isPrefixSegment reports whether the segments before a position are only API
+// prefixes such as /api/v1, so the position is the first meaningful segment
+func isPrefixSegment(segments []string) bool {
+	for _, segment := range segments {
+		if !versionSegmentRegex.MatchString(segment) && !reservedSegments[strings.ToLower(segment)] {
+			return false
+		}
+	}
+	return true
+}
+
+// hasAdminScope reports whether a security label such as "oauth (admin, read)" requires an admin scope
+func hasAdminScope(security string) bool {
+	open := strings.Index(security, "(")
+	if open < 0 {
+		return false
+	}
+	for _, scope := range strings.Split(strings.Trim(security[open:], "()"), ",") {
+		for _, word := range splitWords(strings.TrimSpace(scope)) {
+			if word == "admin" || word == "manage" {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// matches reports whether a user rule applies to a resource
+func (r resourceRule) matches(resource models.Resource) bool {
+	for _, re := range r.resources {
+		if re.MatchString(resource.Name) {
+			return true
+		}
+	}
+	for _, op := range resource.Operations {
+		for _, prefix := range r.paths {
+			if op.Path == prefix || strings.HasPrefix(op.Path, strings.TrimSuffix(prefix, "/")+"/") {
+				return true
+			}
+		}
+		for _, tag := range op.Tags {
+			if r.tags[strings.ToLower(tag)] {
+				return true
+			}
+		}
+	}
+	return false
+}
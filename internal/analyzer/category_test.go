@@ -0,0 +1,108 @@
+package analyzer
+
+import (
+	"testing"
+
+	"github.com/orchard9/api-godoc/internal/config"
+	"github.com/orchard9/api-godoc/pkg/models"
+)
+
+func categoryTestResource(name string, paths ...string) models.Resource {
+	resource := models.Resource{Name: name}
+	for _, path := range paths {
+		resource.Operations = append(resource.Operations, models.Operation{Method: "GET", Path: path})
+	}
+	return resource
+}
+
+func TestCategoryClassifier(t *testing.T) {
+	classifier, err := NewCategoryClassifier(nil)
+	if err != nil {
+		t.Fatalf("NewCategoryClassifier() error = %v", err)
+	}
+
+	withTags := categoryTestResource("accounts", "/accounts", "/accounts/{id}")
+	for i := range withTags.Operations {
+		withTags.Operations[i].Tags = []string{"AdminService"}
+	}
+	withScopes := categoryTestResource("quotas-reset", "/limits/{id}:reset")
+	withScopes.Operations[0].Security = []string{"oauth (admin.write)"}
+
+	tests := []struct {
+		name     string
+		resource models.Resource
+		expected string
+	}{
+		{"core resource", categoryTestResource("users", "/api/v1/users", "/api/v1/users/{id}"), models.CategoryCore},
+		{"admin prefix", categoryTestResource("users", "/admin/users", "/admin/users/{id}"), models.CategoryAdmin},
+		{"versioned admin prefix", categoryTestResource("users", "/api/v2/admin/users"), models.CategoryAdmin},
+		{"auth path", categoryTestResource("login", "/auth/login"), models.CategoryAuth},
+		{"auth name", categoryTestResource("api-keys", "/api-keys", "/api-keys/{id}"), models.CategoryAuth},
+		{"ops", categoryTestResource("watch", "/health/watch"), models.CategoryOps},
+		{"integration", categoryTestResource("webhooks", "/webhooks", "/webhooks/{id}"), models.CategoryIntegration},
+		{"utility", categoryTestResource("uploads", "/uploads"), models.CategoryUtility},
+		{"tags", withTags, models.CategoryAdmin},
+		{"admin scope", withScopes, models.CategoryAdmin},
+		{"deep mention", categoryTestResource("analysis", "/documents/analysis/{id}/status"), models.CategoryCore},
+		{"minority mention", categoryTestResource("tasks", "/tasks", "/tasks/{id}", "/tasks/{id}/move", "/tasks/{id}/status", "/tasks/{id}/blockers"), models.CategoryCore},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resources := []models.Resource{tt.resource}
+			classifier.Classify(resources)
+			if resources[0].Category != tt.expected {
+				t.Errorf("Expected category %q, got %q", tt.expected, resources[0].Category)
+			}
+		})
+	}
+}
+
+func TestCategoryClassifierRules(t *testing.T) {
+	classifier, err := NewCategoryClassifier([]config.CategoryRule{
+		{Category: "billing", Resources: []string{"invoices|payments"}},
+		{Category: models.CategoryAdmin, Paths: []string{"/internal"}},
+		{Category: models.CategoryIntegration, Tags: []string{"Partners"}},
+	})
+	if err != nil {
+		t.Fatalf("NewCategoryClassifier() error = %v", err)
+	}
+
+	tagged := categoryTestResource("orders", "/orders")
+	tagged.Operations[0].Tags = []string{"partners"}
+
+	resources := []models.Resource{
+		categoryTestResource("invoices", "/invoices"),
+		categoryTestResource("invoice-items", "/invoice-items"),
+		categoryTestResource("jobs", "/internal/jobs"),
+		categoryTestResource("internships", "/internships"),
+		tagged,
+		categoryTestResource("login", "/auth/login"),
+	}
+	classifier.Classify(resources)
+
+	expected := []string{"billing", models.CategoryCore, models.CategoryAdmin, models.CategoryCore, models.CategoryIntegration, models.CategoryAuth}
+	for i, resource := range resources {
+		if resource.Category != expected[i] {
+			t.Errorf("Expected %s to be %q, got %q", resource.Name, expected[i], resource.Category)
+		}
+	}
+}
+
+func TestNewCategoryClassifierValidation(t *testing.T) {
+	tests := []struct {
+		name  string
+		rules []config.CategoryRule
+	}{
+		{"missing category", []config.CategoryRule{{Resources: []string{"users"}}}},
+		{"invalid pattern", []config.CategoryRule{{Category: "core", Resources: []string{"("}}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := NewCategoryClassifier(tt.rules); err == nil {
+				t.Error("Expected an error")
+			}
+		})
+	}
+}
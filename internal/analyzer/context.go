@@ -0,0 +1,508 @@
+package analyzer
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/orchard9/api-godoc/internal/config"
+	"github.com/orchard9/api-godoc/pkg/models"
+)
+
+// Affinity weights between two resources: relationships and nesting tie
+// resources together most, shared tags and path prefixes group the rest
+var relationshipWeights = map[string]float64{"strong": 3, "medium": 2, "weak": 1}
+
+const (
+	parentWeight       = 3.0
+	sharedTagWeight    = 2.0
+	sharedPrefixWeight = 1.0
+)
+
+// minContextResources is the smallest API that is split into contexts when
+// no contexts are pinned in config
+const minContextResources = 10
+
+// otherContext collects resources that are not related to any other resource
+const otherContext = "other"
+
+// ContextClusterer groups resources into bounded contexts
+type ContextClusterer struct {
+	rules []resourceRule
+}
+
+// NewContextClusterer creates a clusterer. Resources matching a rule form its
+// context; the remaining resources are clustered among themselves.
+func NewContextClusterer(rules []config.ContextRule) (*ContextClusterer, error) {
+	clusterer := &ContextClusterer{}
+
+	for _, rule := range rules {
+		if rule.Context == "" {
+			return nil, fmt.Errorf("context rule is missing a context")
+		}
+		compiled, err := newResourceRule(rule.Context, rule.Resources, rule.Paths, rule.Tags)
+		if err != nil {
+			return nil, fmt.Errorf("context %s: %w", rule.Context, err)
+		}
+		clusterer.rules = append(clusterer.rules, compiled)
+	}
+
+	return clusterer, nil
+}
+
+// Cluster sets the context of every resource and returns the contexts:
+// pinned contexts in config order, then detected contexts by size. Small
+// APIs without pinned contexts are not clustered.
+func (c *ContextClusterer) Cluster(resources []models.Resource) []models.BoundedContext {
+	for i := range resources {
+		resources[i].Context = ""
+	}
+	if len(resources) == 0 || (len(resources) < minContextResources && len(c.rules) == 0) {
+		return nil
+	}
+
+	// Nodes are resources in name order so that clustering is deterministic
+	order := make([]int, len(resources))
+	for i := range order {
+		order[i] = i
+	}
+	sort.Slice(order, func(a, b int) bool { return resources[order[a]].Name < resources[order[b]].Name })
+	nodes := make([]models.Resource, len(order))
+	for node, i := range order {
+		nodes[node] = resources[i]
+	}
+
+	graph := affinityGraph(nodes)
+
+	// Pinned resources start in their rule's community, never move and
+	// admit no other resources
+	initial := make([]int, len(nodes))
+	fixed := make([]bool, len(nodes))
+	pinnedTo := make([]int, len(nodes))
+	ruleCommunity := make(map[int]int)
+	for node, resource := range nodes {
+		initial[node], pinnedTo[node] = node, -1
+		for r, rule := range c.rules {
+			if !rule.matches(resource) {
+				continue
+			}
+			if _, ok := ruleCommunity[r]; !ok {
+				ruleCommunity[r] = node
+			}
+			initial[node], fixed[node], pinnedTo[node] = ruleCommunity[r], true, r
+			break
+		}
+	}
+	membership := louvain(graph, initial, fixed)
+
+	// Collect communities, folding unpinned singletons into their closest
+	// neighbor's community or the catch-all context
+	members := make(map[int][]int)
+	for node, community := range membership {
+		members[community] = append(members[community], node)
+	}
+	for _, community := range sortedCommunities(members) {
+		if len(members[community]) != 1 || fixed[members[community][0]] {
+			continue
+		}
+		node := members[community][0]
+		target, best := -1, 0.0
+		for _, neighbor := range sortedNeighbors(graph[node]) {
+			if w := graph[node][neighbor]; w > best && membership[neighbor] != community && !fixed[neighbor] {
+				target, best = membership[neighbor], w
+			}
+		}
+		if target >= 0 {
+			membership[node] = target
+			members[target] = append(members[target], node)
+			delete(members, community)
+		}
+	}
+
+	// Name contexts and assign resources
+	var contexts []models.BoundedContext
+	used := make(map[string]bool)
+	for _, community := range sortedCommunities(members) {
+		group := members[community]
+		sort.Ints(group)
+
+		pin := -1
+		for _, node := range group {
+			if pinnedTo[node] >= 0 {
+				pin = pinnedTo[node]
+				break
+			}
+		}
+
+		context := models.BoundedContext{}
+		switch {
+		case pin >= 0:
+			context.Name, context.Pinned = c.rules[pin].name, true
+		case len(group) == 1 && len(graph[group[0]]) == 0:
+			context.Name = otherContext
+		default:
+			context.Name = contextName(nodes, group, graph)
+		}
+		if !context.Pinned {
+			base := context.Name
+			for n := 2; used[context.Name]; n++ {
+				context.Name = fmt.Sprintf("%s-%d", base, n)
+			}
+		}
+		used[context.Name] = true
+
+		for _, node := range group {
+			resources[order[node]].Context = context.Name
+			context.Resources = append(context.Resources, nodes[node].Name)
+		}
+		contexts = mergeContext(contexts, context)
+	}
+
+	sortContexts(contexts, c.rules)
+	linkContexts(contexts, resources)
+	return contexts
+}
+
+// affinityGraph builds the weighted, symmetric affinity between resources
+func affinityGraph(nodes []models.Resource) []map[int]float64 {
+	graph := make([]map[int]float64, len(nodes))
+	for i := range graph {
+		graph[i] = make(map[int]float64)
+	}
+	add := func(a, b int, weight float64) {
+		if a != b {
+			graph[a][b] += weight
+			graph[b][a] += weight
+		}
+	}
+
+	index := make(map[string]int)
+	for node, resource := range nodes {
+		index[resource.Name] = node
+	}
+	for node, resource := range nodes {
+		for _, rel := range resource.Relationships {
+			if other, ok := index[rel.Resource]; ok {
+				weight, known := relationshipWeights[rel.Strength]
+				if !known {
+					weight = 1
+				}
+				add(node, other, weight)
+			}
+		}
+		if parent, ok := index[resource.Parent]; ok {
+			add(node, parent, parentWeight)
+		}
+	}
+
+	// Tags and prefixes shared by most of the API say nothing about context
+	share := func(keys func(models.Resource) []string, weight float64) {
+		groups := make(map[string][]int)
+		for node, resource := range nodes {
+			for _, key := range keys(resource) {
+				groups[key] = append(groups[key], node)
+			}
+		}
+		for _, key := range sortedKeys(groups) {
+			group := groups[key]
+			if len(group) < 2 || len(group)*2 > len(nodes) {
+				continue
+			}
+			for a := 0; a < len(group); a++ {
+				for b := a + 1; b < len(group); b++ {
+					add(group[a], group[b], weight)
+				}
+			}
+		}
+	}
+	share(resourceTags, sharedTagWeight)
+	share(resourcePrefixes, sharedPrefixWeight)
+
+	return graph
+}
+
+// resourceTags returns the normalized operation tags of a resource
+func resourceTags(resource models.Resource) []string {
+	var tags []string
+	for _, op := range resource.Operations {
+		for _, tag := range op.Tags {
+			if name := normalizeGroupName(trimServiceSuffix(tag)); name != "" {
+				tags = appendUnique(tags, name)
+			}
+		}
+	}
+	return tags
+}
+
+// resourcePrefixes returns the first meaningful path segment of each
+// operation, after API prefixes such as /api/v1
+func resourcePrefixes(resource models.Resource) []string {
+	var prefixes []string
+	for _, op := range resource.Operations {
+		segments := splitPath(op.Path)
+		for i, segment := range segments {
+			if isPrefixSegment(segments[:i+1]) {
+				continue
+			}
+			if !strings.HasPrefix(segment, "{") {
+				prefixes = appendUnique(prefixes, strings.ToLower(strings.SplitN(segment, ":", 2)[0]))
+			}
+			break
+		}
+	}
+	return prefixes
+}
+
+// louvain clusters a weighted graph by greedily maximizing modularity, moving
+// nodes between communities and then collapsing communities into nodes until
+// nothing moves. Fixed nodes keep their initial community, which no other
+// node may join.
+func louvain(graph []map[int]float64, initial []int, fixed []bool) []int {
+	membership := make([]int, len(graph))
+	for i := range membership {
+		membership[i] = i
+	}
+	community := append([]int(nil), initial...)
+
+	for level := 0; level < len(membership); level++ {
+		moved := moveNodes(graph, community, fixed)
+
+		// Renumber communities and map original nodes onto them
+		renumber := make(map[int]int)
+		for _, c := range community {
+			if _, ok := renumber[c]; !ok {
+				renumber[c] = len(renumber)
+			}
+		}
+		for i := range membership {
+			membership[i] = renumber[community[membership[i]]]
+		}
+		if !moved || len(renumber) == len(graph) {
+			break
+		}
+
+		// Collapse each community into a node with the summed weights
+		collapsed := make([]map[int]float64, len(renumber))
+		collapsedFixed := make([]bool, len(renumber))
+		for i := range collapsed {
+			collapsed[i] = make(map[int]float64)
+		}
+		for node, neighbors := range graph {
+			c := renumber[community[node]]
+			collapsedFixed[c] = collapsedFixed[c] || fixed[node]
+			for neighbor, weight := range neighbors {
+				collapsed[c][renumber[community[neighbor]]] += weight
+			}
+		}
+		graph, fixed = collapsed, collapsedFixed
+		community = make([]int, len(graph))
+		for i := range community {
+			community[i] = i
+		}
+	}
+
+	return membership
+}
+
+// moveNodes moves each unfixed node to the neighboring community with the
+// largest modularity gain until no move improves it, reporting whether any
+// node moved
+func moveNodes(graph []map[int]float64, community []int, fixed []bool) bool {
+	degree := make([]float64, len(graph))
+	communityDegree := make(map[int]float64)
+	total := 0.0
+	for node, neighbors := range graph {
+		for _, weight := range neighbors {
+			degree[node] += weight
+		}
+		communityDegree[community[node]] += degree[node]
+		total += degree[node]
+	}
+	if total == 0 {
+		return false
+	}
+
+	closed := make(map[int]bool)
+	for node := range graph {
+		if fixed[node] {
+			closed[community[node]] = true
+		}
+	}
+
+	moved := false
+	for improved := true; improved; {
+		improved = false
+		for node := range graph {
+			if fixed[node] {
+				continue
+			}
+			own := community[node]
+			links := make(map[int]float64)
+			for neighbor, weight := range graph[node] {
+				if neighbor != node {
+					links[community[neighbor]] += weight
+				}
+			}
+
+			communityDegree[own] -= degree[node]
+			gain := func(c int) float64 {
+				return links[c] - degree[node]*communityDegree[c]/total
+			}
+			best, bestGain := own, gain(own)
+			for _, c := range sortedNeighbors(links) {
+				if closed[c] {
+					continue
+				}
+				if g := gain(c); g > bestGain+1e-9 {
+					best, bestGain = c, g
+				}
+			}
+			communityDegree[best] += degree[node]
+
+			if best != own {
+				community[node] = best
+				improved, moved = true, true
+			}
+		}
+	}
+	return moved
+}
+
+// contextName names a detected context after the tag most of its resources
+// share, or else its most connected resource
+func contextName(nodes []models.Resource, group []int, graph []map[int]float64) string {
+	counts := make(map[string]int)
+	for _, node := range group {
+		for _, tag := range resourceTags(nodes[node]) {
+			counts[tag]++
+		}
+	}
+	bestTag, bestCount := "", 0
+	for _, tag := range sortedKeys(counts) {
+		if counts[tag] > bestCount {
+			bestTag, bestCount = tag, counts[tag]
+		}
+	}
+	if len(group) > 1 && bestCount*2 >= len(group) {
+		return bestTag
+	}
+
+	best, bestDegree := group[0], -1.0
+	for _, node := range group {
+		degree := 0.0
+		for _, weight := range graph[node] {
+			degree += weight
+		}
+		if degree > bestDegree {
+			best, bestDegree = node, degree
+		}
+	}
+	return nodes[best].Name
+}
+
+// mergeContext adds a context, merging it into an existing one of the same
+// name such as the catch-all context
+func mergeContext(contexts []models.BoundedContext, context models.BoundedContext) []models.BoundedContext {
+	for i := range contexts {
+		if contexts[i].Name == context.Name {
+			contexts[i].Resources = append(contexts[i].Resources, context.Resources...)
+			sort.Strings(contexts[i].Resources)
+			return contexts
+		}
+	}
+	return append(contexts, context)
+}
+
+// sortContexts orders pinned contexts as configured, then detected contexts
+// by size and name, with the catch-all context last
+func sortContexts(contexts []models.BoundedContext, rules []resourceRule) {
+	rank := func(context models.BoundedContext) int {
+		if context.Pinned {
+			for i, rule := range rules {
+				if rule.name == context.Name {
+					return i
+				}
+			}
+		}
+		if context.Name == otherContext {
+			return len(rules) + 1
+		}
+		return len(rules)
+	}
+	sort.SliceStable(contexts, func(i, j int) bool {
+		ri, rj := rank(contexts[i]), rank(contexts[j])
+		if ri != rj {
+			return ri < rj
+		}
+		if len(contexts[i].Resources) != len(contexts[j].Resources) {
+			return len(contexts[i].Resources) > len(contexts[j].Resources)
+		}
+		return contexts[i].Name < contexts[j].Name
+	})
+}
+
+// linkContexts counts the relationships between resources of different contexts
+func linkContexts(contexts []models.BoundedContext, resources []models.Resource) {
+	contextOf := make(map[string]string)
+	for _, resource := range resources {
+		contextOf[resource.Name] = resource.Context
+	}
+
+	// Each related pair of resources counts once, whichever side declares it
+	counts := make(map[string]map[string]int)
+	seen := make(map[[2]string]bool)
+	for _, resource := range resources {
+		for _, rel := range resource.Relationships {
+			other, ok := contextOf[rel.Resource]
+			if !ok || other == resource.Context {
+				continue
+			}
+			pair := [2]string{resource.Name, rel.Resource}
+			if pair[0] > pair[1] {
+				pair[0], pair[1] = pair[1], pair[0]
+			}
+			if seen[pair] {
+				continue
+			}
+			seen[pair] = true
+			if counts[resource.Context] == nil {
+				counts[resource.Context] = make(map[string]int)
+			}
+			if counts[other] == nil {
+				counts[other] = make(map[string]int)
+			}
+			counts[resource.Context][other]++
+			counts[other][resource.Context]++
+		}
+	}
+
+	for i := range contexts {
+		linked := counts[contexts[i].Name]
+		for _, name := range sortedKeys(linked) {
+			contexts[i].Links = append(contexts[i].Links, models.ContextLink{Context: name, Relationships: linked[name]})
+		}
+		sort.SliceStable(contexts[i].Links, func(a, b int) bool {
+			return contexts[i].Links[a].Relationships > contexts[i].Links[b].Relationships
+		})
+	}
+}
+
+// sortedCommunities returns community ids in ascending order
+func sortedCommunities(members map[int][]int) []int {
+	communities := make([]int, 0, len(members))
+	for c := range members {
+		communities = append(communities, c)
+	}
+	sort.Ints(communities)
+	return communities
+}
+
+// sortedNeighbors returns the keys of a weight map in ascending order
+func sortedNeighbors(weights map[int]float64) []int {
+	keys := make([]int, 0, len(weights))
+	for k := range weights {
+		keys = append(keys, k)
+	}
+	sort.Ints(keys)
+	return keys
+}
@@ -0,0 +1,156 @@
+package analyzer
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/orchard9/api-godoc/internal/config"
+	"github.com/orchard9/api-godoc/pkg/models"
+)
+
+// contextTestResources returns a billing and a catalog cluster joined by one
+// weak relationship, plus an unrelated health resource
+func contextTestResources() []models.Resource {
+	resource := func(name, tag string, related ...string) models.Resource {
+		r := models.Resource{
+			Name:       name,
+			Operations: []models.Operation{{Method: "GET", Path: "/" + name, Tags: []string{tag}}},
+		}
+		for _, other := range related {
+			r.Relationships = append(r.Relationships, models.Relationship{Resource: other, Type: "references", Strength: "strong"})
+		}
+		return r
+	}
+
+	resources := []models.Resource{
+		resource("invoices", "BillingService", "payments", "subscriptions"),
+		resource("payments", "BillingService", "refunds"),
+		resource("refunds", "BillingService"),
+		resource("subscriptions", "BillingService", "coupons"),
+		resource("coupons", "BillingService"),
+		resource("products", "CatalogService", "categories", "prices", "variants"),
+		resource("categories", "CatalogService"),
+		resource("prices", "CatalogService", "variants"),
+		resource("variants", "CatalogService"),
+		resource("health", "Health"),
+	}
+	resources[0].Relationships = append(resources[0].Relationships, models.Relationship{Resource: "products", Type: "references", Strength: "weak"})
+	return resources
+}
+
+func TestContextClustererCluster(t *testing.T) {
+	clusterer, err := NewContextClusterer(nil)
+	if err != nil {
+		t.Fatalf("NewContextClusterer() error = %v", err)
+	}
+	resources := contextTestResources()
+	contexts := clusterer.Cluster(resources)
+
+	expected := []models.BoundedContext{
+		{
+			Name:      "billing",
+			Resources: []string{"coupons", "invoices", "payments", "refunds", "subscriptions"},
+			Links:     []models.ContextLink{{Context: "catalog", Relationships: 1}},
+		},
+		{
+			Name:      "catalog",
+			Resources: []string{"categories", "prices", "products", "variants"},
+			Links:     []models.ContextLink{{Context: "billing", Relationships: 1}},
+		},
+		{Name: "other", Resources: []string{"health"}},
+	}
+	if !reflect.DeepEqual(contexts, expected) {
+		t.Errorf("Expected contexts %+v, got %+v", expected, contexts)
+	}
+
+	for _, resource := range resources {
+		if resource.Context == "" {
+			t.Errorf("Expected %s to be assigned a context", resource.Name)
+		}
+	}
+}
+
+func TestContextClustererPinned(t *testing.T) {
+	clusterer, err := NewContextClusterer([]config.ContextRule{
+		{Context: "payments", Resources: []string{"payments|refunds"}},
+	})
+	if err != nil {
+		t.Fatalf("NewContextClusterer() error = %v", err)
+	}
+	contexts := clusterer.Cluster(contextTestResources())
+
+	if len(contexts) == 0 {
+		t.Fatal("Expected contexts")
+	}
+	pinned := contexts[0]
+	if pinned.Name != "payments" || !pinned.Pinned {
+		t.Errorf("Expected the pinned payments context first, got %+v", pinned)
+	}
+	if !reflect.DeepEqual(pinned.Resources, []string{"payments", "refunds"}) {
+		t.Errorf("Expected pinned resources [payments refunds], got %v", pinned.Resources)
+	}
+	for _, context := range contexts[1:] {
+		for _, name := range context.Resources {
+			if name == "payments" || name == "refunds" {
+				t.Errorf("Expected %s to stay pinned, found in %s", name, context.Name)
+			}
+		}
+	}
+}
+
+func TestContextClustererSmallAPI(t *testing.T) {
+	clusterer, err := NewContextClusterer(nil)
+	if err != nil {
+		t.Fatalf("NewContextClusterer() error = %v", err)
+	}
+	resources := contextTestResources()[:4]
+	if contexts := clusterer.Cluster(resources); contexts != nil {
+		t.Errorf("Expected small APIs not to be clustered, got %+v", contexts)
+	}
+	for _, resource := range resources {
+		if resource.Context != "" {
+			t.Errorf("Expected no context for %s, got %s", resource.Name, resource.Context)
+		}
+	}
+}
+
+func TestNewContextClustererInvalid(t *testing.T) {
+	tests := []struct {
+		name  string
+		rules []config.ContextRule
+	}{
+		{"missing context", []config.ContextRule{{Resources: []string{"users"}}}},
+		{"invalid pattern", []config.ContextRule{{Context: "identity", Resources: []string{"users("}}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := NewContextClusterer(tt.rules); err == nil {
+				t.Error("Expected an error")
+			}
+		})
+	}
+}
+
+func TestLouvain(t *testing.T) {
+	// Two triangles joined by a single edge
+	graph := make([]map[int]float64, 6)
+	for i := range graph {
+		graph[i] = make(map[int]float64)
+	}
+	for _, edge := range [][2]int{{0, 1}, {1, 2}, {0, 2}, {3, 4}, {4, 5}, {3, 5}, {2, 3}} {
+		graph[edge[0]][edge[1]], graph[edge[1]][edge[0]] = 1, 1
+	}
+	initial := []int{0, 1, 2, 3, 4, 5}
+
+	membership := louvain(graph, initial, make([]bool, 6))
+	if membership[0] != membership[1] || membership[1] != membership[2] {
+		t.Errorf("Expected the first triangle in one community, got %v", membership)
+	}
+	if membership[3] != membership[4] || membership[4] != membership[5] {
+		t.Errorf("Expected the second triangle in one community, got %v", membership)
+	}
+	if membership[0] == membership[3] {
+		t.Errorf("Expected two communities, got %v", membership)
+	}
+}
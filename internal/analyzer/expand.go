@@ -0,0 +1,173 @@
+package analyzer
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/orchard9/api-godoc/internal/parser"
+	"github.com/orchard9/api-godoc/pkg/models"
+)
+
+// expandParameters are query parameters that embed related resources in a
+// response: Stripe's expand[] and JSON:API's include
+var expandParameters = []string{"expand", "expand[]", "include"}
+
+// expandableRef returns the reference of a property that holds either an
+// identifier or the expanded object, e.g. anyOf: [string, $ref Customer]
+func expandableRef(schema parser.Schema) string {
+	variants := schema.AnyOf
+	if len(variants) == 0 {
+		variants = schema.OneOf
+	}
+	identifier, ref := false, ""
+	for _, variant := range variants {
+		switch {
+		case variant.Ref != "":
+			if ref == "" {
+				ref = variant.Ref
+			}
+		case variant.Type == "string" || variant.Type == "integer":
+			identifier = true
+		}
+	}
+	if !identifier {
+		return ""
+	}
+	return ref
+}
+
+// analyzeExpandableReference adds an expandable relationship for a property,
+// or an array of properties, that is either an identifier or an object
+func (rd *RelationshipDetector) analyzeExpandableReference(resourceMap map[string]*models.Resource, resourceName, propertyName string, property parser.Schema, required bool, evidence models.Evidence) {
+	many := property.Type == "array" && property.Items != nil
+	if many {
+		property = *property.Items
+	}
+	ref := expandableRef(property)
+	if ref == "" {
+		return
+	}
+	target := rd.findResource(resourceMap, rd.schemaToResourceName(rd.extractSchemaNameFromRef(ref)))
+	if target == nil || target.Name == resourceName {
+		return
+	}
+
+	var rel *models.Relationship
+	if many {
+		rel = rd.addRelationship(resourceMap, resourceName, target.Name, "has_many", propertyName, models.CardinalityOneToMany, false, evidence)
+		rd.addRelationship(resourceMap, target.Name, resourceName, "belongs_to", propertyName, models.CardinalityManyToOne, false, evidence)
+	} else {
+		rel = rd.addRelationship(resourceMap, resourceName, target.Name, "references", propertyName, models.CardinalityManyToOne, required, evidence)
+		rd.addRelationship(resourceMap, target.Name, resourceName, "referenced_by", propertyName, models.CardinalityOneToMany, false, evidence)
+	}
+	if rel != nil {
+		rel.Expandable = true
+	}
+}
+
+// detectExpansions records the operations with an expand or include
+// parameter on the relationships they can expand. Enumerated values name
+// the properties or resources to expand (customer, invoice.subscription);
+// without values the operation can expand every expandable reference of its
+// resource.
+func (rd *RelationshipDetector) detectExpansions(resourceMap map[string]*models.Resource, spec *parser.OpenAPISpec) {
+	owners := operationOwners(resourceMap)
+	for _, path := range sortedPaths(spec.Paths) {
+		pathItem := spec.Paths[path]
+		for _, op := range pathItemOperations(pathItem) {
+			operation := op.method + " " + path
+			owner := owners[operation]
+			if owner == nil {
+				continue
+			}
+			for _, parameter := range slices.Concat(pathItem.Parameters, op.operation.Parameters) {
+				parameter = resolveParameter(spec, parameter)
+				if parameter.In != "query" || !slices.Contains(expandParameters, parameter.Name) {
+					continue
+				}
+
+				values := expandValues(parameter.Schema)
+				if len(values) == 0 {
+					for i := range owner.Relationships {
+						if owner.Relationships[i].Expandable {
+							addExpandOperation(&owner.Relationships[i], operation)
+						}
+					}
+					continue
+				}
+
+				evidence := newEvidence(models.EvidenceParameter, fmt.Sprintf("%s?%s=%s", operation, parameter.Name, strings.Join(values, ",")))
+				for _, value := range values {
+					name, _, _ := strings.Cut(value, ".")
+					if rel := rd.expandedRelationship(resourceMap, owner, name, evidence); rel != nil {
+						addEvidence(rel, evidence)
+						rel.Expandable = true
+						addExpandOperation(rel, operation)
+					}
+				}
+			}
+		}
+	}
+}
+
+// expandedRelationship finds the relationship an expand value names, first
+// by property and then by resource, and adds one when only the resource
+// exists
+func (rd *RelationshipDetector) expandedRelationship(resourceMap map[string]*models.Resource, owner *models.Resource, name string, evidence models.Evidence) *models.Relationship {
+	for i, rel := range owner.Relationships {
+		if rel.Via == name && !inverseRelationship(rel.Type) {
+			return &owner.Relationships[i]
+		}
+	}
+	target := rd.findResource(resourceMap, name)
+	if target == nil || target.Name == owner.Name {
+		return nil
+	}
+	for i, rel := range owner.Relationships {
+		if rel.Resource == target.Name && !inverseRelationship(rel.Type) {
+			return &owner.Relationships[i]
+		}
+	}
+	return rd.addRelationship(resourceMap, owner.Name, target.Name, "references", name, "", false, evidence)
+}
+
+// inverseRelationship reports whether a relationship type is the reverse
+// side of another relationship
+func inverseRelationship(relType string) bool {
+	return relType == "belongs_to" || relType == "referenced_by"
+}
+
+// expandValues returns the enumerated values of an expand parameter, which
+// may be a single value or an array
+func expandValues(schema *parser.Schema) []string {
+	if schema == nil {
+		return nil
+	}
+	enum := schema.Enum
+	if schema.Items != nil {
+		enum = schema.Items.Enum
+	}
+	var values []string
+	for _, value := range enum {
+		if s, ok := value.(string); ok && s != "" {
+			values = append(values, s)
+		}
+	}
+	return values
+}
+
+// addExpandOperation records an operation that can expand a relationship
+func addExpandOperation(rel *models.Relationship, operation string) {
+	if !slices.Contains(rel.ExpandOperations, operation) {
+		rel.ExpandOperations = append(rel.ExpandOperations, operation)
+	}
+}
+
+// resolveParameter follows a reference to components.parameters
+func resolveParameter(spec *parser.OpenAPISpec, parameter parser.Parameter) parser.Parameter {
+	if name, ok := strings.CutPrefix(parameter.Ref, "#/components/parameters/"); ok && spec.Components != nil {
+		return spec.Components.Parameters[name]
+	}
+	return parameter
+}
@@ -0,0 +1,105 @@
+package analyzer
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/orchard9/api-godoc/internal/parser"
+	"github.com/orchard9/api-godoc/pkg/models"
+)
+
+func TestExpandableRef(t *testing.T) {
+	tests := []struct {
+		name     string
+		schema   parser.Schema
+		expected string
+	}{
+		{"identifier or object", parser.Schema{AnyOf: []parser.Schema{{Type: "string"}, {Ref: "#/components/schemas/Customer"}, {Ref: "#/components/schemas/DeletedCustomer"}}}, "#/components/schemas/Customer"},
+		{"oneOf", parser.Schema{OneOf: []parser.Schema{{Ref: "#/components/schemas/Invoice"}, {Type: "integer"}}}, "#/components/schemas/Invoice"},
+		{"objects only", parser.Schema{AnyOf: []parser.Schema{{Ref: "#/components/schemas/Card"}, {Ref: "#/components/schemas/Account"}}}, ""},
+		{"plain reference", parser.Schema{Ref: "#/components/schemas/Customer"}, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := expandableRef(tt.schema); got != tt.expected {
+				t.Errorf("expandableRef() = %q, want %q", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestExpandableRelationships(t *testing.T) {
+	expand := parser.Parameter{Name: "expand[]", In: "query", Schema: &parser.Schema{Type: "array", Items: &parser.Schema{Type: "string"}}}
+	spec := &parser.OpenAPISpec{
+		Paths: map[string]parser.PathItem{
+			"/charges": {Get: &parser.Operation{Parameters: []parser.Parameter{{Ref: "#/components/parameters/Expand"}}}},
+			"/charges/{chargeId}": {
+				Parameters: []parser.Parameter{expand},
+				Get:        &parser.Operation{},
+				Delete:     &parser.Operation{},
+			},
+			"/customers":     {Get: &parser.Operation{}},
+			"/refunds":       {Get: &parser.Operation{}},
+			"/articles":      {Get: &parser.Operation{Parameters: []parser.Parameter{{Name: "include", In: "query", Schema: &parser.Schema{Type: "string", Enum: []interface{}{"author", "comments.author"}}}}}},
+			"/authors":       {Get: &parser.Operation{}},
+			"/comments/{id}": {Get: &parser.Operation{}},
+		},
+		Components: &parser.Components{
+			Parameters: map[string]parser.Parameter{"Expand": expand},
+			Schemas: map[string]parser.Schema{
+				"Charge": {
+					Type:     "object",
+					Required: []string{"customer"},
+					Properties: map[string]parser.Schema{
+						"customer": {AnyOf: []parser.Schema{{Type: "string"}, {Ref: "#/components/schemas/Customer"}}},
+						"refunds":  {Type: "array", Items: &parser.Schema{AnyOf: []parser.Schema{{Type: "string"}, {Ref: "#/components/schemas/Refund"}}}},
+					},
+				},
+			},
+		},
+	}
+
+	resources := NewResourceAnalyzer().ExtractResources(spec)
+	NewRelationshipDetector().DetectRelationships(resources, spec)
+
+	type key struct{ from, rel, to string }
+	actual := make(map[key]models.Relationship)
+	for _, resource := range resources {
+		for _, rel := range resource.Relationships {
+			actual[key{resource.Name, rel.Type, rel.Resource}] = rel
+		}
+	}
+
+	charges := []string{"GET /charges", "GET /charges/{chargeId}", "DELETE /charges/{chargeId}"}
+	tests := []struct {
+		key         key
+		cardinality string
+		required    bool
+		operations  []string
+	}{
+		{key{"charges", "references", "customers"}, models.CardinalityManyToOne, true, charges},
+		{key{"charges", "has_many", "refunds"}, models.CardinalityOneToMany, false, charges},
+		{key{"articles", "references", "authors"}, "", false, []string{"GET /articles"}},
+		{key{"articles", "references", "comments"}, "", false, []string{"GET /articles"}},
+	}
+
+	for _, tt := range tests {
+		rel, ok := actual[tt.key]
+		if !ok {
+			t.Errorf("Missing relationship %+v", tt.key)
+			continue
+		}
+		if !rel.Expandable || rel.Cardinality != tt.cardinality || rel.Required != tt.required {
+			t.Errorf("%+v: expected expandable %s (required %v), got %+v", tt.key, tt.cardinality, tt.required, rel)
+		}
+		if !reflect.DeepEqual(rel.ExpandOperations, tt.operations) {
+			t.Errorf("%+v: expected expand operations %v, got %v", tt.key, tt.operations, rel.ExpandOperations)
+		}
+	}
+
+	// Only the forward side is expandable
+	if rel := actual[key{"customers", "referenced_by", "charges"}]; rel.Expandable {
+		t.Errorf("Expected the inverse relationship not to be expandable, got %+v", rel)
+	}
+}
@@ -0,0 +1,319 @@
+package analyzer
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/orchard9/api-godoc/internal/parser"
+	"github.com/orchard9/api-godoc/pkg/models"
+)
+
+// Grouping strategy names accepted by NewGroupingStrategy
+const (
+	GroupByPath        = "path"
+	GroupByTag         = "tag"
+	GroupByOperationID = "operationId"
+	GroupByExtension   = "extension"
+	GroupByHybrid      = "hybrid"
+)
+
+// DefaultResourceExtension is the vendor extension read by the extension strategy
+const DefaultResourceExtension = "x-resource"
+
+// ungroupedResource collects operations a strict strategy cannot place
+const ungroupedResource = "ungrouped"
+
+// GroupingStrategy assigns operations to resources
+type GroupingStrategy interface {
+	// Name returns the strategy identifier
+	Name() string
+	// Resources returns the resources an operation belongs to, or nil when
+	// the strategy has no opinion about it
+	Resources(op OperationContext) []string
+}
+
+// OperationContext describes an operation being grouped
+type OperationContext struct {
+	Path          string
+	Method        string
+	Operation     *parser.Operation
+	PathResources []string // resources derived from the path structure
+}
+
+// NewGroupingStrategy creates a strategy by name. The extension argument
+// names the vendor extension used by the extension strategy.
+func NewGroupingStrategy(name, extension string) (GroupingStrategy, error) {
+	if extension == "" {
+		extension = DefaultResourceExtension
+	}
+
+	switch name {
+	case "", GroupByPath:
+		return pathStrategy{}, nil
+	case GroupByTag:
+		return tagStrategy{}, nil
+	case GroupByOperationID:
+		return operationIDStrategy{}, nil
+	case GroupByExtension:
+		return extensionStrategy{extension: extension}, nil
+	case GroupByHybrid:
+		return fallbackStrategy{name: GroupByHybrid, strategies: []GroupingStrategy{tagStrategy{}, pathStrategy{}}}, nil
+	}
+
+	return nil, fmt.Errorf("unknown grouping strategy: %s (expected path, tag, operationId, extension or hybrid)", name)
+}
+
+// pathStrategy groups by path structure
+type pathStrategy struct{}
+
+func (pathStrategy) Name() string { return GroupByPath }
+
+func (pathStrategy) Resources(op OperationContext) []string {
+	return op.PathResources
+}
+
+// tagStrategy groups by the first operation tag. gRPC gateway tags such as
+// "ProjectService" are reduced to "project".
+type tagStrategy struct{}
+
+func (tagStrategy) Name() string { return GroupByTag }
+
+func (tagStrategy) Resources(op OperationContext) []string {
+	if op.Operation == nil || len(op.Operation.Tags) == 0 {
+		return nil
+	}
+	if name := normalizeGroupName(trimServiceSuffix(op.Operation.Tags[0])); name != "" {
+		return []string{name}
+	}
+	return nil
+}
+
+// operationIDStrategy groups by the noun in the operationId, e.g.
+// "listUsers" and "UserService_GetUser" map to "users" and "user"
+type operationIDStrategy struct{}
+
+func (operationIDStrategy) Name() string { return GroupByOperationID }
+
+func (operationIDStrategy) Resources(op OperationContext) []string {
+	if op.Operation == nil || op.Operation.OperationID == "" {
+		return nil
+	}
+	if name := operationIDResource(op.Operation.OperationID); name != "" {
+		return []string{name}
+	}
+	return nil
+}
+
+// extensionStrategy groups by a vendor extension holding a resource name or list of names
+type extensionStrategy struct {
+	extension string
+}
+
+func (extensionStrategy) Name() string { return GroupByExtension }
+
+func (s extensionStrategy) Resources(op OperationContext) []string {
+	if op.Operation == nil {
+		return nil
+	}
+	value, ok := op.Operation.Extension(s.extension)
+	if !ok {
+		return nil
+	}
+
+	var names []string
+	switch v := value.(type) {
+	case string:
+		names = []string{v}
+	case []interface{}:
+		for _, item := range v {
+			if name, ok := item.(string); ok {
+				names = append(names, name)
+			}
+		}
+	}
+
+	var resources []string
+	for _, name := range names {
+		if name = normalizeGroupName(name); name != "" {
+			resources = append(resources, name)
+		}
+	}
+	return resources
+}
+
+// fallbackStrategy uses the first strategy with an opinion
+type fallbackStrategy struct {
+	name       string
+	strategies []GroupingStrategy
+}
+
+func (s fallbackStrategy) Name() string { return s.name }
+
+func (s fallbackStrategy) Resources(op OperationContext) []string {
+	for _, strategy := range s.strategies {
+		if resources := strategy.Resources(op); len(resources) > 0 {
+			return resources
+		}
+	}
+	return nil
+}
+
+// operationVerbs are leading operationId words that name the action, not the resource
+var operationVerbs = map[string]bool{
+	"list": true, "get": true, "create": true, "update": true, "delete": true,
+	"patch": true, "put": true, "post": true, "remove": true, "add": true,
+	"set": true, "search": true, "find": true, "fetch": true, "retrieve": true,
+	"read": true, "replace": true, "upsert": true, "batch": true, "bulk": true,
+	"cancel": true, "start": true, "stop": true, "submit": true, "process": true,
+	"publish": true, "build": true, "approve": true, "reject": true, "enable": true,
+	"disable": true, "sync": true, "import": true, "export": true, "count": true,
+	"check": true, "validate": true, "verify": true, "generate": true,
+	"refresh": true, "revoke": true, "register": true,
+}
+
+// operationIDResource extracts the resource noun from an operationId. For
+// "Service_Method" ids the method is used, falling back to the service when
+// the method is only a verb.
+func operationIDResource(operationID string) string {
+	service, method := "", operationID
+	if i := strings.LastIndex(operationID, "_"); i >= 0 {
+		service, method = operationID[:i], operationID[i+1:]
+	}
+
+	words := splitWords(method)
+	if len(words) > 1 && operationVerbs[words[0]] {
+		words = words[1:]
+	} else if len(words) == 1 && operationVerbs[words[0]] {
+		words = nil
+	}
+	if len(words) > 0 {
+		return strings.Join(words, "-")
+	}
+
+	return normalizeGroupName(trimServiceSuffix(service))
+}
+
+// trimServiceSuffix drops a trailing "Service" from gRPC service names
+func trimServiceSuffix(name string) string {
+	if trimmed := strings.TrimSuffix(name, "Service"); trimmed != "" {
+		return trimmed
+	}
+	return name
+}
+
+// normalizeGroupName converts a tag or identifier to the kebab-case form used by path resources
+func normalizeGroupName(name string) string {
+	return strings.Join(splitWords(name), "-")
+}
+
+// splitWords splits camelCase, snake_case, kebab-case and spaced names into lowercase words
+func splitWords(name string) []string {
+	var words []string
+	var current []rune
+
+	flush := func() {
+		if len(current) > 0 {
+			words = append(words, strings.ToLower(string(current)))
+			current = current[:0]
+		}
+	}
+
+	runes := []rune(name)
+	for i, r := range runes {
+		switch {
+		case r == '_' || r == '-' || r == ' ' || r == '.' || r == '/':
+			flush()
+		case unicode.IsUpper(r) && i > 0 && (unicode.IsLower(runes[i-1]) || unicode.IsDigit(runes[i-1])):
+			flush()
+			current = append(current, r)
+		default:
+			current = append(current, r)
+		}
+	}
+	flush()
+
+	return words
+}
+
+// CompareStrategies groups every operation with each strategy and returns the
+// operations the strategies place differently. Names are compared in their
+// normalized form, and strategies without an opinion on an operation are
+// ignored for it.
+func (ra *ResourceAnalyzer) CompareStrategies(spec *parser.OpenAPISpec, strategies ...GroupingStrategy) []models.GroupingDisagreement {
+	var disagreements []models.GroupingDisagreement
+	patterns := ra.analyzePathPatterns(spec.Paths)
+
+	for _, path := range sortedPaths(spec.Paths) {
+		pathResources := ra.extractResourcesForPath(path, patterns)
+
+		for _, mo := range pathItemOperations(spec.Paths[path]) {
+			ctx := OperationContext{Path: path, Method: mo.method, Operation: mo.operation, PathResources: pathResources}
+
+			assignments := make(map[string][]string)
+			keys := make(map[string]bool)
+			for _, strategy := range strategies {
+				resources := strategy.Resources(ctx)
+				if len(resources) == 0 {
+					continue
+				}
+				assignments[strategy.Name()] = resources
+
+				var parts []string
+				for _, resource := range resources {
+					parts = append(parts, ra.names.Key(resource))
+				}
+				sort.Strings(parts)
+				keys[strings.Join(parts, ",")] = true
+			}
+
+			if len(keys) > 1 {
+				disagreements = append(disagreements, models.GroupingDisagreement{
+					Method:      mo.method,
+					Path:        path,
+					Assignments: assignments,
+				})
+			}
+		}
+	}
+
+	return disagreements
+}
+
+// methodOperation pairs an HTTP method with its operation
+type methodOperation struct {
+	method    string
+	operation *parser.Operation
+}
+
+// pathItemOperations returns the operations of a path item in a fixed method order
+func pathItemOperations(pathItem parser.PathItem) []methodOperation {
+	candidates := []methodOperation{
+		{"GET", pathItem.Get},
+		{"POST", pathItem.Post},
+		{"PUT", pathItem.Put},
+		{"DELETE", pathItem.Delete},
+		{"PATCH", pathItem.Patch},
+		{"HEAD", pathItem.Head},
+		{"OPTIONS", pathItem.Options},
+	}
+
+	operations := make([]methodOperation, 0, len(candidates))
+	for _, candidate := range candidates {
+		if candidate.operation != nil {
+			operations = append(operations, candidate)
+		}
+	}
+	return operations
+}
+
+// sortedPaths returns spec paths in lexical order
+func sortedPaths(paths map[string]parser.PathItem) []string {
+	result := make([]string, 0, len(paths))
+	for path := range paths {
+		result = append(result, path)
+	}
+	sort.Strings(result)
+	return result
+}
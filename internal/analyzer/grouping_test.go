@@ -0,0 +1,154 @@
+package analyzer
+
+import (
+	"reflect"
+	"sort"
+	"testing"
+
+	"github.com/orchard9/api-godoc/internal/parser"
+)
+
+func groupingTestSpec() *parser.OpenAPISpec {
+	return &parser.OpenAPISpec{
+		Paths: map[string]parser.PathItem{
+			"/v1/projects": {
+				Get: &parser.Operation{Tags: []string{"ProjectService"}, OperationID: "ProjectService_ListProjects"},
+			},
+			"/v1/projects/{id}": {
+				Get: &parser.Operation{Tags: []string{"ProjectService"}, OperationID: "ProjectService_GetProject"},
+			},
+			"/v1/audits/{id}": {
+				Get: &parser.Operation{
+					Tags:        []string{"ProjectService"},
+					OperationID: "ProjectService_GetAudit",
+					Extensions:  map[string]interface{}{"x-resource": "Audit"},
+				},
+			},
+			"/v1/tasks/{id}": {
+				Delete: &parser.Operation{OperationID: "deleteTask"},
+			},
+		},
+	}
+}
+
+func TestNewGroupingStrategy(t *testing.T) {
+	for _, name := range []string{"", GroupByPath, GroupByTag, GroupByOperationID, GroupByExtension, GroupByHybrid} {
+		strategy, err := NewGroupingStrategy(name, "")
+		if err != nil {
+			t.Errorf("NewGroupingStrategy(%q) error = %v", name, err)
+			continue
+		}
+		if name != "" && strategy.Name() != name {
+			t.Errorf("Expected strategy name %q, got %q", name, strategy.Name())
+		}
+	}
+
+	if _, err := NewGroupingStrategy("bogus", ""); err == nil {
+		t.Error("Expected error for unknown strategy")
+	}
+}
+
+func TestGroupingStrategies(t *testing.T) {
+	spec := groupingTestSpec()
+
+	tests := []struct {
+		strategy string
+		expected []string
+	}{
+		{GroupByPath, []string{"audits", "projects", "tasks"}},
+		{GroupByTag, []string{"project", "ungrouped"}},
+		{GroupByOperationID, []string{"audit", "projects", "task"}},
+		{GroupByExtension, []string{"audit", "ungrouped"}},
+		{GroupByHybrid, []string{"project", "tasks"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.strategy, func(t *testing.T) {
+			strategy, err := NewGroupingStrategy(tt.strategy, "")
+			if err != nil {
+				t.Fatalf("NewGroupingStrategy() error = %v", err)
+			}
+
+			ra := NewResourceAnalyzer()
+			ra.SetGroupingStrategy(strategy)
+
+			var names []string
+			for _, resource := range ra.ExtractResources(spec) {
+				names = append(names, resource.Name)
+			}
+			sort.Strings(names)
+
+			if !reflect.DeepEqual(names, tt.expected) {
+				t.Errorf("Expected resources %v, got %v", tt.expected, names)
+			}
+		})
+	}
+}
+
+func TestOperationIDResource(t *testing.T) {
+	tests := []struct {
+		operationID string
+		expected    string
+	}{
+		{"listUsers", "users"},
+		{"getUserById", "user-by-id"},
+		{"ProjectService_GetAuditRequest", "audit-request"},
+		{"AuthService_Register", "auth"},
+		{"AuthService_Login", "login"},
+		{"delete", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.operationID, func(t *testing.T) {
+			if got := operationIDResource(tt.operationID); got != tt.expected {
+				t.Errorf("operationIDResource(%q) = %q, expected %q", tt.operationID, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestNormalizeGroupName(t *testing.T) {
+	tests := map[string]string{
+		"ApiKeys":       "api-keys",
+		"api_keys":      "api-keys",
+		"User Profiles": "user-profiles",
+		"OAuth2":        "oauth2",
+		"v2Items":       "v2-items",
+	}
+
+	for input, expected := range tests {
+		if got := normalizeGroupName(input); got != expected {
+			t.Errorf("normalizeGroupName(%q) = %q, expected %q", input, got, expected)
+		}
+	}
+}
+
+func TestCompareStrategies(t *testing.T) {
+	ra := NewResourceAnalyzer()
+	spec := groupingTestSpec()
+
+	var strategies []GroupingStrategy
+	for _, name := range []string{GroupByPath, GroupByTag, GroupByExtension} {
+		strategy, _ := NewGroupingStrategy(name, "")
+		strategies = append(strategies, strategy)
+	}
+
+	disagreements := ra.CompareStrategies(spec, strategies...)
+
+	// Plural path names agree with singular tags; only the audit is grouped differently
+	if len(disagreements) != 1 {
+		t.Fatalf("Expected 1 disagreement, got %d: %+v", len(disagreements), disagreements)
+	}
+	d := disagreements[0]
+	if d.Method != "GET" || d.Path != "/v1/audits/{id}" {
+		t.Errorf("Expected GET /v1/audits/{id}, got %s %s", d.Method, d.Path)
+	}
+	expected := map[string][]string{
+		GroupByPath:      {"audits"},
+		GroupByTag:       {"project"},
+		GroupByExtension: {"audit"},
+	}
+	if !reflect.DeepEqual(d.Assignments, expected) {
+		t.Errorf("Expected assignments %v, got %v", expected, d.Assignments)
+	}
+}
@@ -0,0 +1,209 @@
+package analyzer
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/orchard9/api-godoc/internal/parser"
+	"github.com/orchard9/api-godoc/pkg/models"
+)
+
+// nestedSeparator joins parent and child names of separated nested resources
+const nestedSeparator = "/"
+
+// chainLevel is one resource level of a path, e.g. "tasks/{taskId}"
+type chainLevel struct {
+	name       string // resource name, qualified with its parents when nested resources are kept separate
+	plain      string // resource name as it appears in the path
+	identifier string // path parameter identifying an item, if any
+	pattern    string // path up to and including this level
+	nested     bool   // whether the level is nested under an identified parent
+}
+
+// SetSeparateNested keeps nested resources apart from same-named resources
+// elsewhere, e.g. /features/{id}/tasks becomes "features/tasks" instead of
+// being merged with top-level /tasks
+func (ra *ResourceAnalyzer) SetSeparateNested(separate bool) {
+	ra.separateNested = separate
+}
+
+// resourceChain splits a path into resource levels. A level is nested under
+// the previous one only when an identifier parameter sits between them;
+// namespace segments such as /auth/api-keys start a new chain.
+func (ra *ResourceAnalyzer) resourceChain(path string) []chainLevel {
+	var levels []chainLevel
+	var prefix []string
+	var qualified []string
+
+	segments := splitPath(path)
+	_, stripped, _ := ra.segments.stripPrefix(segments)
+
+	for i, segment := range segments {
+		prefix = append(prefix, segment)
+		// Configured prefixes such as /tenants/{tenant} are not resource levels
+		if i < stripped {
+			continue
+		}
+
+		// A variable identifies the item of the current level
+		if match := ra.pathVariableRegex.FindString(segment); match != "" && len(levels) > 0 {
+			last := &levels[len(levels)-1]
+			if last.identifier == "" {
+				last.identifier = strings.Trim(match, "{}")
+				last.pattern = "/" + strings.Join(prefix, "/")
+			}
+		}
+
+		// Custom methods such as {id}:move or tasks:batch are actions, not levels
+		literal := strings.TrimSpace(ra.pathVariableRegex.ReplaceAllString(segment, ""))
+		if literal == "" || strings.Contains(literal, ":") || !ra.isResourceName(literal) {
+			continue
+		}
+
+		nested := len(levels) > 0 && levels[len(levels)-1].identifier != ""
+		if !nested {
+			qualified = qualified[:0]
+		}
+		qualified = append(qualified, literal)
+
+		levels = append(levels, chainLevel{
+			name:    strings.Join(qualified, nestedSeparator),
+			plain:   literal,
+			pattern: "/" + strings.Join(prefix, "/"),
+			nested:  nested,
+		})
+	}
+
+	if !ra.separateNested {
+		for i := range levels {
+			levels[i].name = levels[i].plain
+		}
+	}
+
+	return levels
+}
+
+// qualifyResources maps path resource names to their chain names for a path
+func (ra *ResourceAnalyzer) qualifyResources(path string, names []string) []string {
+	if !ra.separateNested {
+		return names
+	}
+
+	qualified := make(map[string]string)
+	for _, level := range ra.resourceChain(path) {
+		qualified[level.plain] = level.name
+	}
+
+	result := make([]string, len(names))
+	for i, name := range names {
+		result[i] = name
+		if q, ok := qualified[name]; ok {
+			result[i] = q
+		}
+	}
+	return result
+}
+
+// resourcePlacement collects where a resource appears in the path tree
+type resourcePlacement struct {
+	children map[string]bool
+	parents  map[string]bool // enclosing resource per occurrence, "" at the root
+	patterns map[string]int  // occurrences per path pattern
+	pattern  string
+	levels   []chainLevel
+}
+
+// buildHierarchy links resources to their parents and children and picks a
+// canonical path pattern with the identifier of every level
+func (ra *ResourceAnalyzer) buildHierarchy(resourceMap map[string]*models.Resource, paths map[string]parser.PathItem) {
+	placements := make(map[string]*resourcePlacement)
+	candidates := make(map[string][][]chainLevel)
+
+	for _, path := range sortedPaths(paths) {
+		levels := ra.resourceChain(path)
+		for i, level := range levels {
+			if resourceMap[level.name] == nil {
+				continue
+			}
+
+			placement := placements[level.name]
+			if placement == nil {
+				placement = &resourcePlacement{children: make(map[string]bool), parents: make(map[string]bool), patterns: make(map[string]int)}
+				placements[level.name] = placement
+			}
+			placement.patterns[level.pattern]++
+
+			parent := ""
+			if level.nested && levels[i-1].name != level.name && placements[levels[i-1].name] != nil {
+				parent = levels[i-1].name
+				placements[parent].children[level.name] = true
+			}
+			placement.parents[parent] = true
+
+			candidates[level.name] = append(candidates[level.name], connectedLevels(levels[:i+1]))
+		}
+	}
+
+	for name, placement := range placements {
+		// Canonical pattern: prefer identified levels, then the shallowest
+		// path, then the most common spelling
+		for _, chain := range candidates[name] {
+			if placement.levels == nil || placement.betterChain(chain) {
+				placement.levels = chain
+				placement.pattern = chain[len(chain)-1].pattern
+			}
+		}
+
+		resource := resourceMap[name]
+		resource.PathPattern = placement.pattern
+
+		// A resource nested under several parents, or also reachable at the
+		// root, has no single enclosing resource
+		resource.Parent = ""
+		if len(placement.parents) == 1 && len(placement.levels) > 1 {
+			if parent := placement.levels[len(placement.levels)-2].name; resourceMap[parent] != nil {
+				resource.Parent = parent
+			}
+		}
+
+		resource.Children = nil
+		for child := range placement.children {
+			resource.Children = append(resource.Children, child)
+		}
+		sort.Strings(resource.Children)
+
+		resource.Identifiers = nil
+		for _, level := range placement.levels {
+			if level.identifier != "" {
+				resource.Identifiers = append(resource.Identifiers, models.ResourceIdentifier{
+					Resource:  level.name,
+					Parameter: level.identifier,
+				})
+			}
+		}
+	}
+}
+
+// connectedLevels returns the trailing levels joined by identifiers
+func connectedLevels(levels []chainLevel) []chainLevel {
+	start := len(levels) - 1
+	for start > 0 && levels[start].nested {
+		start--
+	}
+	return levels[start:]
+}
+
+// betterChain reports whether a chain makes a better canonical pattern than the current one
+func (p *resourcePlacement) betterChain(chain []chainLevel) bool {
+	level, current := chain[len(chain)-1], p.levels[len(p.levels)-1]
+	if (level.identifier != "") != (current.identifier != "") {
+		return level.identifier != ""
+	}
+	if len(chain) != len(p.levels) {
+		return len(chain) < len(p.levels)
+	}
+	if p.patterns[level.pattern] != p.patterns[current.pattern] {
+		return p.patterns[level.pattern] > p.patterns[current.pattern]
+	}
+	return level.pattern < current.pattern
+}
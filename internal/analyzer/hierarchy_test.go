@@ -0,0 +1,173 @@
+package analyzer
+
+import (
+	"reflect"
+	"slices"
+	"testing"
+
+	"github.com/orchard9/api-godoc/internal/parser"
+	"github.com/orchard9/api-godoc/pkg/models"
+)
+
+func hierarchyTestSpec() *parser.OpenAPISpec {
+	op := func() *parser.Operation { return &parser.Operation{} }
+	return &parser.OpenAPISpec{
+		Paths: map[string]parser.PathItem{
+			"/v1/features":                       {Get: op()},
+			"/v1/features/{featureId}":           {Get: op()},
+			"/v1/features/{featureId}/tasks":     {Get: op()},
+			"/v1/tasks":                          {Get: op()},
+			"/v1/tasks/{taskId}":                 {Get: op()},
+			"/v1/tasks/{taskId}:move":            {Post: op()},
+			"/v1/tasks/{taskId}/comments":        {Get: op()},
+			"/v1/tasks/{taskId}/comments/{id}":   {Delete: op()},
+			"/v1/auth/api-keys":                  {Get: op()},
+			"/v1/auth/api-keys/{keyId}":          {Delete: op()},
+			"/v1/projects/{project.id}/settings": {Get: op()},
+		},
+	}
+}
+
+func TestResourceChain(t *testing.T) {
+	ra := NewResourceAnalyzer()
+
+	tests := []struct {
+		path     string
+		expected []chainLevel
+	}{
+		{
+			path: "/v1/features/{featureId}/tasks",
+			expected: []chainLevel{
+				{name: "features", plain: "features", identifier: "featureId", pattern: "/v1/features/{featureId}"},
+				{name: "tasks", plain: "tasks", pattern: "/v1/features/{featureId}/tasks", nested: true},
+			},
+		},
+		{
+			path: "/v1/auth/api-keys/{keyId}",
+			expected: []chainLevel{
+				{name: "auth", plain: "auth", pattern: "/v1/auth"},
+				{name: "api-keys", plain: "api-keys", identifier: "keyId", pattern: "/v1/auth/api-keys/{keyId}"},
+			},
+		},
+		{
+			path: "/v1/tasks/{taskId}:move",
+			expected: []chainLevel{
+				{name: "tasks", plain: "tasks", identifier: "taskId", pattern: "/v1/tasks/{taskId}:move"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			if got := ra.resourceChain(tt.path); !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("resourceChain(%q) = %+v, expected %+v", tt.path, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestBuildHierarchy(t *testing.T) {
+	ra := NewResourceAnalyzer()
+	resources := make(map[string]models.Resource)
+	for _, resource := range ra.ExtractResources(hierarchyTestSpec()) {
+		resources[resource.Name] = resource
+	}
+
+	features := resources["features"]
+	if features.PathPattern != "/v1/features/{featureId}" || features.Parent != "" {
+		t.Errorf("Expected root features at /v1/features/{featureId}, got %q (parent %q)", features.PathPattern, features.Parent)
+	}
+	if !reflect.DeepEqual(features.Children, []string{"tasks"}) {
+		t.Errorf("Expected features children [tasks], got %v", features.Children)
+	}
+
+	// Merged tasks keep their top-level canonical path
+	tasks := resources["tasks"]
+	if tasks.PathPattern != "/v1/tasks/{taskId}" || tasks.Parent != "" {
+		t.Errorf("Expected root tasks at /v1/tasks/{taskId}, got %q (parent %q)", tasks.PathPattern, tasks.Parent)
+	}
+
+	comments := resources["comments"]
+	if comments.Parent != "tasks" || comments.PathPattern != "/v1/tasks/{taskId}/comments/{id}" {
+		t.Errorf("Expected comments nested under tasks, got %q (parent %q)", comments.PathPattern, comments.Parent)
+	}
+	expectedIDs := []models.ResourceIdentifier{{Resource: "tasks", Parameter: "taskId"}, {Resource: "comments", Parameter: "id"}}
+	if !reflect.DeepEqual(comments.Identifiers, expectedIDs) {
+		t.Errorf("Expected identifiers %v, got %v", expectedIDs, comments.Identifiers)
+	}
+
+	// Namespace segments do not make parents
+	if keys := resources["api-keys"]; keys.Parent != "" || keys.PathPattern != "/v1/auth/api-keys/{keyId}" {
+		t.Errorf("Expected api-keys at the root, got %q (parent %q)", keys.PathPattern, keys.Parent)
+	}
+}
+
+func TestBuildHierarchyMultipleParents(t *testing.T) {
+	op := func() *parser.Operation { return &parser.Operation{} }
+	spec := &parser.OpenAPISpec{
+		Paths: map[string]parser.PathItem{
+			"/features/{featureId}":                {Get: op()},
+			"/features/{featureId}/attachments":    {Get: op()},
+			"/tasks/{taskId}":                      {Get: op()},
+			"/tasks/{taskId}/attachments/{id}":     {Get: op()},
+			"/tasks/{taskId}/comments":             {Get: op()},
+			"/tasks/{taskId}/comments/{commentId}": {Delete: op()},
+		},
+	}
+
+	resources := make(map[string]models.Resource)
+	for _, resource := range NewResourceAnalyzer().ExtractResources(spec) {
+		resources[resource.Name] = resource
+	}
+
+	// Attachments are nested under features and tasks, so neither is the parent
+	attachments := resources["attachments"]
+	if attachments.Parent != "" || attachments.PathPattern != "/tasks/{taskId}/attachments/{id}" {
+		t.Errorf("Expected attachments without a parent, got %q (parent %q)", attachments.PathPattern, attachments.Parent)
+	}
+	for _, parent := range []string{"features", "tasks"} {
+		if !slices.Contains(resources[parent].Children, "attachments") {
+			t.Errorf("Expected attachments among %s children, got %v", parent, resources[parent].Children)
+		}
+	}
+
+	if comments := resources["comments"]; comments.Parent != "tasks" {
+		t.Errorf("Expected comments nested under tasks, got parent %q", comments.Parent)
+	}
+}
+
+func TestSeparateNested(t *testing.T) {
+	ra := NewResourceAnalyzer()
+	ra.SetSeparateNested(true)
+
+	resources := make(map[string]models.Resource)
+	for _, resource := range ra.ExtractResources(hierarchyTestSpec()) {
+		resources[resource.Name] = resource
+	}
+
+	nested, ok := resources["features/tasks"]
+	if !ok {
+		t.Fatalf("Expected separate features/tasks resource, got %v", resourceNames(resources))
+	}
+	if nested.Parent != "features" || len(nested.Operations) != 1 || !nested.Operations[0].IsResourceOp {
+		t.Errorf("Expected features/tasks with one resource operation under features, got %+v", nested)
+	}
+
+	tasks := resources["tasks"]
+	for _, op := range tasks.Operations {
+		if op.Path == "/v1/features/{featureId}/tasks" {
+			t.Error("Expected nested tasks operation to stay out of top-level tasks")
+		}
+	}
+	if !reflect.DeepEqual(tasks.Children, []string{"tasks/comments"}) {
+		t.Errorf("Expected tasks children [tasks/comments], got %v", tasks.Children)
+	}
+}
+
+func resourceNames(resources map[string]models.Resource) []string {
+	var names []string
+	for name := range resources {
+		names = append(names, name)
+	}
+	return names
+}
@@ -0,0 +1,241 @@
+package analyzer
+
+import (
+	"strings"
+
+	"github.com/orchard9/api-godoc/internal/parser"
+	"github.com/orchard9/api-godoc/pkg/models"
+)
+
+// listFieldNames are wrapper properties that hold the items of a paginated list
+var listFieldNames = map[string]bool{
+	"items": true, "data": true, "results": true, "records": true,
+	"entries": true, "content": true, "values": true, "nodes": true, "edges": true,
+}
+
+// paginationFieldNames are response properties that page through a list,
+// compared without case or underscores, e.g. nextPageToken or total_size
+var paginationFieldNames = map[string]bool{
+	"nextpagetoken": true, "pagetoken": true, "nexttoken": true, "nextcursor": true, "cursor": true,
+	"continuationtoken": true, "nextpage": true, "next": true, "hasmore": true,
+	"total": true, "totalsize": true, "totalcount": true, "count": true,
+	"page": true, "pagesize": true, "offset": true, "limit": true,
+}
+
+// isPaginationField reports whether a response property pages through a list
+func isPaginationField(name string) bool {
+	return paginationFieldNames[strings.ToLower(strings.ReplaceAll(name, "_", ""))]
+}
+
+// classifyOperations assigns a kind to every operation of every resource
+// and marks the standard reads and writes as resource operations. Writes to
+// a whole collection are batches only on resources that can be listed or
+// added to; a singleton such as /settings is updated in place.
+func (ra *ResourceAnalyzer) classifyOperations(resourceMap map[string]*models.Resource, paths map[string]parser.PathItem) {
+	identified := ra.identifiedSegments(paths)
+	for _, resource := range resourceMap {
+		for i := range resource.Operations {
+			resource.Operations[i].Kind = ra.classifyOperation(resource.Operations[i], identified)
+		}
+		collection := isCollectionResource(*resource)
+		for i, op := range resource.Operations {
+			if literal, _ := ra.lastLiteral(op.Path); !collection && op.Kind == models.KindBatch && !isBatchLiteral(literal) {
+				resource.Operations[i].Kind = itemKind(op.Method)
+			}
+			resource.Operations[i].IsResourceOp = isCRUDKind(resource.Operations[i].Kind)
+		}
+	}
+}
+
+// isCRUDKind reports whether a kind is a standard read or write of a
+// collection or item rather than an action or batch
+func isCRUDKind(kind string) bool {
+	switch kind {
+	case models.KindList, models.KindGet, models.KindCreate, models.KindReplace, models.KindUpdate, models.KindDelete:
+		return true
+	}
+	return false
+}
+
+// identifiedSegments returns the literal segments that are followed by an
+// identifier somewhere in the spec, i.e. the collections of the API
+func (ra *ResourceAnalyzer) identifiedSegments(paths map[string]parser.PathItem) map[string]bool {
+	identified := make(map[string]bool)
+	for path := range paths {
+		segments := ra.analysisSegments(path)
+		for i := 0; i+1 < len(segments); i++ {
+			if !ra.pathVariableRegex.MatchString(segments[i]) && strings.HasPrefix(segments[i+1], "{") {
+				identified[segments[i]] = true
+			}
+		}
+	}
+	return identified
+}
+
+// classifyOperation derives the kind of an operation from its method, the
+// shape of its path (collection, item, custom method) and its schemas
+func (ra *ResourceAnalyzer) classifyOperation(op models.Operation, identified map[string]bool) string {
+	if op.Method == "OPTIONS" || op.Method == "TRACE" {
+		return ""
+	}
+
+	literal, item := ra.lastLiteral(op.Path)
+
+	// grpc-gateway custom methods: /tasks/{id}:move, /tasks:batchGet
+	if isBatchLiteral(literal) {
+		return models.KindBatch
+	}
+	if strings.Contains(literal, ":") {
+		return models.KindAction
+	}
+
+	collection := literal != "" && !item && (identified[literal] || ra.isPluralNoun(literal))
+
+	switch {
+	case item || literal == "":
+		return itemKind(op.Method)
+	case collection && !identified[literal] && (op.Method == "GET" || op.Method == "HEAD") && ra.hasObjectResponse(op, literal):
+		// A plural word that reads one object is a singleton such as /settings
+		return models.KindGet
+	case collection:
+		return collectionKind(op)
+	}
+
+	// A literal that is neither an item nor a collection is a singleton
+	// sub-resource such as /users/{id}/profile or an action such as /activate
+	switch op.Method {
+	case "GET", "HEAD":
+		if ra.hasListResponse(op, literal) {
+			return models.KindList
+		}
+		return models.KindGet
+	case "POST":
+		return models.KindAction
+	}
+	return itemKind(op.Method)
+}
+
+// lastLiteral returns the last literal segment of a path and whether an
+// identifier follows it
+func (ra *ResourceAnalyzer) lastLiteral(path string) (string, bool) {
+	segments := ra.analysisSegments(path)
+	item := false
+	for i := len(segments) - 1; i >= 0; i-- {
+		if clean := strings.TrimSpace(ra.pathVariableRegex.ReplaceAllString(segments[i], "")); clean != "" {
+			return clean, item
+		}
+		item = true
+	}
+	return "", item
+}
+
+// isCollectionResource reports whether a resource can be listed or added to
+func isCollectionResource(resource models.Resource) bool {
+	for _, op := range resource.Operations {
+		if op.Kind == models.KindList || op.Kind == models.KindCreate {
+			return true
+		}
+	}
+	return false
+}
+
+// itemKind classifies an operation on a single item
+func itemKind(method string) string {
+	switch method {
+	case "GET", "HEAD":
+		return models.KindGet
+	case "PUT":
+		return models.KindReplace
+	case "PATCH":
+		return models.KindUpdate
+	case "DELETE":
+		return models.KindDelete
+	}
+	return models.KindAction
+}
+
+// collectionKind classifies an operation on a collection. Writes that
+// address the whole collection affect many items at once.
+func collectionKind(op models.Operation) string {
+	switch op.Method {
+	case "GET", "HEAD":
+		return models.KindList
+	case "POST":
+		if op.RequestBody != nil && op.RequestBody.Schema != nil && isArrayType(op.RequestBody.Schema.Type) {
+			return models.KindBatch
+		}
+		return models.KindCreate
+	}
+	return models.KindBatch
+}
+
+// isPluralNoun reports whether the last word of a segment is a plural noun
+func (ra *ResourceAnalyzer) isPluralNoun(segment string) bool {
+	words := splitWords(segment)
+	if len(words) == 0 {
+		return false
+	}
+	last := words[len(words)-1]
+	return ra.names.inflector.Singular(last) != last
+}
+
+// isBatchWord reports whether a path literal or custom method names a batch operation
+func isBatchWord(word string) bool {
+	words := splitWords(word)
+	return len(words) > 0 && (words[0] == "batch" || words[0] == "bulk")
+}
+
+// isBatchLiteral reports whether a path literal is a batch segment such as
+// batch or a batch custom method such as tasks:batchGet
+func isBatchLiteral(literal string) bool {
+	if i := strings.LastIndex(literal, ":"); i >= 0 {
+		return isBatchWord(literal[i+1:])
+	}
+	return isBatchWord(literal)
+}
+
+// hasListResponse reports whether the success response is an array or a
+// wrapper around one: a list field such as items, an array named after the
+// path literal as in grpc-gateway's {activities: [...]}, or any array next
+// to pagination fields such as nextPageToken
+func (ra *ResourceAnalyzer) hasListResponse(op models.Operation, literal string) bool {
+	for _, resp := range op.Responses {
+		if !strings.HasPrefix(resp.StatusCode, "2") || resp.Schema == nil {
+			continue
+		}
+		if isArrayType(resp.Schema.Type) {
+			return true
+		}
+		arrays, paginated := false, false
+		for _, field := range resp.Schema.Properties {
+			switch {
+			case !isArrayType(field.Type.Type):
+				paginated = paginated || isPaginationField(field.Name)
+			case listFieldNames[strings.ToLower(field.Name)]:
+				return true
+			case literal != "" && ra.names.Key(field.Name) == ra.names.Key(literal):
+				return true
+			default:
+				arrays = true
+			}
+		}
+		return arrays && paginated
+	}
+	return false
+}
+
+// hasObjectResponse reports whether the success response is known to be a
+// single object rather than a list
+func (ra *ResourceAnalyzer) hasObjectResponse(op models.Operation, literal string) bool {
+	for _, resp := range op.Responses {
+		if strings.HasPrefix(resp.StatusCode, "2") && resp.Schema != nil {
+			return !ra.hasListResponse(op, literal)
+		}
+	}
+	return false
+}
+
+// isArrayType reports whether a type label describes an array, e.g. "array[User]"
+func isArrayType(typeLabel string) bool {
+	return strings.HasPrefix(typeLabel, "array")
+}
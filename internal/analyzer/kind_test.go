@@ -0,0 +1,130 @@
+package analyzer
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/orchard9/api-godoc/internal/parser"
+	"github.com/orchard9/api-godoc/pkg/models"
+)
+
+func TestClassifyOperation(t *testing.T) {
+	ra := NewResourceAnalyzer()
+	identified := map[string]bool{"users": true, "tasks": true, "settings": true}
+
+	arrayResponse := []models.Response{{StatusCode: "200", Schema: &models.FieldType{Type: "array[User]"}}}
+	pagedResponse := []models.Response{{StatusCode: "200", Schema: &models.FieldType{
+		Type:       "object",
+		Properties: []models.Field{{Name: "items", Type: models.FieldType{Type: "array[User]"}}, {Name: "nextPageToken", Type: models.FieldType{Type: "string"}}},
+	}}}
+	arrayBody := &models.RequestBody{Schema: &models.FieldType{Type: "array[User]"}}
+	objectResponse := []models.Response{{StatusCode: "200", Schema: &models.FieldType{Type: "object"}}}
+	// grpc-gateway list responses name the array after the collection
+	namedResponse := []models.Response{{StatusCode: "200", Schema: &models.FieldType{
+		Type:       "object",
+		Properties: []models.Field{{Name: "activities", Type: models.FieldType{Type: "array[v1Activity]"}}, {Name: "nextPageToken", Type: models.FieldType{Type: "string"}}},
+	}}}
+	tokenResponse := []models.Response{{StatusCode: "200", Schema: &models.FieldType{
+		Type:       "object",
+		Properties: []models.Field{{Name: "containers", Type: models.FieldType{Type: "array[v1ReleaseContainer]"}}, {Name: "nextPageToken", Type: models.FieldType{Type: "string"}}},
+	}}}
+
+	tests := []struct {
+		name     string
+		op       models.Operation
+		expected string
+	}{
+		{"list collection", models.Operation{Method: "GET", Path: "/users"}, models.KindList},
+		{"get item", models.Operation{Method: "GET", Path: "/users/{id}"}, models.KindGet},
+		{"create", models.Operation{Method: "POST", Path: "/users"}, models.KindCreate},
+		{"replace", models.Operation{Method: "PUT", Path: "/users/{id}"}, models.KindReplace},
+		{"update", models.Operation{Method: "PATCH", Path: "/users/{id}"}, models.KindUpdate},
+		{"delete", models.Operation{Method: "DELETE", Path: "/users/{id}"}, models.KindDelete},
+		{"versioned item", models.Operation{Method: "GET", Path: "/api/v1/users/{id}"}, models.KindGet},
+		{"nested create", models.Operation{Method: "POST", Path: "/users/{id}/posts"}, models.KindCreate},
+		{"unidentified plural list", models.Operation{Method: "GET", Path: "/users/{id}/posts"}, models.KindList},
+		{"grpc custom method", models.Operation{Method: "POST", Path: "/tasks/{id}:move"}, models.KindAction},
+		{"grpc collection method", models.Operation{Method: "POST", Path: "/tasks:scaffold"}, models.KindAction},
+		{"grpc batch method", models.Operation{Method: "POST", Path: "/tasks:batchGet"}, models.KindBatch},
+		{"batch path", models.Operation{Method: "POST", Path: "/users/batch"}, models.KindBatch},
+		{"bulk delete", models.Operation{Method: "DELETE", Path: "/users"}, models.KindBatch},
+		{"identified collection with object response", models.Operation{Method: "GET", Path: "/settings", Responses: objectResponse}, models.KindList},
+		{"unidentified singleton read", models.Operation{Method: "GET", Path: "/preferences", Responses: objectResponse}, models.KindGet},
+		{"array create", models.Operation{Method: "POST", Path: "/users", RequestBody: arrayBody}, models.KindBatch},
+		{"action", models.Operation{Method: "POST", Path: "/users/{id}/activate"}, models.KindAction},
+		{"login", models.Operation{Method: "POST", Path: "/auth/login"}, models.KindAction},
+		{"singleton", models.Operation{Method: "GET", Path: "/users/{id}/profile"}, models.KindGet},
+		{"singleton update", models.Operation{Method: "PUT", Path: "/users/{id}/profile"}, models.KindReplace},
+		{"array response", models.Operation{Method: "GET", Path: "/search", Responses: arrayResponse}, models.KindList},
+		{"paginated response", models.Operation{Method: "GET", Path: "/tasks/{id}/blockedBy", Responses: pagedResponse}, models.KindList},
+		{"grpc-gateway list", models.Operation{Method: "GET", Path: "/v1/projects/{projectId}/activities", Responses: namedResponse}, models.KindList},
+		{"grpc-gateway top-level list", models.Operation{Method: "GET", Path: "/v1/activities", Responses: namedResponse}, models.KindList},
+		{"array next to a page token", models.Operation{Method: "GET", Path: "/v1/releases", Responses: tokenResponse}, models.KindList},
+		{"options", models.Operation{Method: "OPTIONS", Path: "/users"}, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ra.classifyOperation(tt.op, identified); got != tt.expected {
+				t.Errorf("classifyOperation(%s %s) = %q, expected %q", tt.op.Method, tt.op.Path, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestExtractResourcesClassifiesOperations(t *testing.T) {
+	objectResponse := map[string]parser.Response{"200": {Content: parser.Content{
+		"application/json": {Schema: &parser.Schema{Type: "object", Properties: map[string]parser.Schema{"theme": {Type: "string"}}}},
+	}}}
+	spec := &parser.OpenAPISpec{
+		Paths: map[string]parser.PathItem{
+			"/settings":    {Get: &parser.Operation{Responses: objectResponse}, Put: &parser.Operation{}, Patch: &parser.Operation{}},
+			"/tasks":       {Get: &parser.Operation{}, Post: &parser.Operation{}, Delete: &parser.Operation{}},
+			"/tasks/{id}":  {Get: &parser.Operation{}, Patch: &parser.Operation{}},
+			"/tasks:purge": {Post: &parser.Operation{}},
+
+			"/tasks/{id}:publish": {Post: &parser.Operation{}},
+			"/tasks:batch-get":    {Get: &parser.Operation{}},
+		},
+	}
+
+	// /settings is a singleton: it reads one object and cannot be listed
+	// or added to, so its writes are not batches
+	expected := map[string]string{
+		"GET /settings":     models.KindGet,
+		"PUT /settings":     models.KindReplace,
+		"PATCH /settings":   models.KindUpdate,
+		"GET /tasks":        models.KindList,
+		"POST /tasks":       models.KindCreate,
+		"DELETE /tasks":     models.KindBatch,
+		"GET /tasks/{id}":   models.KindGet,
+		"PATCH /tasks/{id}": models.KindUpdate,
+		"POST /tasks:purge": models.KindAction,
+
+		"POST /tasks/{id}:publish": models.KindAction,
+		"GET /tasks:batch-get":     models.KindBatch,
+	}
+
+	// Custom methods stay on the resource they act on
+	resources := NewResourceAnalyzer().ExtractResources(spec)
+	var names []string
+	for _, resource := range resources {
+		names = append(names, resource.Name)
+	}
+	slices.Sort(names)
+	if !slices.Equal(names, []string{"settings", "tasks"}) {
+		t.Errorf("Expected resources [settings tasks], got %v", names)
+	}
+
+	for _, resource := range resources {
+		for _, op := range resource.Operations {
+			key := op.Method + " " + op.Path
+			if op.Kind != expected[key] {
+				t.Errorf("Expected %s to be %q, got %q", key, expected[key], op.Kind)
+			}
+			if crud := op.Kind != models.KindAction && op.Kind != models.KindBatch; op.IsResourceOp != crud {
+				t.Errorf("Expected %s IsResourceOp = %v, got %v", key, crud, op.IsResourceOp)
+			}
+		}
+	}
+}
@@ -0,0 +1,294 @@
+package analyzer
+
+import (
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/orchard9/api-godoc/internal/parser"
+	"github.com/orchard9/api-godoc/pkg/models"
+)
+
+// stateFieldNames are properties that hold a resource's state, in order of preference
+var stateFieldNames = []string{"status", "state", "phase", "stage", "lifecycle"}
+
+// placeholderStates are enum values that mean "not set" rather than a state
+var placeholderStates = map[string]bool{"unspecified": true, "unknown": true, "none": true, "null": true, "unset": true}
+
+// LifecycleInferrer infers resource state machines from status enums and action endpoints
+type LifecycleInferrer struct {
+	inflector         *Inflector
+	pathVariableRegex *regexp.Regexp
+}
+
+// NewLifecycleInferrer creates a new lifecycle inferrer
+func NewLifecycleInferrer() *LifecycleInferrer {
+	return &LifecycleInferrer{
+		inflector:         NewInflector(),
+		pathVariableRegex: regexp.MustCompile(`\{[^}]+\}`),
+	}
+}
+
+// Infer sets the lifecycle of every resource whose bound schema has a state
+// enum. Schemas are the flattened representation schemas returned by
+// SchemaBinder.Bind, keyed by resource name.
+func (li *LifecycleInferrer) Infer(resources []models.Resource, schemas map[string]*parser.Schema, spec *parser.OpenAPISpec) {
+	resolver := newRefResolver(spec)
+
+	for i := range resources {
+		resource := &resources[i]
+		resource.Lifecycle = nil
+
+		schema, ok := schemas[resource.Name]
+		if !ok {
+			continue
+		}
+		field, states, initial := stateEnum(schema, resolver)
+		if len(states) < 2 {
+			continue
+		}
+
+		// A status without actions that change it is just a field
+		lifecycle := &models.Lifecycle{Field: field, States: states, Initial: initial}
+		for _, itemPath := range li.itemPaths(*resource) {
+			lifecycle.Transitions = append(lifecycle.Transitions, li.transitions(itemPath, states, spec)...)
+		}
+		if len(lifecycle.Transitions) > 0 {
+			resource.Lifecycle = lifecycle
+		}
+	}
+}
+
+// stateEnum finds the state property of a schema and returns its name, its
+// states without placeholders and the initial state
+func stateEnum(schema *parser.Schema, resolver refResolver) (string, []string, string) {
+	properties, _ := collectProperties(schema, resolver)
+
+	var fields []string
+	for _, name := range stateFieldNames {
+		if _, ok := properties[name]; ok {
+			fields = append(fields, name)
+		}
+	}
+	// Qualified names such as orderStatus come after the plain ones
+	for _, name := range sortedKeys(properties) {
+		words := splitWords(name)
+		if len(words) > 1 && (words[len(words)-1] == "status" || words[len(words)-1] == "state") {
+			fields = append(fields, name)
+		}
+	}
+
+	for _, field := range fields {
+		prop := properties[field]
+		resolved := resolver.schema(&prop)
+		if resolved == nil || len(resolved.Enum) == 0 {
+			continue
+		}
+
+		// Placeholders such as STATUS_UNSPECIFIED often lack the prefix of the
+		// other values, so they are dropped before the prefix is found
+		var values []string
+		for _, value := range resolved.Enum {
+			if s, ok := value.(string); ok && !isPlaceholderState(s) {
+				values = append(values, s)
+			}
+		}
+		if len(values) == 0 {
+			continue
+		}
+		prefix := commonEnumPrefix(values)
+
+		var states []string
+		for _, value := range values {
+			states = appendUnique(states, strings.TrimPrefix(value, prefix))
+		}
+
+		initial := states[0]
+		if def, ok := resolved.Default.(string); ok {
+			if state := strings.TrimPrefix(def, prefix); slices.Contains(states, state) {
+				initial = state
+			}
+		}
+		return field, states, initial
+	}
+
+	return "", nil, ""
+}
+
+// isPlaceholderState reports whether an enum value means "not set", e.g. ORDER_STATUS_UNSPECIFIED
+func isPlaceholderState(value string) bool {
+	words := splitWords(value)
+	return len(words) == 0 || placeholderStates[words[len(words)-1]]
+}
+
+// commonEnumPrefix returns the prefix shared by all enum values up to an
+// underscore, e.g. "ORDER_STATUS_" for protobuf enums
+func commonEnumPrefix(values []string) string {
+	if len(values) < 2 {
+		return ""
+	}
+	prefix := values[0]
+	for _, value := range values[1:] {
+		for !strings.HasPrefix(value, prefix) {
+			prefix = prefix[:len(prefix)-1]
+		}
+	}
+	prefix = prefix[:strings.LastIndex(prefix, "_")+1]
+	for _, value := range values {
+		if value == prefix {
+			return ""
+		}
+	}
+	return prefix
+}
+
+// itemPaths returns the paths that address a single item of a resource, with
+// variables reduced to {} so that /orders/{id} and /orders/{orderId} match
+func (li *LifecycleInferrer) itemPaths(resource models.Resource) []string {
+	var paths []string
+	for _, op := range resource.Operations {
+		switch op.Kind {
+		case models.KindGet, models.KindReplace, models.KindUpdate, models.KindDelete:
+			if strings.HasSuffix(op.Path, "}") {
+				paths = appendUnique(paths, li.pathVariableRegex.ReplaceAllString(op.Path, "{}"))
+			}
+		}
+	}
+	sort.Strings(paths)
+	return paths
+}
+
+// transitions finds the action operations on an item path such as
+// /orders/{id}:cancel or /orders/{id}/approve
+func (li *LifecycleInferrer) transitions(itemPath string, states []string, spec *parser.OpenAPISpec) []models.Transition {
+	var transitions []models.Transition
+
+	for _, path := range sortedKeys(spec.Paths) {
+		normalized := li.pathVariableRegex.ReplaceAllString(path, "{}")
+		action, custom := "", false
+		switch {
+		case strings.HasPrefix(normalized, itemPath+":"):
+			action, custom = normalized[len(itemPath)+1:], true
+		case strings.HasPrefix(normalized, itemPath+"/"):
+			action = normalized[len(itemPath)+1:]
+		default:
+			continue
+		}
+		words := splitWords(action)
+		if len(words) == 0 || strings.ContainsAny(action, "/{:") {
+			continue
+		}
+
+		for _, mo := range pathItemOperations(spec.Paths[path]) {
+			if mo.method != "POST" && mo.method != "PUT" && mo.method != "PATCH" {
+				continue
+			}
+			summary := mo.operation.Summary
+			if summary == "" {
+				summary = mo.operation.Description
+			}
+			to, from := transitionStates(action, summary, states)
+			// A sub-collection such as /orders/{id}/items is only a transition
+			// when it names a target state
+			if to == "" && !custom && li.inflector.Singular(words[len(words)-1]) != words[len(words)-1] {
+				continue
+			}
+			transitions = append(transitions, models.Transition{
+				Action:  action,
+				From:    from,
+				To:      to,
+				Method:  mo.method,
+				Path:    path,
+				Summary: summary,
+			})
+		}
+	}
+
+	return transitions
+}
+
+// transitionStates derives the target and source states of an action from
+// its verb ("cancel" leads to "cancelled") and its summary ("moves the order
+// from pending to shipped")
+func transitionStates(action, summary string, states []string) (string, []string) {
+	to := ""
+	if words := splitWords(action); len(words) > 0 {
+		forms := verbForms(words[0])
+		for _, state := range states {
+			stateWords := splitWords(state)
+			if len(stateWords) > 0 && slices.Contains(forms, stateWords[len(stateWords)-1]) {
+				to = state
+				break
+			}
+		}
+	}
+
+	text := strings.FieldsFunc(strings.ToLower(summary), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	var from, mentioned []string
+	for _, state := range states {
+		phrase := splitWords(state)
+		for i := 0; i+len(phrase) <= len(text); i++ {
+			if !slices.Equal(text[i:i+len(phrase)], phrase) {
+				continue
+			}
+			switch precedingWord(text[:i], "to", "into", "from") {
+			case "to", "into":
+				if to == "" {
+					to = state
+				}
+			case "from":
+				from = appendUnique(from, state)
+			default:
+				mentioned = appendUnique(mentioned, state)
+			}
+		}
+	}
+
+	// Other states named alongside a known target are where the action starts
+	if to != "" && len(from) == 0 {
+		for _, state := range mentioned {
+			if state != to {
+				from = append(from, state)
+			}
+		}
+	}
+	return to, from
+}
+
+// precedingWord returns which of the given words appears within the three
+// words before a position, nearest first, or "" when none does
+func precedingWord(words []string, candidates ...string) string {
+	for i := len(words) - 1; i >= 0 && i >= len(words)-3; i-- {
+		if slices.Contains(candidates, words[i]) {
+			return words[i]
+		}
+	}
+	return ""
+}
+
+// verbForms returns the forms a state may take after a verb: publish →
+// published, cancel → cancelled, verify → verified, activate → active
+func verbForms(verb string) []string {
+	forms := []string{verb, verb + "ed", verb + "d", verb + "ing"}
+	n := len(verb)
+	if n < 3 {
+		return forms
+	}
+	if strings.HasSuffix(verb, "e") {
+		forms = append(forms, verb[:n-1]+"ing")
+	}
+	if strings.HasSuffix(verb, "y") {
+		forms = append(forms, verb[:n-1]+"ied")
+	}
+	if strings.HasSuffix(verb, "ate") {
+		forms = append(forms, verb[:n-3]+"e")
+	}
+	if !isVowel(verb[n-1]) && isVowel(verb[n-2]) && !isVowel(verb[n-3]) {
+		forms = append(forms, verb+verb[n-1:]+"ed")
+	}
+	return forms
+}
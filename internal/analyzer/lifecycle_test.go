@@ -0,0 +1,182 @@
+package analyzer
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/orchard9/api-godoc/internal/parser"
+	"github.com/orchard9/api-godoc/pkg/models"
+)
+
+func lifecycleTestSpec() *parser.OpenAPISpec {
+	str := parser.Schema{Type: "string"}
+	return &parser.OpenAPISpec{
+		Paths: map[string]parser.PathItem{
+			"/orders": {
+				Get: &parser.Operation{Responses: jsonResponse(&parser.Schema{Type: "array", Items: ref("Order")})},
+			},
+			"/orders/{id}": {
+				Get: &parser.Operation{Responses: jsonResponse(ref("Order"))},
+			},
+			"/orders/{orderId}:cancel": {
+				Post: &parser.Operation{Summary: "Cancel a pending order"},
+			},
+			"/orders/{id}/ship": {
+				Post: &parser.Operation{Summary: "Marks a paid order as shipped"},
+			},
+			"/orders/{orderId}:refund": {
+				Post: &parser.Operation{Summary: "Refund an order"},
+			},
+			// A sub-collection, not a transition
+			"/orders/{id}/items": {
+				Post: &parser.Operation{Summary: "Add an item"},
+			},
+			// A status without actions
+			"/tickets/{id}": {
+				Get: &parser.Operation{Responses: jsonResponse(ref("Ticket"))},
+			},
+		},
+		Components: &parser.Components{
+			Schemas: map[string]parser.Schema{
+				"Order": {Type: "object", Properties: map[string]parser.Schema{
+					"id":     str,
+					"status": *ref("OrderStatus"),
+				}},
+				"OrderStatus": {Type: "string", Default: "ORDER_STATUS_UNSPECIFIED", Enum: []interface{}{
+					"ORDER_STATUS_UNSPECIFIED", "ORDER_STATUS_PENDING", "ORDER_STATUS_PAID",
+					"ORDER_STATUS_SHIPPED", "ORDER_STATUS_CANCELLED",
+				}},
+				"Ticket": {Type: "object", Properties: map[string]parser.Schema{
+					"state": {Type: "string", Enum: []interface{}{"open", "closed"}},
+				}},
+			},
+		},
+	}
+}
+
+func TestLifecycleInferrerInfer(t *testing.T) {
+	spec := lifecycleTestSpec()
+	resources := NewResourceAnalyzer().ExtractResources(spec)
+	schemas := NewSchemaBinder().Bind(resources, spec)
+	NewLifecycleInferrer().Infer(resources, schemas, spec)
+
+	lifecycles := make(map[string]*models.Lifecycle)
+	for _, resource := range resources {
+		lifecycles[resource.Name] = resource.Lifecycle
+	}
+
+	if lifecycles["tickets"] != nil {
+		t.Errorf("Expected no lifecycle for a status without actions, got %+v", lifecycles["tickets"])
+	}
+
+	lifecycle := lifecycles["orders"]
+	if lifecycle == nil {
+		t.Fatal("Expected a lifecycle for orders")
+	}
+	if lifecycle.Field != "status" {
+		t.Errorf("Expected field status, got %s", lifecycle.Field)
+	}
+	if expected := []string{"PENDING", "PAID", "SHIPPED", "CANCELLED"}; !reflect.DeepEqual(lifecycle.States, expected) {
+		t.Errorf("Expected states %v, got %v", expected, lifecycle.States)
+	}
+	if lifecycle.Initial != "PENDING" {
+		t.Errorf("Expected initial state PENDING, got %s", lifecycle.Initial)
+	}
+
+	expected := []models.Transition{
+		{Action: "ship", From: []string{"PAID"}, To: "SHIPPED", Method: "POST", Path: "/orders/{id}/ship", Summary: "Marks a paid order as shipped"},
+		{Action: "cancel", From: []string{"PENDING"}, To: "CANCELLED", Method: "POST", Path: "/orders/{orderId}:cancel", Summary: "Cancel a pending order"},
+		{Action: "refund", Method: "POST", Path: "/orders/{orderId}:refund", Summary: "Refund an order"},
+	}
+	if !reflect.DeepEqual(lifecycle.Transitions, expected) {
+		t.Errorf("Expected transitions %+v, got %+v", expected, lifecycle.Transitions)
+	}
+}
+
+func TestTransitionStates(t *testing.T) {
+	tests := []struct {
+		name         string
+		action       string
+		summary      string
+		states       []string
+		expectedTo   string
+		expectedFrom []string
+	}{
+		{
+			name:       "past tense",
+			action:     "publish",
+			states:     []string{"draft", "published"},
+			expectedTo: "published",
+		},
+		{
+			name:       "doubled consonant",
+			action:     "cancel",
+			states:     []string{"active", "cancelled"},
+			expectedTo: "cancelled",
+		},
+		{
+			name:       "adjective",
+			action:     "activate",
+			states:     []string{"inactive", "active"},
+			expectedTo: "active",
+		},
+		{
+			name:       "y to ied",
+			action:     "verifyEmail",
+			states:     []string{"PENDING", "VERIFIED"},
+			expectedTo: "VERIFIED",
+		},
+		{
+			name:         "summary from and to",
+			action:       "move",
+			summary:      "Moves the task from todo to in-progress",
+			states:       []string{"todo", "in_progress", "done"},
+			expectedTo:   "in_progress",
+			expectedFrom: []string{"todo"},
+		},
+		{
+			name:       "summary target only",
+			action:     "results",
+			summary:    "Submits deliverables and transitions to completed",
+			states:     []string{"REQUESTED", "COMPLETED"},
+			expectedTo: "COMPLETED",
+		},
+		{
+			name:    "unknown",
+			action:  "refund",
+			summary: "Refund an order",
+			states:  []string{"PAID", "SHIPPED"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			to, from := transitionStates(tt.action, tt.summary, tt.states)
+			if to != tt.expectedTo {
+				t.Errorf("Expected to %q, got %q", tt.expectedTo, to)
+			}
+			if !reflect.DeepEqual(from, tt.expectedFrom) {
+				t.Errorf("Expected from %v, got %v", tt.expectedFrom, from)
+			}
+		})
+	}
+}
+
+func TestCommonEnumPrefix(t *testing.T) {
+	tests := []struct {
+		values   []string
+		expected string
+	}{
+		{[]string{"ORDER_STATUS_PENDING", "ORDER_STATUS_PAID"}, "ORDER_STATUS_"},
+		{[]string{"PENDING_REVIEW", "PENDING_PAYMENT"}, "PENDING_"},
+		{[]string{"PENDING", "PENDING_REVIEW"}, ""},
+		{[]string{"active", "archived"}, ""},
+		{[]string{"ORDER_STATUS_PAID"}, ""},
+	}
+
+	for _, tt := range tests {
+		if got := commonEnumPrefix(tt.values); got != tt.expected {
+			t.Errorf("commonEnumPrefix(%v) = %q, expected %q", tt.values, got, tt.expected)
+		}
+	}
+}
@@ -0,0 +1,175 @@
+package analyzer
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/orchard9/api-godoc/internal/parser"
+	"github.com/orchard9/api-godoc/pkg/models"
+)
+
+// hypermediaProperties are schema properties whose own properties name
+// related resources: HAL _links and _embedded, JSON:API links and
+// relationships
+var hypermediaProperties = []string{"_links", "_embedded", "links", "relationships"}
+
+// ignoredLinkRelations are link relations that do not lead to another resource
+var ignoredLinkRelations = []string{"self", "curies", "first", "prev", "previous", "next", "last", "related", "find"}
+
+// detectLinkRelationships adds navigable_via relationships from OpenAPI Link
+// objects and from hypermedia links in resource schemas. Link evidence also
+// corroborates the relationships through the same property, e.g. a customer
+// link the customerId reference it follows.
+func (rd *RelationshipDetector) detectLinkRelationships(resourceMap map[string]*models.Resource, spec *parser.OpenAPISpec) {
+	rd.detectOpenAPILinks(resourceMap, spec)
+	rd.detectHypermediaLinks(resourceMap, spec)
+}
+
+// detectOpenAPILinks follows response links to the operation they target,
+// by operationId or operationRef, and records their parameter mappings
+func (rd *RelationshipDetector) detectOpenAPILinks(resourceMap map[string]*models.Resource, spec *parser.OpenAPISpec) {
+	owners := operationOwners(resourceMap)
+	operationIDs := make(map[string]string)
+	for _, path := range sortedPaths(spec.Paths) {
+		for _, op := range pathItemOperations(spec.Paths[path]) {
+			if op.operation.OperationID != "" {
+				operationIDs[op.operation.OperationID] = op.method + " " + path
+			}
+		}
+	}
+
+	for _, path := range sortedPaths(spec.Paths) {
+		for _, op := range pathItemOperations(spec.Paths[path]) {
+			operation := op.method + " " + path
+			source := owners[operation]
+			if source == nil {
+				continue
+			}
+			for _, code := range sortedKeys(op.operation.Responses) {
+				response := resolveResponse(spec, op.operation.Responses[code])
+				for _, name := range sortedKeys(response.Links) {
+					link := resolveLink(spec, response.Links[name])
+					targetOperation := operationIDs[link.OperationId]
+					if link.OperationId == "" {
+						targetOperation = operationFromRef(link.OperationRef)
+					}
+					target := owners[targetOperation]
+					if target == nil || target.Name == source.Name {
+						continue
+					}
+
+					evidence := newEvidence(models.EvidenceLink, fmt.Sprintf("%s responses/%s/links/%s", operation, code, name))
+					rel := rd.addRelationship(resourceMap, source.Name, target.Name, "navigable_via", name, "", false, evidence)
+					if !slices.Contains(rel.Operations, targetOperation) {
+						rel.Operations = append(rel.Operations, targetOperation)
+					}
+					for parameter, expression := range link.Parameters {
+						if rel.Parameters == nil {
+							rel.Parameters = make(map[string]string)
+						}
+						rel.Parameters[parameter] = fmt.Sprint(expression)
+					}
+				}
+			}
+		}
+	}
+}
+
+// detectHypermediaLinks reads HAL _links and _embedded and JSON:API links
+// and relationships from resource schemas. Each relation names its target
+// resource; embedded relations may also refer to its schema.
+func (rd *RelationshipDetector) detectHypermediaLinks(resourceMap map[string]*models.Resource, spec *parser.OpenAPISpec) {
+	if spec.Components == nil {
+		return
+	}
+
+	for _, schemaName := range sortedKeys(spec.Components.Schemas) {
+		source := rd.findResource(resourceMap, rd.schemaToResourceName(schemaName))
+		if source == nil {
+			continue
+		}
+		schema := spec.Components.Schemas[schemaName]
+		for _, property := range hypermediaProperties {
+			links, ok := schema.Properties[property]
+			if !ok {
+				continue
+			}
+			links = resolveSchema(spec, links)
+			for _, relation := range sortedKeys(links.Properties) {
+				if slices.Contains(ignoredLinkRelations, strings.ToLower(relation)) {
+					continue
+				}
+				target := rd.findResource(resourceMap, relation)
+				linked := links.Properties[relation]
+				if linked.Type == "array" && linked.Items != nil {
+					linked = *linked.Items
+				}
+				if ref := schemaRef(linked); ref != "" && property == "_embedded" {
+					if embedded := rd.findResource(resourceMap, rd.schemaToResourceName(rd.extractSchemaNameFromRef(ref))); embedded != nil {
+						target = embedded
+					}
+				}
+				if target == nil || target.Name == source.Name {
+					continue
+				}
+
+				location := "#/components/schemas/" + schemaName + "/properties/" + property + "/properties/" + relation
+				evidence := newEvidence(models.EvidenceLink, location)
+				rd.addRelationship(resourceMap, source.Name, target.Name, "navigable_via", property+"."+relation, "", false, evidence)
+			}
+		}
+	}
+}
+
+// operationOwners indexes resources by their operations as "METHOD path"
+func operationOwners(resourceMap map[string]*models.Resource) map[string]*models.Resource {
+	owners := make(map[string]*models.Resource)
+	for _, resource := range resourceMap {
+		for _, op := range resource.Operations {
+			if _, exists := owners[op.Method+" "+op.Path]; !exists {
+				owners[op.Method+" "+op.Path] = resource
+			}
+		}
+	}
+	return owners
+}
+
+// resolveResponse follows a reference to components.responses
+func resolveResponse(spec *parser.OpenAPISpec, response parser.Response) parser.Response {
+	if name, ok := strings.CutPrefix(response.Ref, "#/components/responses/"); ok && spec.Components != nil {
+		return spec.Components.Responses[name]
+	}
+	return response
+}
+
+// resolveLink follows a reference to components.links
+func resolveLink(spec *parser.OpenAPISpec, link parser.Link) parser.Link {
+	if name, ok := strings.CutPrefix(link.Ref, "#/components/links/"); ok && spec.Components != nil {
+		return spec.Components.Links[name]
+	}
+	return link
+}
+
+// resolveSchema follows a reference to components.schemas
+func resolveSchema(spec *parser.OpenAPISpec, schema parser.Schema) parser.Schema {
+	if name, ok := strings.CutPrefix(schemaRef(schema), "#/components/schemas/"); ok && spec.Components != nil {
+		return spec.Components.Schemas[name]
+	}
+	return schema
+}
+
+// operationFromRef converts an operationRef such as
+// #/paths/~1users~1{userId}/get to "GET /users/{userId}"
+func operationFromRef(ref string) string {
+	_, pointer, found := strings.Cut(ref, "#/paths/")
+	if !found {
+		return ""
+	}
+	i := strings.LastIndex(pointer, "/")
+	if i < 0 {
+		return ""
+	}
+	path := strings.NewReplacer("~1", "/", "~0", "~").Replace(pointer[:i])
+	return strings.ToUpper(pointer[i+1:]) + " " + path
+}
@@ -0,0 +1,143 @@
+package analyzer
+
+import (
+	"reflect"
+	"slices"
+	"testing"
+
+	"github.com/orchard9/api-godoc/internal/parser"
+	"github.com/orchard9/api-godoc/pkg/models"
+)
+
+func TestLinkRelationships(t *testing.T) {
+	spec := &parser.OpenAPISpec{
+		Paths: map[string]parser.PathItem{
+			"/orders/{orderId}": {
+				Get: &parser.Operation{
+					OperationID: "getOrder",
+					Responses: map[string]parser.Response{
+						"200": {
+							Links: map[string]parser.Link{
+								"customer": {OperationId: "getCustomer", Parameters: map[string]interface{}{"customerId": "$response.body#/customerId"}},
+								"invoice":  {Ref: "#/components/links/OrderInvoice"},
+								"self":     {OperationId: "getOrder"},
+							},
+						},
+					},
+				},
+			},
+			"/customers/{customerId}": {Get: &parser.Operation{OperationID: "getCustomer"}},
+			"/invoices/{invoiceId}":   {Get: &parser.Operation{}},
+			"/products/{productId}":   {Get: &parser.Operation{}},
+			"/reviews":                {Get: &parser.Operation{}},
+			"/articles":               {Get: &parser.Operation{}},
+			"/authors":                {Get: &parser.Operation{}},
+		},
+		Components: &parser.Components{
+			Links: map[string]parser.Link{
+				"OrderInvoice": {OperationRef: "#/paths/~1invoices~1{invoiceId}/get"},
+			},
+			Schemas: map[string]parser.Schema{
+				"Order": {
+					Type: "object",
+					Properties: map[string]parser.Schema{
+						"customerId": {Type: "string"},
+					},
+				},
+				"Product": {
+					Type: "object",
+					Properties: map[string]parser.Schema{
+						"_links": {Type: "object", Properties: map[string]parser.Schema{
+							"self":    {Type: "object"},
+							"reviews": {Type: "object"},
+						}},
+						"_embedded": {Type: "object", Properties: map[string]parser.Schema{
+							"top": {Type: "array", Items: &parser.Schema{Ref: "#/components/schemas/Review"}},
+						}},
+					},
+				},
+				"Review": {Type: "object"},
+				"Article": {
+					Type: "object",
+					Properties: map[string]parser.Schema{
+						"relationships": {Ref: "#/components/schemas/ArticleRelationships"},
+					},
+				},
+				"ArticleRelationships": {
+					Type: "object",
+					Properties: map[string]parser.Schema{
+						"author": {Type: "object"},
+					},
+				},
+			},
+		},
+	}
+
+	resources := NewResourceAnalyzer().ExtractResources(spec)
+	NewRelationshipDetector().DetectRelationships(resources, spec)
+
+	type key struct{ from, to, via string }
+	actual := make(map[key]models.Relationship)
+	for _, resource := range resources {
+		for _, rel := range resource.Relationships {
+			actual[key{resource.Name, rel.Resource, rel.Via}] = rel
+		}
+	}
+
+	tests := []struct {
+		from, to, via string
+		source        string
+		operations    []string
+		parameters    map[string]string
+	}{
+		{"orders", "customers", "customer", "GET /orders/{orderId} responses/200/links/customer",
+			[]string{"GET /customers/{customerId}"}, map[string]string{"customerId": "$response.body#/customerId"}},
+		{"orders", "invoices", "invoice", "GET /orders/{orderId} responses/200/links/invoice",
+			[]string{"GET /invoices/{invoiceId}"}, nil},
+		{"products", "reviews", "_links.reviews", "#/components/schemas/Product/properties/_links/properties/reviews", nil, nil},
+		{"products", "reviews", "_embedded.top", "#/components/schemas/Product/properties/_embedded/properties/top", nil, nil},
+		{"articles", "authors", "relationships.author", "#/components/schemas/Article/properties/relationships/properties/author", nil, nil},
+	}
+
+	for _, tt := range tests {
+		rel, ok := actual[key{tt.from, tt.to, tt.via}]
+		if !ok {
+			t.Errorf("Missing link %s -> %s via %s", tt.from, tt.to, tt.via)
+			continue
+		}
+		if rel.Type != "navigable_via" || !slices.Contains(rel.Evidence, newEvidence(models.EvidenceLink, tt.source)) {
+			t.Errorf("%s -> %s via %s: expected navigable_via with link evidence %s, got %+v", tt.from, tt.to, tt.via, tt.source, rel)
+		}
+		if !reflect.DeepEqual(rel.Operations, tt.operations) || !reflect.DeepEqual(rel.Parameters, tt.parameters) {
+			t.Errorf("%s -> %s via %s: expected %v %v, got %v %v", tt.from, tt.to, tt.via, tt.operations, tt.parameters, rel.Operations, rel.Parameters)
+		}
+	}
+
+	// Links corroborate the foreign key they follow
+	if rel := actual[key{"orders", "customers", "customerId"}]; rel.Confidence != 0.95 {
+		t.Errorf("Expected the customerId reference to be corroborated by the link, got %+v", rel)
+	}
+	for k := range actual {
+		if k.via == "self" || k.via == "_links.self" {
+			t.Errorf("Expected self links to be ignored, got %+v", k)
+		}
+	}
+}
+
+func TestOperationFromRef(t *testing.T) {
+	tests := []struct {
+		ref      string
+		expected string
+	}{
+		{"#/paths/~1users~1{userId}/get", "GET /users/{userId}"},
+		{"https://example.com/openapi.json#/paths/~1orders/post", "POST /orders"},
+		{"#/components/links/Other", ""},
+		{"", ""},
+	}
+
+	for _, tt := range tests {
+		if got := operationFromRef(tt.ref); got != tt.expected {
+			t.Errorf("operationFromRef(%q) = %q, want %q", tt.ref, got, tt.expected)
+		}
+	}
+}
@@ -0,0 +1,360 @@
+package analyzer
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/orchard9/api-godoc/internal/config"
+	"github.com/orchard9/api-godoc/pkg/models"
+)
+
+// defaultIrregulars maps singular nouns to plurals that the suffix rules get wrong
+var defaultIrregulars = map[string]string{
+	"person":    "people",
+	"child":     "children",
+	"man":       "men",
+	"woman":     "women",
+	"mouse":     "mice",
+	"goose":     "geese",
+	"tooth":     "teeth",
+	"foot":      "feet",
+	"ox":        "oxen",
+	"leaf":      "leaves",
+	"knife":     "knives",
+	"life":      "lives",
+	"wife":      "wives",
+	"half":      "halves",
+	"shelf":     "shelves",
+	"wolf":      "wolves",
+	"thief":     "thieves",
+	"criterion": "criteria",
+	"matrix":    "matrices",
+	"vertex":    "vertices",
+	"cache":     "caches",
+	"niche":     "niches",
+	"movie":     "movies",
+	"cookie":    "cookies",
+	"quiz":      "quizzes",
+}
+
+// defaultUncountables are nouns used identically in singular and plural
+var defaultUncountables = []string{
+	"data", "metadata", "information", "equipment", "news", "series",
+	"species", "feedback", "media", "analytics",
+}
+
+// Inflector converts English nouns between singular and plural forms
+type Inflector struct {
+	plurals     map[string]string
+	singulars   map[string]string
+	uncountable map[string]bool
+}
+
+// NewInflector creates an inflector with the built-in English rules
+func NewInflector() *Inflector {
+	in := &Inflector{
+		plurals:     make(map[string]string),
+		singulars:   make(map[string]string),
+		uncountable: make(map[string]bool),
+	}
+	for singular, plural := range defaultIrregulars {
+		in.AddIrregular(singular, plural)
+	}
+	in.AddUncountable(defaultUncountables...)
+	return in
+}
+
+// AddIrregular registers a singular/plural pair
+func (in *Inflector) AddIrregular(singular, plural string) {
+	singular, plural = strings.ToLower(singular), strings.ToLower(plural)
+	in.plurals[singular] = plural
+	in.singulars[plural] = singular
+}
+
+// AddUncountable registers words that have no distinct plural
+func (in *Inflector) AddUncountable(words ...string) {
+	for _, word := range words {
+		in.uncountable[strings.ToLower(word)] = true
+	}
+}
+
+// Plural returns the plural form of a lowercase word
+func (in *Inflector) Plural(word string) string {
+	if word == "" || in.uncountable[word] {
+		return word
+	}
+	if plural, ok := in.plurals[word]; ok {
+		return plural
+	}
+	if _, ok := in.singulars[word]; ok {
+		return word
+	}
+
+	switch {
+	case strings.HasSuffix(word, "is"):
+		return strings.TrimSuffix(word, "is") + "es"
+	case strings.HasSuffix(word, "s"), strings.HasSuffix(word, "x"), strings.HasSuffix(word, "z"),
+		strings.HasSuffix(word, "ch"), strings.HasSuffix(word, "sh"):
+		return word + "es"
+	case strings.HasSuffix(word, "y") && len(word) > 1 && !isVowel(word[len(word)-2]):
+		return strings.TrimSuffix(word, "y") + "ies"
+	}
+	return word + "s"
+}
+
+// Singular returns the singular form of a lowercase word
+func (in *Inflector) Singular(word string) string {
+	if word == "" || in.uncountable[word] {
+		return word
+	}
+	if singular, ok := in.singulars[word]; ok {
+		return singular
+	}
+	if _, ok := in.plurals[word]; ok {
+		return word
+	}
+
+	switch {
+	case strings.HasSuffix(word, "ies") && len(word) > 4:
+		return strings.TrimSuffix(word, "ies") + "y"
+	case strings.HasSuffix(word, "yses"):
+		return strings.TrimSuffix(word, "es") + "is"
+	case strings.HasSuffix(word, "sses"), strings.HasSuffix(word, "xes"), strings.HasSuffix(word, "zzes"),
+		strings.HasSuffix(word, "ches"), strings.HasSuffix(word, "shes"):
+		return strings.TrimSuffix(word, "es")
+	case strings.HasSuffix(word, "uses") && len(word) > 4 && !isVowel(word[len(word)-5]):
+		// statuses and buses, but not houses or causes
+		return strings.TrimSuffix(word, "es")
+	case strings.HasSuffix(word, "ss"), strings.HasSuffix(word, "us"), strings.HasSuffix(word, "is"):
+		return word
+	case strings.HasSuffix(word, "s") && len(word) > 1:
+		return strings.TrimSuffix(word, "s")
+	}
+	return word
+}
+
+// isVowel reports whether a byte is a lowercase vowel
+func isVowel(b byte) bool {
+	return strings.IndexByte("aeiou", b) >= 0
+}
+
+// NameNormalizer maps raw resource names to canonical names, so that
+// "user", "users" and "Users" or "apiKeys" and "api-keys" become one resource
+type NameNormalizer struct {
+	inflector    *Inflector
+	aliases      map[string]string // normalized key -> canonical name
+	displayNames map[string]string // canonical name -> display name
+}
+
+// NewNameNormalizer creates a normalizer with built-in rules and config overrides
+func NewNameNormalizer(naming config.NamingConfig) *NameNormalizer {
+	n := &NameNormalizer{
+		inflector:    NewInflector(),
+		aliases:      make(map[string]string),
+		displayNames: make(map[string]string),
+	}
+	for singular, plural := range naming.Irregular {
+		n.inflector.AddIrregular(singular, plural)
+	}
+	n.inflector.AddUncountable(naming.Uncountable...)
+	for alias, canonical := range naming.Aliases {
+		n.aliases[n.Key(alias)] = canonical
+	}
+	for name, display := range naming.DisplayNames {
+		n.displayNames[name] = display
+	}
+	return n
+}
+
+// Key reduces a name to its comparison form: kebab-case with the last word
+// of every nested part singular, e.g. "apiKeys" -> "api-key"
+func (n *NameNormalizer) Key(name string) string {
+	parts := strings.Split(name, nestedSeparator)
+	for i, part := range parts {
+		words := splitWords(part)
+		if len(words) > 0 {
+			words[len(words)-1] = n.inflector.Singular(words[len(words)-1])
+		}
+		parts[i] = strings.Join(words, "-")
+	}
+	return strings.Join(parts, nestedSeparator)
+}
+
+// pluralKey returns the plural form of a key
+func (n *NameNormalizer) pluralKey(key string) string {
+	parts := strings.Split(key, nestedSeparator)
+	for i, part := range parts {
+		words := strings.Split(part, "-")
+		words[len(words)-1] = n.inflector.Plural(words[len(words)-1])
+		parts[i] = strings.Join(words, "-")
+	}
+	return strings.Join(parts, nestedSeparator)
+}
+
+// kebab converts every nested part of a name to kebab-case
+func (n *NameNormalizer) kebab(name string) string {
+	parts := strings.Split(name, nestedSeparator)
+	for i, part := range parts {
+		parts[i] = normalizeGroupName(part)
+	}
+	return strings.Join(parts, nestedSeparator)
+}
+
+// DisplayName returns the configured display name or a title-cased form of the name
+func (n *NameNormalizer) DisplayName(name string) string {
+	if display, ok := n.displayNames[name]; ok {
+		return display
+	}
+
+	parts := strings.Split(name, nestedSeparator)
+	for i, part := range parts {
+		words := splitWords(part)
+		for j, word := range words {
+			words[j] = strings.ToUpper(word[:1]) + word[1:]
+		}
+		parts[i] = strings.Join(words, " ")
+	}
+	return strings.Join(parts, " / ")
+}
+
+// canonicalNames maps every raw name to its canonical name. Among the
+// variants of a key the plural form wins, then the singular, then the first
+// variant alphabetically; configured aliases override the choice.
+func (n *NameNormalizer) canonicalNames(names []string) map[string]string {
+	variants := make(map[string]map[string]bool)
+	for _, name := range names {
+		key := n.Key(name)
+		if variants[key] == nil {
+			variants[key] = make(map[string]bool)
+		}
+		variants[key][n.kebab(name)] = true
+	}
+
+	canonicalByKey := make(map[string]string, len(variants))
+	for key, forms := range variants {
+		if alias, ok := n.aliases[key]; ok {
+			canonicalByKey[key] = alias
+			continue
+		}
+		switch plural := n.pluralKey(key); {
+		case forms[plural]:
+			canonicalByKey[key] = plural
+		case forms[key]:
+			canonicalByKey[key] = key
+		default:
+			canonicalByKey[key] = sortedKeys(forms)[0]
+		}
+	}
+
+	canonical := make(map[string]string, len(names))
+	for _, name := range names {
+		canonical[name] = canonicalByKey[n.Key(name)]
+	}
+	return canonical
+}
+
+// sortedKeys returns map keys in lexical order
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// SetNameNormalizer changes how resource names are merged and displayed
+func (ra *ResourceAnalyzer) SetNameNormalizer(normalizer *NameNormalizer) {
+	if normalizer == nil {
+		normalizer = NewNameNormalizer(config.NamingConfig{})
+	}
+	ra.names = normalizer
+}
+
+// normalizeResources merges resources whose names differ only in plurality or
+// casing and rewrites hierarchy references to the canonical names
+func (ra *ResourceAnalyzer) normalizeResources(resourceMap map[string]*models.Resource) map[string]*models.Resource {
+	names := sortedKeys(resourceMap)
+	canonical := ra.names.canonicalNames(names)
+	rename := func(name string) string {
+		if c, ok := canonical[name]; ok {
+			return c
+		}
+		return name
+	}
+
+	merged := make(map[string]*models.Resource, len(resourceMap))
+	for _, name := range names {
+		resource := resourceMap[name]
+		target := rename(name)
+
+		existing := merged[target]
+		if existing == nil {
+			existing = &models.Resource{
+				Name:        target,
+				DisplayName: ra.names.DisplayName(target),
+				Description: ra.generateResourceDescription(target),
+				Operations:  []models.Operation{},
+			}
+			merged[target] = existing
+		}
+		if name != target {
+			existing.Aliases = appendUnique(existing.Aliases, name)
+		}
+
+		existing.Operations = mergeOperations(existing.Operations, resource.Operations)
+
+		// Hierarchy data of the variant that already has the canonical name wins
+		if existing.PathPattern == "" || name == target {
+			existing.PathPattern = resource.PathPattern
+			existing.Parent = rename(resource.Parent)
+			existing.Identifiers = nil
+			for _, id := range resource.Identifiers {
+				existing.Identifiers = append(existing.Identifiers, models.ResourceIdentifier{
+					Resource:  rename(id.Resource),
+					Parameter: id.Parameter,
+				})
+			}
+		}
+		for _, child := range resource.Children {
+			if child = rename(child); child != target {
+				existing.Children = appendUnique(existing.Children, child)
+			}
+		}
+	}
+
+	for _, resource := range merged {
+		if resource.Parent == resource.Name {
+			resource.Parent = ""
+		}
+		sort.Strings(resource.Aliases)
+		sort.Strings(resource.Children)
+	}
+
+	return merged
+}
+
+// mergeOperations appends operations that are not already present
+func mergeOperations(existing, additional []models.Operation) []models.Operation {
+	seen := make(map[string]bool, len(existing))
+	for _, op := range existing {
+		seen[op.Method+" "+op.Path] = true
+	}
+	for _, op := range additional {
+		if key := op.Method + " " + op.Path; !seen[key] {
+			seen[key] = true
+			existing = append(existing, op)
+		}
+	}
+	return existing
+}
+
+// appendUnique appends a value unless the slice already contains it
+func appendUnique(values []string, value string) []string {
+	for _, v := range values {
+		if v == value {
+			return values
+		}
+	}
+	return append(values, value)
+}
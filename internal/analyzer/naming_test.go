@@ -0,0 +1,159 @@
+package analyzer
+
+import (
+	"reflect"
+	"sort"
+	"testing"
+
+	"github.com/orchard9/api-godoc/internal/config"
+	"github.com/orchard9/api-godoc/internal/parser"
+)
+
+func TestInflector(t *testing.T) {
+	in := NewInflector()
+
+	tests := []struct {
+		singular string
+		plural   string
+	}{
+		{"user", "users"},
+		{"category", "categories"},
+		{"key", "keys"},
+		{"address", "addresses"},
+		{"box", "boxes"},
+		{"match", "matches"},
+		{"status", "statuses"},
+		{"bus", "buses"},
+		{"dish", "dishes"},
+		{"house", "houses"},
+		{"purchase", "purchases"},
+		{"size", "sizes"},
+		{"cause", "causes"},
+		{"analysis", "analyses"},
+		{"person", "people"},
+		{"child", "children"},
+		{"leaf", "leaves"},
+		{"cache", "caches"},
+		{"movie", "movies"},
+		{"metadata", "metadata"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.singular, func(t *testing.T) {
+			if got := in.Plural(tt.singular); got != tt.plural {
+				t.Errorf("Plural(%q) = %q, expected %q", tt.singular, got, tt.plural)
+			}
+			if got := in.Singular(tt.plural); got != tt.singular {
+				t.Errorf("Singular(%q) = %q, expected %q", tt.plural, got, tt.singular)
+			}
+			if got := in.Singular(tt.singular); got != tt.singular {
+				t.Errorf("Singular(%q) = %q, expected it unchanged", tt.singular, got)
+			}
+		})
+	}
+}
+
+func TestNameNormalizerKey(t *testing.T) {
+	n := NewNameNormalizer(config.NamingConfig{})
+
+	tests := map[string]string{
+		"users":          "user",
+		"Users":          "user",
+		"apiKeys":        "api-key",
+		"api-keys":       "api-key",
+		"api_keys":       "api-key",
+		"features/tasks": "feature/task",
+		"people":         "person",
+	}
+
+	for name, expected := range tests {
+		if got := n.Key(name); got != expected {
+			t.Errorf("Key(%q) = %q, expected %q", name, got, expected)
+		}
+	}
+}
+
+func TestNormalizeResources(t *testing.T) {
+	op := func() *parser.Operation { return &parser.Operation{} }
+	spec := &parser.OpenAPISpec{
+		Paths: map[string]parser.PathItem{
+			"/user/{id}":         {Get: op()},
+			"/users":             {Get: op()},
+			"/Users/{id}/avatar": {Put: op()},
+			"/apiKeys":           {Get: op()},
+			"/api-keys/{keyId}":  {Delete: op()},
+			"/person/{id}":       {Get: op()},
+		},
+	}
+
+	tests := []struct {
+		name          string
+		naming        config.NamingConfig
+		expected      []string
+		resource      string
+		aliases       []string
+		displayName   string
+		operationsLen int
+	}{
+		{
+			name:          "default rules",
+			expected:      []string{"api-keys", "avatar", "person", "users"},
+			resource:      "users",
+			aliases:       []string{"Users", "user"},
+			displayName:   "Users",
+			operationsLen: 3,
+		},
+		{
+			name:          "casing variants",
+			expected:      []string{"api-keys", "avatar", "person", "users"},
+			resource:      "api-keys",
+			aliases:       []string{"apiKeys"},
+			displayName:   "Api Keys",
+			operationsLen: 2,
+		},
+		{
+			name: "config overrides",
+			naming: config.NamingConfig{
+				Aliases:      map[string]string{"person": "members", "users": "members"},
+				DisplayNames: map[string]string{"members": "Team Members"},
+			},
+			expected:      []string{"api-keys", "avatar", "members"},
+			resource:      "members",
+			aliases:       []string{"Users", "person", "user", "users"},
+			displayName:   "Team Members",
+			operationsLen: 4,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ra := NewResourceAnalyzer()
+			ra.SetNameNormalizer(NewNameNormalizer(tt.naming))
+
+			resources := ra.ExtractResources(spec)
+			var names []string
+			for _, resource := range resources {
+				names = append(names, resource.Name)
+			}
+			sort.Strings(names)
+			if !reflect.DeepEqual(names, tt.expected) {
+				t.Fatalf("Expected resources %v, got %v", tt.expected, names)
+			}
+
+			for _, resource := range resources {
+				if resource.Name != tt.resource {
+					continue
+				}
+				if !reflect.DeepEqual(resource.Aliases, tt.aliases) {
+					t.Errorf("Expected aliases %v, got %v", tt.aliases, resource.Aliases)
+				}
+				if resource.DisplayName != tt.displayName {
+					t.Errorf("Expected display name %q, got %q", tt.displayName, resource.DisplayName)
+				}
+				if len(resource.Operations) != tt.operationsLen {
+					t.Errorf("Expected %d operations, got %d", tt.operationsLen, len(resource.Operations))
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,375 @@
+package analyzer
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/orchard9/api-godoc/internal/parser"
+	"github.com/orchard9/api-godoc/pkg/models"
+)
+
+// maxRefDepth bounds $ref chains so self-referencing schemas terminate
+const maxRefDepth = 8
+
+// refResolver resolves local $refs against a spec's components
+type refResolver struct {
+	components *parser.Components
+}
+
+// newRefResolver creates a resolver for the given spec
+func newRefResolver(spec *parser.OpenAPISpec) refResolver {
+	if spec == nil {
+		return refResolver{}
+	}
+	return refResolver{components: spec.Components}
+}
+
+// refName extracts the component name from a $ref
+func refName(ref string) string {
+	parts := strings.Split(ref, "/")
+	return parts[len(parts)-1]
+}
+
+// schema follows a schema $ref chain, returning the target or the input if unresolvable
+func (r refResolver) schema(s *parser.Schema) *parser.Schema {
+	for depth := 0; s != nil && s.Ref != "" && depth < maxRefDepth; depth++ {
+		if r.components == nil || !strings.HasPrefix(s.Ref, "#/components/schemas/") {
+			return s
+		}
+		target, ok := r.components.Schemas[refName(s.Ref)]
+		if !ok {
+			return s
+		}
+		s = &target
+	}
+	return s
+}
+
+// parameter resolves a parameter $ref
+func (r refResolver) parameter(p parser.Parameter) parser.Parameter {
+	if p.Ref == "" || r.components == nil {
+		return p
+	}
+	if target, ok := r.components.Parameters[refName(p.Ref)]; ok {
+		return target
+	}
+	return p
+}
+
+// requestBody resolves a request body $ref
+func (r refResolver) requestBody(b *parser.RequestBody) *parser.RequestBody {
+	if b == nil || b.Ref == "" || r.components == nil {
+		return b
+	}
+	if target, ok := r.components.RequestBodies[refName(b.Ref)]; ok {
+		return &target
+	}
+	return b
+}
+
+// response resolves a response $ref
+func (r refResolver) response(resp parser.Response) parser.Response {
+	if resp.Ref == "" || r.components == nil {
+		return resp
+	}
+	if target, ok := r.components.Responses[refName(resp.Ref)]; ok {
+		return target
+	}
+	return resp
+}
+
+// buildOperationDetails populates parameters, request body, responses and
+// security of an operation from its parsed definition
+func (ra *ResourceAnalyzer) buildOperationDetails(operation *models.Operation, op *parser.Operation, pathParams []parser.Parameter, spec *parser.OpenAPISpec) {
+	resolver := newRefResolver(spec)
+
+	operation.Parameters = ra.buildParameters(op.Parameters, pathParams, resolver)
+	operation.RequestBody = ra.buildRequestBody(resolver.requestBody(op.RequestBody), resolver)
+	operation.Responses = ra.buildResponses(op.Responses, resolver)
+
+	// Operation security overrides the global requirement, even when empty
+	security := op.Security
+	if security == nil && spec != nil {
+		security = spec.Security
+	}
+	operation.Security = ra.buildSecurity(security)
+}
+
+// buildParameters merges path-level and operation parameters; operation
+// parameters override path-level ones with the same name and location
+func (ra *ResourceAnalyzer) buildParameters(opParams, pathParams []parser.Parameter, resolver refResolver) []models.Parameter {
+	var params []models.Parameter
+	index := make(map[string]int)
+
+	for _, source := range [][]parser.Parameter{pathParams, opParams} {
+		for _, p := range source {
+			p = resolver.parameter(p)
+			if p.Name == "" {
+				continue
+			}
+
+			param := ra.buildParameter(p, resolver)
+			key := param.In + ":" + param.Name
+			if i, exists := index[key]; exists {
+				params[i] = param
+				continue
+			}
+			index[key] = len(params)
+			params = append(params, param)
+		}
+	}
+
+	return params
+}
+
+// buildParameter converts a single resolved parameter
+func (ra *ResourceAnalyzer) buildParameter(p parser.Parameter, resolver refResolver) models.Parameter {
+	param := models.Parameter{
+		Name:        p.Name,
+		In:          p.In,
+		Description: p.Description,
+		Required:    p.Required || p.In == "path",
+	}
+
+	schema := p.Schema
+	if schema == nil {
+		if _, mediaType, ok := chooseContent(p.Content); ok {
+			schema = mediaType.Schema
+		}
+	}
+
+	example := p.Example
+	if schema != nil {
+		resolved := resolver.schema(schema)
+		param.Type = typeLabel(schema, resolved)
+		param.Format = resolved.Format
+		if example == nil {
+			example = resolved.Example
+		}
+	}
+	param.Example = formatExample(example)
+
+	return param
+}
+
+// buildRequestBody converts a resolved request body
+func (ra *ResourceAnalyzer) buildRequestBody(body *parser.RequestBody, resolver refResolver) *models.RequestBody {
+	if body == nil || (body.Ref == "" && len(body.Content) == 0 && body.Description == "") {
+		return nil
+	}
+
+	result := &models.RequestBody{
+		Description: body.Description,
+		Required:    body.Required,
+	}
+
+	if contentType, mediaType, ok := chooseContent(body.Content); ok {
+		result.ContentType = contentType
+		result.Schema = ra.schemaToFieldType(mediaType.Schema, resolver, 0)
+	}
+
+	return result
+}
+
+// buildResponses converts responses sorted by status code
+func (ra *ResourceAnalyzer) buildResponses(responses map[string]parser.Response, resolver refResolver) []models.Response {
+	codes := make([]string, 0, len(responses))
+	for code := range responses {
+		codes = append(codes, code)
+	}
+	// "default" sorts after numeric codes
+	sort.Slice(codes, func(i, j int) bool {
+		if (codes[i] == "default") != (codes[j] == "default") {
+			return codes[j] == "default"
+		}
+		return codes[i] < codes[j]
+	})
+
+	result := make([]models.Response, 0, len(codes))
+	for _, code := range codes {
+		resp := resolver.response(responses[code])
+		response := models.Response{
+			StatusCode:  code,
+			Description: resp.Description,
+		}
+		if contentType, mediaType, ok := chooseContent(resp.Content); ok {
+			response.ContentType = contentType
+			response.Schema = ra.schemaToFieldType(mediaType.Schema, resolver, 0)
+		}
+		result = append(result, response)
+	}
+
+	return result
+}
+
+// buildSecurity flattens security requirements into "scheme" or "scheme (scope, ...)" labels
+func (ra *ResourceAnalyzer) buildSecurity(requirements []parser.SecurityRequirement) []string {
+	var security []string
+	seen := make(map[string]bool)
+
+	for _, requirement := range requirements {
+		names := make([]string, 0, len(requirement))
+		for name := range requirement {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		for _, name := range names {
+			label := name
+			if scopes := requirement[name]; len(scopes) > 0 {
+				label = fmt.Sprintf("%s (%s)", name, strings.Join(scopes, ", "))
+			}
+			if !seen[label] {
+				seen[label] = true
+				security = append(security, label)
+			}
+		}
+	}
+
+	return security
+}
+
+// schemaToFieldType converts a schema into a FieldType, resolving $refs.
+// Object properties are expanded one level deep to keep output compact.
+func (ra *ResourceAnalyzer) schemaToFieldType(schema *parser.Schema, resolver refResolver, depth int) *models.FieldType {
+	if schema == nil {
+		return nil
+	}
+
+	resolved := resolver.schema(schema)
+	fieldType := &models.FieldType{
+		Type:      typeLabel(schema, resolved),
+		Format:    resolved.Format,
+		Pattern:   resolved.Pattern,
+		MinLength: resolved.MinLength,
+		MaxLength: resolved.MaxLength,
+		Minimum:   resolved.Minimum,
+		Maximum:   resolved.Maximum,
+	}
+	if schema.Ref != "" {
+		fieldType.Reference = schema.Ref
+	}
+	for _, e := range resolved.Enum {
+		fieldType.Enum = append(fieldType.Enum, fmt.Sprint(e))
+	}
+
+	if resolved.Items != nil && depth < maxRefDepth {
+		fieldType.Items = ra.schemaToFieldType(resolved.Items, resolver, depth+1)
+	}
+
+	if depth == 0 {
+		properties, required := collectProperties(resolved, resolver)
+		names := make([]string, 0, len(properties))
+		for name := range properties {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		for _, name := range names {
+			prop := properties[name]
+			propType := ra.schemaToFieldType(&prop, resolver, depth+1)
+			fieldType.Properties = append(fieldType.Properties, models.Field{
+				Name:        name,
+				Type:        *propType,
+				Description: prop.Description,
+				Required:    required[name],
+				Example:     formatExample(prop.Example),
+				Deprecated:  prop.Deprecated,
+			})
+		}
+	}
+
+	return fieldType
+}
+
+// collectProperties gathers object properties, including those inherited through allOf
+func collectProperties(schema *parser.Schema, resolver refResolver) (map[string]parser.Schema, map[string]bool) {
+	properties := make(map[string]parser.Schema)
+	required := make(map[string]bool)
+
+	var collect func(s *parser.Schema, depth int)
+	collect = func(s *parser.Schema, depth int) {
+		s = resolver.schema(s)
+		if s == nil || depth > maxRefDepth {
+			return
+		}
+		for name, prop := range s.Properties {
+			properties[name] = prop
+		}
+		for _, name := range s.Required {
+			required[name] = true
+		}
+		for i := range s.AllOf {
+			collect(&s.AllOf[i], depth+1)
+		}
+	}
+	collect(schema, 0)
+
+	return properties, required
+}
+
+// typeLabel describes a schema type: the component name for refs, "array[T]" for arrays
+func typeLabel(schema, resolved *parser.Schema) string {
+	if schema.Ref != "" {
+		return refName(schema.Ref)
+	}
+	if resolved.Type == "array" && resolved.Items != nil {
+		items := resolved.Items
+		if items.Ref != "" {
+			return "array[" + refName(items.Ref) + "]"
+		}
+		if items.Type != "" {
+			return "array[" + items.Type + "]"
+		}
+	}
+	if resolved.Type == "" && (len(resolved.Properties) > 0 || len(resolved.AllOf) > 0) {
+		return "object"
+	}
+	return resolved.Type
+}
+
+// chooseContent picks the preferred media type: application/json, then any
+// +json type, then the first type alphabetically
+func chooseContent(content parser.Content) (string, parser.MediaType, bool) {
+	if len(content) == 0 {
+		return "", parser.MediaType{}, false
+	}
+
+	if mediaType, ok := content["application/json"]; ok {
+		return "application/json", mediaType, true
+	}
+
+	contentTypes := make([]string, 0, len(content))
+	for contentType := range content {
+		contentTypes = append(contentTypes, contentType)
+	}
+	sort.Strings(contentTypes)
+
+	for _, contentType := range contentTypes {
+		if strings.HasSuffix(contentType, "+json") {
+			return contentType, content[contentType], true
+		}
+	}
+
+	return contentTypes[0], content[contentTypes[0]], true
+}
+
+// formatExample renders an example value as a string
+func formatExample(example interface{}) string {
+	switch v := example.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case map[string]interface{}, []interface{}:
+		data, err := json.Marshal(v)
+		if err != nil {
+			return fmt.Sprint(v)
+		}
+		return string(data)
+	default:
+		return fmt.Sprint(v)
+	}
+}
@@ -0,0 +1,142 @@
+package analyzer
+
+import (
+	"testing"
+
+	"github.com/orchard9/api-godoc/internal/parser"
+)
+
+func operationTestSpec() *parser.OpenAPISpec {
+	return &parser.OpenAPISpec{
+		Security: []parser.SecurityRequirement{{"bearer": {}}},
+		Components: &parser.Components{
+			Schemas: map[string]parser.Schema{
+				"Base": {
+					Type:       "object",
+					Properties: map[string]parser.Schema{"id": {Type: "string"}},
+					Required:   []string{"id"},
+				},
+				"User": {
+					AllOf: []parser.Schema{
+						{Ref: "#/components/schemas/Base"},
+						{Properties: map[string]parser.Schema{"name": {Type: "string"}}},
+					},
+				},
+			},
+			Parameters: map[string]parser.Parameter{
+				"Limit": {Name: "limit", In: "query", Schema: &parser.Schema{Type: "integer", Example: 10}},
+			},
+			RequestBodies: map[string]parser.RequestBody{
+				"UserBody": {
+					Required: true,
+					Content: parser.Content{
+						"application/xml":  {Schema: &parser.Schema{Ref: "#/components/schemas/User"}},
+						"application/json": {Schema: &parser.Schema{Ref: "#/components/schemas/User"}},
+					},
+				},
+			},
+			Responses: map[string]parser.Response{
+				"NotFound": {Description: "Not found"},
+			},
+		},
+	}
+}
+
+func TestBuildOperationDetails(t *testing.T) {
+	ra := NewResourceAnalyzer()
+	spec := operationTestSpec()
+
+	pathParams := []parser.Parameter{
+		{Name: "id", In: "path", Description: "path level", Schema: &parser.Schema{Type: "string"}},
+	}
+	op := &parser.Operation{
+		Parameters: []parser.Parameter{
+			{Ref: "#/components/parameters/Limit"},
+			{Name: "id", In: "path", Description: "operation level", Schema: &parser.Schema{Type: "string"}},
+		},
+		RequestBody: &parser.RequestBody{Ref: "#/components/requestBodies/UserBody"},
+		Responses: map[string]parser.Response{
+			"default": {Description: "Error"},
+			"404":     {Ref: "#/components/responses/NotFound"},
+			"200": {
+				Description: "OK",
+				Content: parser.Content{
+					"application/vnd.api+json": {Schema: &parser.Schema{
+						Type:  "array",
+						Items: &parser.Schema{Ref: "#/components/schemas/User"},
+					}},
+					"text/plain": {Schema: &parser.Schema{Type: "string"}},
+				},
+			},
+		},
+	}
+
+	operation := ra.createOperation("PUT", "/users/{id}", op, pathParams, spec)
+
+	if len(operation.Parameters) != 2 {
+		t.Fatalf("Expected 2 parameters, got %d: %+v", len(operation.Parameters), operation.Parameters)
+	}
+	if id := operation.Parameters[0]; id.Name != "id" || id.Description != "operation level" || !id.Required {
+		t.Errorf("Expected operation-level required path parameter to override, got %+v", id)
+	}
+	if limit := operation.Parameters[1]; limit.Name != "limit" || limit.Type != "integer" || limit.Example != "10" {
+		t.Errorf("Expected resolved limit parameter, got %+v", limit)
+	}
+
+	body := operation.RequestBody
+	if body == nil {
+		t.Fatal("Expected resolved request body")
+	}
+	if body.ContentType != "application/json" || !body.Required {
+		t.Errorf("Expected required application/json body, got %+v", body)
+	}
+	if body.Schema == nil || body.Schema.Type != "User" || len(body.Schema.Properties) != 2 {
+		t.Errorf("Expected User schema with inherited properties, got %+v", body.Schema)
+	} else if id := body.Schema.Properties[0]; id.Name != "id" || !id.Required {
+		t.Errorf("Expected required id property from allOf, got %+v", id)
+	}
+
+	codes := make([]string, 0, len(operation.Responses))
+	for _, resp := range operation.Responses {
+		codes = append(codes, resp.StatusCode)
+	}
+	if !equalSlices(codes, []string{"200", "404", "default"}) {
+		t.Errorf("Expected responses sorted with default last, got %v", codes)
+	}
+	ok := operation.Responses[0]
+	if ok.ContentType != "application/vnd.api+json" || ok.Schema == nil || ok.Schema.Type != "array[User]" {
+		t.Errorf("Expected +json content with array schema, got %+v", ok)
+	}
+	if operation.Responses[1].Description != "Not found" {
+		t.Errorf("Expected resolved response ref, got %+v", operation.Responses[1])
+	}
+
+	if !equalSlices(operation.Security, []string{"bearer"}) {
+		t.Errorf("Expected global security to be inherited, got %v", operation.Security)
+	}
+}
+
+func TestBuildOperationSecurityOverride(t *testing.T) {
+	ra := NewResourceAnalyzer()
+	spec := operationTestSpec()
+
+	tests := []struct {
+		name     string
+		security []parser.SecurityRequirement
+		expected []string
+	}{
+		{"inherits global", nil, []string{"bearer"}},
+		{"explicitly public", []parser.SecurityRequirement{}, nil},
+		{"scoped override", []parser.SecurityRequirement{{"oauth": {"read", "write"}}}, []string{"oauth (read, write)"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			op := &parser.Operation{Security: tt.security}
+			operation := ra.createOperation("GET", "/users", op, nil, spec)
+			if !equalSlices(operation.Security, tt.expected) {
+				t.Errorf("Expected security %v, got %v", tt.expected, operation.Security)
+			}
+		})
+	}
+}
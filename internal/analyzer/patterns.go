@@ -60,6 +60,11 @@ func (pd *patternDetector) DetectPatterns(spec *parser.OpenAPISpec) []models.Pat
 		patterns = append(patterns, *pattern)
 	}
 
+	// Detect long-running operations
+	if pattern := pd.detectLongRunningOperations(spec); pattern != nil {
+		patterns = append(patterns, *pattern)
+	}
+
 	return patterns
 }
 
@@ -75,6 +80,12 @@ func (pd *patternDetector) detectPagination(spec *parser.OpenAPISpec) *models.Pa
 				continue
 			}
 
+			if op.Pageable != nil {
+				examples = append(examples, path)
+				paginationTypes["next-link (x-ms-pageable)"] = true
+				continue
+			}
+
 			if pd.hasPaginationParams(op.Parameters) {
 				examples = append(examples, path)
 				// Detect pagination type
@@ -383,6 +394,32 @@ func (pd *patternDetector) detectAuthentication(spec *parser.OpenAPISpec) *model
 	}
 }
 
+// detectLongRunningOperations looks for operations marked x-ms-long-running-operation
+func (pd *patternDetector) detectLongRunningOperations(spec *parser.OpenAPISpec) *models.Pattern {
+	var examples []string
+
+	for path, pathItem := range spec.Paths {
+		for _, op := range pd.getOperations(pathItem) {
+			if op != nil && op.LongRunning {
+				examples = append(examples, path)
+				break
+			}
+		}
+	}
+
+	if len(examples) == 0 {
+		return nil
+	}
+
+	return &models.Pattern{
+		Type:        "long_running_operations",
+		Description: "API marks operations as long-running (x-ms-long-running-operation)",
+		Examples:    examples,
+		Confidence:  "high", // Extension is explicit
+		Impact:      "Clients should poll the operation status until it completes",
+	}
+}
+
 // Helper methods
 
 // hasPaginationParams checks if parameters include pagination
@@ -171,6 +171,22 @@ func TestPatternDetection(t *testing.T) {
 			},
 			expected: []string{"versioning", "pagination", "sorting", "filtering"},
 		},
+		{
+			name: "vendor extension patterns",
+			spec: &parser.OpenAPISpec{
+				Paths: map[string]parser.PathItem{
+					"/vaults": {
+						Get: &parser.Operation{
+							Pageable: &parser.Pageable{NextLinkName: "nextLink"},
+						},
+					},
+					"/vaults/{vaultName}": {
+						Put: &parser.Operation{LongRunning: true},
+					},
+				},
+			},
+			expected: []string{"pagination", "long_running_operations"},
+		},
 	}
 
 	detector := NewPatternDetector()
@@ -0,0 +1,336 @@
+package analyzer
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/orchard9/api-godoc/internal/config"
+	"github.com/orchard9/api-godoc/internal/parser"
+	"github.com/orchard9/api-godoc/pkg/models"
+)
+
+// Pipeline stage names, in run order
+const (
+	StageParse         = "parse"
+	StageNormalize     = "normalize"
+	StageExtract       = "extract"
+	StageFilter        = "filter"
+	StageFields        = "fields"
+	StageRelationships = "relationships"
+	StagePatterns      = "patterns"
+	StageSummarize     = "summarize"
+)
+
+// Optional detectors run by the pipeline stages
+const (
+	DetectorRelationships = "relationships" // links between resources
+	DetectorPatterns      = "patterns"      // pagination, authentication and other API patterns
+	DetectorLifecycles    = "lifecycles"    // state machines from status enums and actions
+	DetectorContexts      = "contexts"      // bounded contexts
+	DetectorCapabilities  = "capabilities"  // capability matrix and gaps
+)
+
+// Detectors lists every optional detector
+var Detectors = []string{DetectorRelationships, DetectorPatterns, DetectorLifecycles, DetectorContexts, DetectorCapabilities}
+
+// PipelineOptions configures the built-in stages
+type PipelineOptions struct {
+	Config         *config.Config  // naming, segment, category and context rules
+	GroupBy        string          // grouping strategy (default path)
+	GroupExtension string          // vendor extension for extension grouping
+	SeparateNested bool            // keep nested resources apart from top-level ones
+	Filter         *ResourceFilter // nil keeps every resource
+	SchemaLevel    string          // essential, standard or full
+	Detectors      []string        // optional detectors to run; nil runs all
+	MinConfidence  float64         // drop relationships below this confidence (0 to 1)
+}
+
+// State is the intermediate result passed from stage to stage. Hooks may
+// inspect or modify it.
+type State struct {
+	Source    []byte                    // raw specification, parsed when Spec is nil
+	Spec      *parser.OpenAPISpec       // parsed specification
+	Resources []models.Resource         // extracted resources
+	Schemas   map[string]*parser.Schema // schemas bound to resources by name
+	Analysis  *models.APIAnalysis       // result, built up by the stages
+	Timings   []StageTiming             // duration of each completed stage
+}
+
+// StageTiming records how long a stage took
+type StageTiming struct {
+	Stage    string
+	Duration time.Duration
+}
+
+// Stage is one step of the pipeline
+type Stage struct {
+	Name string
+	Run  func(ctx context.Context, state *State) error
+}
+
+// Hook runs after a stage; an error stops the pipeline
+type Hook func(ctx context.Context, stage string, state *State) error
+
+// Pipeline runs analysis stages in order over a shared State
+type Pipeline struct {
+	stages []Stage
+	hooks  map[string][]Hook
+	all    []Hook
+}
+
+// NewPipeline creates a pipeline with the built-in stages
+func NewPipeline(opts PipelineOptions) (*Pipeline, error) {
+	cfg := opts.Config
+	if cfg == nil {
+		cfg = &config.Config{}
+	}
+	for _, d := range opts.Detectors {
+		if !slices.Contains(Detectors, d) {
+			return nil, fmt.Errorf("unknown detector: %s", d)
+		}
+	}
+	enabled := func(detector string) bool {
+		return opts.Detectors == nil || slices.Contains(opts.Detectors, detector)
+	}
+
+	names := NewNameNormalizer(cfg.Naming)
+	segments, err := NewSegmentRules(cfg.Segments)
+	if err != nil {
+		return nil, fmt.Errorf("invalid segment rules: %w", err)
+	}
+	classifier, err := NewCategoryClassifier(cfg.Categories)
+	if err != nil {
+		return nil, fmt.Errorf("invalid category rules: %w", err)
+	}
+	clusterer, err := NewContextClusterer(cfg.Contexts)
+	if err != nil {
+		return nil, fmt.Errorf("invalid context rules: %w", err)
+	}
+	groupBy := opts.GroupBy
+	if groupBy == "" {
+		groupBy = GroupByPath
+	}
+	strategy, err := NewGroupingStrategy(groupBy, opts.GroupExtension)
+	if err != nil {
+		return nil, fmt.Errorf("invalid grouping: %w", err)
+	}
+	if opts.MinConfidence < 0 || opts.MinConfidence > 1 {
+		return nil, fmt.Errorf("invalid minimum confidence: %v (expected 0 to 1)", opts.MinConfidence)
+	}
+	if opts.Filter != nil {
+		if err := opts.Filter.Validate(); err != nil {
+			return nil, fmt.Errorf("invalid resource filter: %w", err)
+		}
+	}
+
+	resourceAnalyzer := NewResourceAnalyzer()
+	resourceAnalyzer.SetNameNormalizer(names)
+	resourceAnalyzer.SetSegmentRules(segments)
+	resourceAnalyzer.SetGroupingStrategy(strategy)
+	resourceAnalyzer.SetSeparateNested(opts.SeparateNested)
+	binder := NewSchemaBinder()
+	binder.SetNameNormalizer(names)
+	relationshipDetector := NewRelationshipDetector()
+	relationshipDetector.SetNameNormalizer(names)
+
+	p := &Pipeline{hooks: make(map[string][]Hook)}
+	p.stages = []Stage{
+		{StageParse, func(_ context.Context, s *State) error {
+			if s.Spec != nil {
+				return nil
+			}
+			spec, err := parser.New().Parse(s.Source)
+			if err != nil {
+				return fmt.Errorf("failed to parse specification: %w", err)
+			}
+			s.Spec = spec
+			return nil
+		}},
+		{StageNormalize, func(_ context.Context, s *State) error {
+			s.Analysis = &models.APIAnalysis{
+				Title:         s.Spec.Info.Title,
+				Version:       s.Spec.Info.Version,
+				Description:   s.Spec.Info.Description,
+				SpecType:      "Unknown",
+				OriginalPaths: len(s.Spec.Paths),
+				GeneratedAt:   time.Now(),
+			}
+			if s.Analysis.Title == "" {
+				s.Analysis.Title = "API Documentation"
+			}
+			if s.Analysis.Version == "" {
+				s.Analysis.Version = "1.0.0"
+			}
+			if s.Spec.OpenAPI != "" {
+				s.Analysis.SpecType = "OpenAPI " + s.Spec.OpenAPI
+			}
+			if len(s.Spec.Servers) > 0 {
+				s.Analysis.BaseURL = s.Spec.Servers[0].URL
+			}
+			return nil
+		}},
+		{StageExtract, func(_ context.Context, s *State) error {
+			s.Resources = resourceAnalyzer.ExtractResources(s.Spec)
+			classifier.Classify(s.Resources)
+			s.Analysis.Grouping = groupingReport(resourceAnalyzer, s.Spec, strategy, opts.GroupExtension)
+			return nil
+		}},
+		{StageFilter, func(_ context.Context, s *State) error {
+			if opts.Filter != nil {
+				s.Resources = NewResourceFilterer().FilterResources(s.Resources, opts.Filter)
+			}
+			return nil
+		}},
+		{StageFields, func(_ context.Context, s *State) error {
+			reducer := NewSchemaReducer()
+			s.Schemas = binder.Bind(s.Resources, s.Spec)
+			for i := range s.Resources {
+				if schema, ok := s.Schemas[s.Resources[i].Name]; ok {
+					s.Resources[i].Fields = reducer.SchemaToFields(schema, opts.SchemaLevel)
+				}
+			}
+			if enabled(DetectorLifecycles) {
+				NewLifecycleInferrer().Infer(s.Resources, s.Schemas, s.Spec)
+			}
+			return nil
+		}},
+		{StageRelationships, func(_ context.Context, s *State) error {
+			if enabled(DetectorRelationships) {
+				relationshipDetector.DetectRelationships(s.Resources, s.Spec)
+				FilterRelationships(s.Resources, opts.MinConfidence)
+			}
+			if enabled(DetectorContexts) {
+				s.Analysis.Contexts = clusterer.Cluster(s.Resources)
+			}
+			return nil
+		}},
+		{StagePatterns, func(_ context.Context, s *State) error {
+			if enabled(DetectorPatterns) {
+				s.Analysis.Patterns = NewPatternDetector().DetectPatterns(s.Spec)
+			}
+			return nil
+		}},
+		{StageSummarize, func(_ context.Context, s *State) error {
+			s.Analysis.Resources = s.Resources
+			s.Analysis.Summary = calculateSummary(s.Resources, s.Spec)
+			s.Analysis.Summary.Skipped = resourceAnalyzer.SkippedSegments(s.Spec)
+			if enabled(DetectorCapabilities) {
+				s.Analysis.Capabilities = CapabilityMatrix(s.Resources)
+			}
+			return nil
+		}},
+	}
+	return p, nil
+}
+
+// Stages returns the stage names in run order
+func (p *Pipeline) Stages() []string {
+	names := make([]string, len(p.stages))
+	for i, stage := range p.stages {
+		names[i] = stage.Name
+	}
+	return names
+}
+
+// Insert adds a stage after the named stage
+func (p *Pipeline) Insert(after string, stage Stage) error {
+	i := slices.Index(p.Stages(), after)
+	if i < 0 {
+		return fmt.Errorf("unknown stage: %s", after)
+	}
+	if slices.Contains(p.Stages(), stage.Name) {
+		return fmt.Errorf("duplicate stage: %s", stage.Name)
+	}
+	p.stages = slices.Insert(p.stages, i+1, stage)
+	return nil
+}
+
+// AddHook registers a hook that runs after the named stage, or after every
+// stage when the name is empty
+func (p *Pipeline) AddHook(stage string, hook Hook) error {
+	if stage == "" {
+		p.all = append(p.all, hook)
+		return nil
+	}
+	if !slices.Contains(p.Stages(), stage) {
+		return fmt.Errorf("unknown stage: %s", stage)
+	}
+	p.hooks[stage] = append(p.hooks[stage], hook)
+	return nil
+}
+
+// Run runs every stage over the state, checking the context before each one
+func (p *Pipeline) Run(ctx context.Context, state *State) (*models.APIAnalysis, error) {
+	if state.Spec == nil && state.Source == nil {
+		return nil, fmt.Errorf("spec cannot be nil")
+	}
+
+	for _, stage := range p.stages {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		start := time.Now()
+		if err := stage.Run(ctx, state); err != nil {
+			return nil, fmt.Errorf("%s stage: %w", stage.Name, err)
+		}
+		state.Timings = append(state.Timings, StageTiming{Stage: stage.Name, Duration: time.Since(start)})
+
+		for _, hook := range slices.Concat(p.hooks[stage.Name], p.all) {
+			if err := hook(ctx, stage.Name, state); err != nil {
+				return nil, fmt.Errorf("%s stage hook: %w", stage.Name, err)
+			}
+		}
+	}
+	return state.Analysis, nil
+}
+
+// groupingReport compares the selected grouping strategy with the
+// alternatives. Path grouping is the default and is not reported.
+func groupingReport(ra *ResourceAnalyzer, spec *parser.OpenAPISpec, strategy GroupingStrategy, extension string) *models.GroupingReport {
+	if strategy.Name() == GroupByPath {
+		return nil
+	}
+
+	var strategies []GroupingStrategy
+	for _, name := range []string{GroupByPath, GroupByTag, GroupByOperationID, GroupByExtension} {
+		if s, err := NewGroupingStrategy(name, extension); err == nil {
+			strategies = append(strategies, s)
+		}
+	}
+
+	return &models.GroupingReport{
+		Strategy:      strategy.Name(),
+		Disagreements: ra.CompareStrategies(spec, strategies...),
+	}
+}
+
+// calculateSummary counts resources and operations. Operations on nested
+// paths belong to every resource in the chain, so each method and path pair
+// counts once. Coverage is the share of specification paths served by at
+// least one reported resource, so it never exceeds 100%.
+func calculateSummary(resources []models.Resource, spec *parser.OpenAPISpec) models.AnalysisStat {
+	operations := make(map[string]bool)
+	covered := make(map[string]bool)
+	for _, resource := range resources {
+		for _, op := range resource.Operations {
+			operations[op.Method+" "+op.Path] = true
+			if _, ok := spec.Paths[op.Path]; ok {
+				covered[op.Path] = true
+			}
+		}
+	}
+
+	coverage := 0
+	if len(spec.Paths) > 0 {
+		coverage = len(covered) * 100 / len(spec.Paths)
+	}
+
+	return models.AnalysisStat{
+		TotalResources:   len(resources),
+		TotalOperations:  len(operations),
+		TotalEndpoints:   len(spec.Paths),
+		ResourceCoverage: coverage,
+	}
+}
@@ -0,0 +1,225 @@
+package analyzer
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/orchard9/api-godoc/internal/parser"
+	"github.com/orchard9/api-godoc/pkg/models"
+)
+
+// pipelineTestSpec has three paths served by two resources
+func pipelineTestSpec() *parser.OpenAPISpec {
+	return &parser.OpenAPISpec{
+		OpenAPI: "3.0.3",
+		Info:    parser.Info{Title: "Shop"},
+		Paths: map[string]parser.PathItem{
+			"/orders": {
+				Get:  &parser.Operation{Summary: "List orders"},
+				Post: &parser.Operation{Summary: "Create order"},
+			},
+			"/orders/{id}": {
+				Get:    &parser.Operation{Summary: "Get order"},
+				Put:    &parser.Operation{Summary: "Replace order"},
+				Patch:  &parser.Operation{Summary: "Update order"},
+				Delete: &parser.Operation{Summary: "Delete order"},
+			},
+			"/users": {
+				Get: &parser.Operation{Summary: "List users"},
+			},
+		},
+	}
+}
+
+func TestPipelineRun(t *testing.T) {
+	pipeline, err := NewPipeline(PipelineOptions{})
+	if err != nil {
+		t.Fatalf("NewPipeline() error = %v", err)
+	}
+
+	var seen []string
+	if err := pipeline.AddHook("", func(_ context.Context, stage string, state *State) error {
+		seen = append(seen, stage)
+		if len(state.Timings) != len(seen) || state.Timings[len(seen)-1].Stage != stage {
+			t.Errorf("Expected a timing for %s, got %+v", stage, state.Timings)
+		}
+		return nil
+	}); err != nil {
+		t.Fatalf("AddHook() error = %v", err)
+	}
+	var extracted int
+	if err := pipeline.AddHook(StageExtract, func(_ context.Context, _ string, state *State) error {
+		extracted = len(state.Resources)
+		return nil
+	}); err != nil {
+		t.Fatalf("AddHook() error = %v", err)
+	}
+
+	state := &State{Spec: pipelineTestSpec()}
+	analysis, err := pipeline.Run(context.Background(), state)
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+
+	if !reflect.DeepEqual(seen, pipeline.Stages()) {
+		t.Errorf("Expected hooks after %v, got %v", pipeline.Stages(), seen)
+	}
+	if extracted != 2 {
+		t.Errorf("Expected 2 resources after extraction, got %d", extracted)
+	}
+	if analysis.Title != "Shop" || analysis.Version != "1.0.0" || analysis.SpecType != "OpenAPI 3.0.3" {
+		t.Errorf("Unexpected metadata: %s %s %s", analysis.Title, analysis.Version, analysis.SpecType)
+	}
+	if len(analysis.Capabilities) == 0 {
+		t.Error("Expected a capability matrix")
+	}
+}
+
+func TestPipelineSummary(t *testing.T) {
+	nested := &parser.OpenAPISpec{Paths: map[string]parser.PathItem{
+		"/users":                {Get: &parser.Operation{}},
+		"/users/{userId}/posts": {Get: &parser.Operation{}, Post: &parser.Operation{}},
+	}}
+
+	tests := []struct {
+		name     string
+		spec     *parser.OpenAPISpec
+		filter   *ResourceFilter
+		expected models.AnalysisStat
+	}{
+		{
+			name:     "all resources",
+			expected: models.AnalysisStat{TotalResources: 2, TotalOperations: 7, TotalEndpoints: 3, ResourceCoverage: 100},
+		},
+		{
+			name:     "filtered",
+			filter:   &ResourceFilter{Include: []string{"users"}},
+			expected: models.AnalysisStat{TotalResources: 1, TotalOperations: 1, TotalEndpoints: 3, ResourceCoverage: 33},
+		},
+		{
+			// Nested operations belong to users and posts but count once
+			name:     "nested paths",
+			spec:     nested,
+			expected: models.AnalysisStat{TotalResources: 2, TotalOperations: 3, TotalEndpoints: 2, ResourceCoverage: 100},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pipeline, err := NewPipeline(PipelineOptions{Filter: tt.filter})
+			if err != nil {
+				t.Fatalf("NewPipeline() error = %v", err)
+			}
+			spec := tt.spec
+			if spec == nil {
+				spec = pipelineTestSpec()
+			}
+			analysis, err := pipeline.Run(context.Background(), &State{Spec: spec})
+			if err != nil {
+				t.Fatalf("Run() error = %v", err)
+			}
+			summary := analysis.Summary
+			summary.Skipped = nil
+			if !reflect.DeepEqual(summary, tt.expected) {
+				t.Errorf("Expected summary %+v, got %+v", tt.expected, analysis.Summary)
+			}
+		})
+	}
+}
+
+func TestPipelineDetectors(t *testing.T) {
+	pipeline, err := NewPipeline(PipelineOptions{Detectors: []string{DetectorRelationships}})
+	if err != nil {
+		t.Fatalf("NewPipeline() error = %v", err)
+	}
+	analysis, err := pipeline.Run(context.Background(), &State{Spec: pipelineTestSpec()})
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	if analysis.Patterns != nil || analysis.Capabilities != nil {
+		t.Errorf("Expected only relationships, got patterns %v and capabilities %v", analysis.Patterns, analysis.Capabilities)
+	}
+}
+
+func TestPipelineInsert(t *testing.T) {
+	pipeline, err := NewPipeline(PipelineOptions{})
+	if err != nil {
+		t.Fatalf("NewPipeline() error = %v", err)
+	}
+
+	// A custom stage drops resources without write operations
+	err = pipeline.Insert(StageFilter, Stage{Name: "writable", Run: func(_ context.Context, state *State) error {
+		var kept []models.Resource
+		for _, resource := range state.Resources {
+			if len(resource.Operations) > 1 {
+				kept = append(kept, resource)
+			}
+		}
+		state.Resources = kept
+		return nil
+	}})
+	if err != nil {
+		t.Fatalf("Insert() error = %v", err)
+	}
+
+	expected := []string{StageParse, StageNormalize, StageExtract, StageFilter, "writable", StageFields, StageRelationships, StagePatterns, StageSummarize}
+	if !reflect.DeepEqual(pipeline.Stages(), expected) {
+		t.Errorf("Expected stages %v, got %v", expected, pipeline.Stages())
+	}
+
+	analysis, err := pipeline.Run(context.Background(), &State{Spec: pipelineTestSpec()})
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	if len(analysis.Resources) != 1 || analysis.Resources[0].Name != "orders" {
+		t.Errorf("Expected only orders, got %+v", analysis.Resources)
+	}
+
+	if err := pipeline.Insert("bogus", Stage{Name: "other"}); err == nil {
+		t.Error("Expected an error for an unknown stage")
+	}
+	if err := pipeline.Insert(StageParse, Stage{Name: StageFields}); err == nil {
+		t.Error("Expected an error for a duplicate stage")
+	}
+	if err := pipeline.AddHook("bogus", nil); err == nil {
+		t.Error("Expected an error for a hook on an unknown stage")
+	}
+}
+
+func TestPipelineErrors(t *testing.T) {
+	if _, err := NewPipeline(PipelineOptions{Detectors: []string{"sentiment"}}); err == nil {
+		t.Error("Expected an error for an unknown detector")
+	}
+	if _, err := NewPipeline(PipelineOptions{Filter: &ResourceFilter{Pattern: "[invalid"}}); err == nil {
+		t.Error("Expected an error for an invalid filter")
+	}
+
+	pipeline, err := NewPipeline(PipelineOptions{})
+	if err != nil {
+		t.Fatalf("NewPipeline() error = %v", err)
+	}
+
+	if _, err := pipeline.Run(context.Background(), &State{Source: []byte("not: [valid")}); err == nil {
+		t.Error("Expected a parse error")
+	}
+
+	stop := errors.New("stop")
+	if err := pipeline.AddHook(StageExtract, func(context.Context, string, *State) error { return stop }); err != nil {
+		t.Fatalf("AddHook() error = %v", err)
+	}
+	state := &State{Spec: pipelineTestSpec()}
+	if _, err := pipeline.Run(context.Background(), state); !errors.Is(err, stop) {
+		t.Errorf("Expected the hook error, got %v", err)
+	}
+	if state.Analysis.Summary.TotalResources != 0 {
+		t.Error("Expected the pipeline to stop before summarizing")
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := pipeline.Run(ctx, &State{Spec: pipelineTestSpec()}); !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected context.Canceled, got %v", err)
+	}
+}
@@ -1,35 +1,64 @@
 package analyzer
 
 import (
+	"math"
 	"regexp"
+	"slices"
 	"strings"
 
+	"github.com/orchard9/api-godoc/internal/config"
 	"github.com/orchard9/api-godoc/internal/parser"
 	"github.com/orchard9/api-godoc/pkg/models"
 )
 
+// pathHierarchyVia is the via of relationships found in nested paths
+const pathHierarchyVia = "path hierarchy"
+
+// evidenceWeights is how strongly each kind of evidence supports a relationship
+var evidenceWeights = map[string]float64{
+	models.EvidencePathHierarchy: 0.9,
+	models.EvidenceLink:          0.9,
+	models.EvidenceSchemaRef:     0.8,
+	models.EvidenceParameter:     0.6,
+	models.EvidenceNaming:        0.5,
+}
+
+// newEvidence creates an evidence item weighted by its kind
+func newEvidence(kind, source string) models.Evidence {
+	return models.Evidence{Kind: kind, Source: source, Weight: evidenceWeights[kind]}
+}
+
 // RelationshipDetector analyzes API specifications to identify relationships between resources
 type RelationshipDetector struct {
-	pathParamRegex  *regexp.Regexp
-	idPatternRegex  *regexp.Regexp
-	foreignKeyRegex *regexp.Regexp
+	pathParamRegex   *regexp.Regexp
+	idPatternRegex   *regexp.Regexp
+	foreignKeyRegex  *regexp.Regexp
+	foreignKeysRegex *regexp.Regexp
+	names            *NameNormalizer
 }
 
 // NewRelationshipDetector creates a new relationship detector
 func NewRelationshipDetector() *RelationshipDetector {
 	return &RelationshipDetector{
-		pathParamRegex:  regexp.MustCompile(`\{([^}]+)\}`),
-		idPatternRegex:  regexp.MustCompile(`^(.+)Id$|^(.+)_id$`),
-		foreignKeyRegex: regexp.MustCompile(`^(.+)(Id|_id)$`),
+		pathParamRegex:   regexp.MustCompile(`\{([^}]+)\}`),
+		idPatternRegex:   regexp.MustCompile(`^(.+)Id$|^(.+)_id$`),
+		foreignKeyRegex:  regexp.MustCompile(`^(.+)(Id|_id)$`),
+		foreignKeysRegex: regexp.MustCompile(`^(.+)(Ids|_ids)$`),
+		names:            NewNameNormalizer(config.NamingConfig{}),
+	}
+}
+
+// SetNameNormalizer changes how path segments and schema names are matched to resources
+func (rd *RelationshipDetector) SetNameNormalizer(normalizer *NameNormalizer) {
+	if normalizer == nil {
+		normalizer = NewNameNormalizer(config.NamingConfig{})
 	}
+	rd.names = normalizer
 }
 
 // DetectRelationships analyzes resources to identify relationships between them
 func (rd *RelationshipDetector) DetectRelationships(resources []models.Resource, spec *parser.OpenAPISpec) {
-	resourceMap := make(map[string]*models.Resource)
-	for i := range resources {
-		resourceMap[resources[i].Name] = &resources[i]
-	}
+	resourceMap := rd.indexResources(resources)
 
 	// Analyze path-based relationships
 	rd.detectPathRelationships(resourceMap, spec)
@@ -39,13 +68,39 @@ func (rd *RelationshipDetector) DetectRelationships(resources []models.Resource,
 
 	// Analyze schema-based relationships
 	rd.detectSchemaRelationships(resourceMap, spec)
+
+	// Analyze OpenAPI links and hypermedia links
+	rd.detectLinkRelationships(resourceMap, spec)
+
+	// Record the operations that expand references
+	rd.detectExpansions(resourceMap, spec)
+
+	// Collapse join resources into many-to-many associations
+	rd.detectAssociations(resourceMap, spec)
+}
+
+// indexResources indexes resources by name, aliases and their normalized
+// keys so raw path segments, parameters and schema names find their resource
+func (rd *RelationshipDetector) indexResources(resources []models.Resource) map[string]*models.Resource {
+	resourceMap := make(map[string]*models.Resource)
+	for i := range resources {
+		resource := &resources[i]
+		for _, name := range append([]string{resource.Name}, resource.Aliases...) {
+			for _, key := range []string{name, rd.names.Key(name)} {
+				if _, exists := resourceMap[key]; !exists {
+					resourceMap[key] = resource
+				}
+			}
+		}
+	}
+	return resourceMap
 }
 
 // detectPathRelationships identifies relationships from nested paths
 func (rd *RelationshipDetector) detectPathRelationships(resourceMap map[string]*models.Resource, spec *parser.OpenAPISpec) {
 	for path := range spec.Paths {
 		segments := rd.extractPathSegments(path)
-		rd.analyzePathHierarchy(segments, resourceMap)
+		rd.analyzePathHierarchy(path, segments, resourceMap)
 	}
 }
 
@@ -90,7 +145,8 @@ type PathSegment struct {
 }
 
 // analyzePathHierarchy detects parent-child relationships from path structure
-func (rd *RelationshipDetector) analyzePathHierarchy(segments []PathSegment, resourceMap map[string]*models.Resource) {
+func (rd *RelationshipDetector) analyzePathHierarchy(path string, segments []PathSegment, resourceMap map[string]*models.Resource) {
+	evidence := newEvidence(models.EvidencePathHierarchy, path)
 	for i := 0; i < len(segments)-1; i++ {
 		current := segments[i]
 		next := segments[i+1]
@@ -100,12 +156,20 @@ func (rd *RelationshipDetector) analyzePathHierarchy(segments []PathSegment, res
 			continue
 		}
 
-		// Look for pattern: /resource/{id}/childResource
+		// Look for pattern: /resource/{id}/childResource. A singular child
+		// without an identifier (/users/{id}/profile) is the only one of
+		// its kind; children cannot exist without their parent.
 		if i+2 < len(segments) && next.IsParameter {
 			childSegment := segments[i+2]
 			if !childSegment.IsParameter {
-				rd.addRelationship(resourceMap, current.Value, childSegment.Value, "has_many", "path hierarchy", "strong")
-				rd.addRelationship(resourceMap, childSegment.Value, current.Value, "belongs_to", "path hierarchy", "strong")
+				identified := i+3 < len(segments) && segments[i+3].IsParameter
+				if !identified && rd.isSingular(childSegment.Value) {
+					rd.addRelationship(resourceMap, current.Value, childSegment.Value, "has_one", pathHierarchyVia, models.CardinalityOneToOne, false, evidence)
+					rd.addRelationship(resourceMap, childSegment.Value, current.Value, "belongs_to", pathHierarchyVia, models.CardinalityOneToOne, true, evidence)
+				} else {
+					rd.addRelationship(resourceMap, current.Value, childSegment.Value, "has_many", pathHierarchyVia, models.CardinalityOneToMany, false, evidence)
+					rd.addRelationship(resourceMap, childSegment.Value, current.Value, "belongs_to", pathHierarchyVia, models.CardinalityManyToOne, true, evidence)
+				}
 			}
 		}
 	}
@@ -113,7 +177,12 @@ func (rd *RelationshipDetector) analyzePathHierarchy(segments []PathSegment, res
 
 // detectParameterRelationships identifies relationships from path and query parameters
 func (rd *RelationshipDetector) detectParameterRelationships(resourceMap map[string]*models.Resource) {
+	visited := make(map[*models.Resource]bool)
 	for _, resource := range resourceMap {
+		if visited[resource] {
+			continue
+		}
+		visited[resource] = true
 		for _, operation := range resource.Operations {
 			rd.analyzeOperationParameters(resource, operation, resourceMap)
 		}
@@ -127,25 +196,31 @@ func (rd *RelationshipDetector) analyzeOperationParameters(resource *models.Reso
 	for _, match := range pathParams {
 		if len(match) > 1 {
 			paramName := match[1]
-			rd.analyzeParameterForRelationship(resource.Name, paramName, resourceMap)
+			rd.analyzeParameterForRelationship(resource.Name, paramName, operation.Method+" "+operation.Path, resourceMap)
 		}
 	}
 }
 
 // analyzeParameterForRelationship checks if a parameter indicates a relationship
-func (rd *RelationshipDetector) analyzeParameterForRelationship(resourceName, paramName string, resourceMap map[string]*models.Resource) {
+func (rd *RelationshipDetector) analyzeParameterForRelationship(resourceName, paramName, source string, resourceMap map[string]*models.Resource) {
 	// Check for foreign key patterns like userId, user_id, projectId, etc.
 	matches := rd.foreignKeyRegex.FindStringSubmatch(paramName)
 	if len(matches) > 1 {
 		// Extract the referenced resource name
 		referencedResource := matches[1]
-		referencedResource = strings.ToLower(referencedResource)
 
-		// Check if this resource exists
-		if targetResource, exists := resourceMap[referencedResource]; exists {
-			rd.addRelationship(resourceMap, targetResource.Name, resourceName, "has_many", paramName, "medium")
-			rd.addRelationship(resourceMap, resourceName, targetResource.Name, "references", paramName, "medium")
+		// Check if this resource exists; nested path parameters repeat the
+		// path hierarchy and corroborate the relationship it created
+		targetResource := rd.findResource(resourceMap, referencedResource)
+		if targetResource == nil || targetResource.Name == resourceName {
+			return
+		}
+		evidence := newEvidence(models.EvidenceParameter, source)
+		if rd.corroborate(resourceMap, resourceName, targetResource.Name, paramName, evidence) {
+			return
 		}
+		rd.addRelationship(resourceMap, targetResource.Name, resourceName, "has_many", paramName, models.CardinalityOneToMany, false, evidence)
+		rd.addRelationship(resourceMap, resourceName, targetResource.Name, "references", paramName, models.CardinalityManyToOne, true, evidence)
 	}
 }
 
@@ -159,73 +234,195 @@ func (rd *RelationshipDetector) detectSchemaRelationships(resourceMap map[string
 	}
 }
 
-// analyzeSchemaReferences examines schema properties for references to other resources
+// analyzeSchemaReferences examines schema properties for references to other
+// resources. The property shape decides the cardinality: a single reference
+// points to one target, an array to many. References in both directions
+// make the relationship one-to-one or many-to-many.
 func (rd *RelationshipDetector) analyzeSchemaReferences(schemaName string, schema parser.Schema, resourceMap map[string]*models.Resource, spec *parser.OpenAPISpec) {
 	if schema.Properties == nil {
 		return
 	}
 
 	resourceName := rd.schemaToResourceName(schemaName)
-	sourceResource := resourceMap[resourceName]
+	sourceResource := rd.findResource(resourceMap, resourceName)
 	if sourceResource == nil {
 		return
 	}
 
 	for propertyName, property := range schema.Properties {
+		required := slices.Contains(schema.Required, propertyName) && !property.Nullable
+		source := "#/components/schemas/" + schemaName + "/properties/" + propertyName
+		evidence := newEvidence(models.EvidenceSchemaRef, source)
+
+		// Check for identifiers that expand into other schemas
+		rd.analyzeExpandableReference(resourceMap, sourceResource.Name, propertyName, property, required, evidence)
+
 		// Check for $ref to other schemas
-		if property.Ref != "" {
-			referencedSchema := rd.extractSchemaNameFromRef(property.Ref)
+		if ref := schemaRef(property); ref != "" {
+			referencedSchema := rd.extractSchemaNameFromRef(ref)
 			referencedResource := rd.schemaToResourceName(referencedSchema)
 
-			if targetResource := resourceMap[referencedResource]; targetResource != nil {
-				rd.addRelationship(resourceMap, sourceResource.Name, targetResource.Name, "references", propertyName, "strong")
+			if targetResource := rd.findResource(resourceMap, referencedResource); targetResource != nil {
+				cardinality := models.CardinalityManyToOne
+				if single, _ := rd.backReference(spec, referencedSchema, sourceResource.Name, resourceMap); single {
+					cardinality = models.CardinalityOneToOne
+				}
+				rd.addRelationship(resourceMap, sourceResource.Name, targetResource.Name, "references", propertyName, cardinality, required, evidence)
 			}
 		}
 
 		// Check for array references
-		if property.Type == "array" && property.Items != nil && property.Items.Ref != "" {
-			referencedSchema := rd.extractSchemaNameFromRef(property.Items.Ref)
-			referencedResource := rd.schemaToResourceName(referencedSchema)
-
-			if targetResource := resourceMap[referencedResource]; targetResource != nil {
-				rd.addRelationship(resourceMap, sourceResource.Name, targetResource.Name, "has_many", propertyName, "strong")
-				rd.addRelationship(resourceMap, targetResource.Name, sourceResource.Name, "belongs_to", propertyName, "strong")
+		if property.Type == "array" && property.Items != nil {
+			if ref := schemaRef(*property.Items); ref != "" {
+				referencedSchema := rd.extractSchemaNameFromRef(ref)
+				referencedResource := rd.schemaToResourceName(referencedSchema)
+
+				if targetResource := rd.findResource(resourceMap, referencedResource); targetResource != nil {
+					forward, inverse := models.CardinalityOneToMany, models.CardinalityManyToOne
+					if _, many := rd.backReference(spec, referencedSchema, sourceResource.Name, resourceMap); many {
+						forward, inverse = models.CardinalityManyToMany, models.CardinalityManyToMany
+					}
+					nonEmpty := required && property.MinItems != nil && *property.MinItems > 0
+					rd.addRelationship(resourceMap, sourceResource.Name, targetResource.Name, "has_many", propertyName, forward, nonEmpty, evidence)
+					rd.addRelationship(resourceMap, targetResource.Name, sourceResource.Name, "belongs_to", propertyName, inverse, false, evidence)
+				}
 			}
 		}
 
 		// Check for foreign key naming patterns in properties
-		rd.analyzePropertyForForeignKey(sourceResource.Name, propertyName, resourceMap)
+		rd.analyzePropertyForForeignKey(sourceResource.Name, propertyName, property, required, source, resourceMap)
+	}
+}
+
+// backReference reports whether a schema refers back to a resource with a
+// single reference or with an array of references
+func (rd *RelationshipDetector) backReference(spec *parser.OpenAPISpec, schemaName, resourceName string, resourceMap map[string]*models.Resource) (single, many bool) {
+	if spec.Components == nil {
+		return false, false
+	}
+	refersTo := func(ref string) bool {
+		target := rd.findResource(resourceMap, rd.schemaToResourceName(rd.extractSchemaNameFromRef(ref)))
+		return ref != "" && target != nil && target.Name == resourceName
+	}
+	for _, property := range spec.Components.Schemas[schemaName].Properties {
+		if refersTo(schemaRef(property)) {
+			single = true
+		}
+		if property.Type == "array" && property.Items != nil && refersTo(schemaRef(*property.Items)) {
+			many = true
+		}
 	}
+	return single, many
 }
 
-// analyzePropertyForForeignKey checks property names for foreign key patterns
-func (rd *RelationshipDetector) analyzePropertyForForeignKey(resourceName, propertyName string, resourceMap map[string]*models.Resource) {
+// schemaRef returns the reference of a schema, including references wrapped
+// in a single-element allOf to carry nullable or a description
+func schemaRef(schema parser.Schema) string {
+	if schema.Ref != "" {
+		return schema.Ref
+	}
+	if len(schema.AllOf) == 1 {
+		return schema.AllOf[0].Ref
+	}
+	return ""
+}
+
+// analyzePropertyForForeignKey checks property names for foreign key
+// patterns: customerId refers to one customer, productIds to many products
+func (rd *RelationshipDetector) analyzePropertyForForeignKey(resourceName, propertyName string, property parser.Schema, required bool, source string, resourceMap map[string]*models.Resource) {
+	evidence := newEvidence(models.EvidenceNaming, source)
+	if property.Type == "array" {
+		matches := rd.foreignKeysRegex.FindStringSubmatch(propertyName)
+		if len(matches) > 1 {
+			if targetResource := rd.findResource(resourceMap, matches[1]); targetResource != nil && targetResource.Name != resourceName {
+				nonEmpty := required && property.MinItems != nil && *property.MinItems > 0
+				rd.addRelationship(resourceMap, resourceName, targetResource.Name, "references", propertyName, models.CardinalityManyToMany, nonEmpty, evidence)
+				rd.addRelationship(resourceMap, targetResource.Name, resourceName, "referenced_by", propertyName, models.CardinalityManyToMany, false, evidence)
+			}
+		}
+		return
+	}
+
 	matches := rd.foreignKeyRegex.FindStringSubmatch(propertyName)
 	if len(matches) > 1 {
 		referencedResource := matches[1]
-		referencedResource = strings.ToLower(referencedResource)
 
-		if targetResource := resourceMap[referencedResource]; targetResource != nil {
-			rd.addRelationship(resourceMap, resourceName, targetResource.Name, "references", propertyName, "medium")
-			rd.addRelationship(resourceMap, targetResource.Name, resourceName, "referenced_by", propertyName, "weak")
+		if targetResource := rd.findResource(resourceMap, referencedResource); targetResource != nil && targetResource.Name != resourceName {
+			rd.addRelationship(resourceMap, resourceName, targetResource.Name, "references", propertyName, models.CardinalityManyToOne, required, evidence)
+			rd.addRelationship(resourceMap, targetResource.Name, resourceName, "referenced_by", propertyName, models.CardinalityOneToMany, false, evidence)
 		}
 	}
 }
 
-// schemaToResourceName converts a schema name to a likely resource name
+// isSingular reports whether a path segment is a singular noun such as
+// profile, as opposed to a collection such as tasks
+func (rd *RelationshipDetector) isSingular(segment string) bool {
+	word := strings.ToLower(segment)
+	inflector := rd.names.inflector
+	return inflector.Plural(word) != word && inflector.Singular(word) == word
+}
+
+// schemaToResourceName converts a schema name to a likely resource name in
+// kebab-case, e.g. "v1ApiKeyResponse" -> "api-key"
 func (rd *RelationshipDetector) schemaToResourceName(schemaName string) string {
+	return schemaResourceName(schemaName)
+}
+
+// schemaResourceName strips version prefixes and Request/Response style
+// suffixes from a schema name and returns it in kebab-case
+func schemaResourceName(schemaName string) string {
+	words := splitWords(schemaName)
+
+	// Drop API version prefixes used by generated schemas (v1Task)
+	if len(words) > 1 && isVersionWord(words[0]) {
+		words = words[1:]
+	}
+
 	// Remove common suffixes
-	name := strings.ToLower(schemaName)
-	suffixes := []string{"response", "request", "schema", "model", "dto"}
+	suffixes := map[string]bool{"response": true, "request": true, "schema": true, "model": true, "dto": true}
+	if len(words) > 1 && suffixes[words[len(words)-1]] {
+		words = words[:len(words)-1]
+	} else if len(words) == 1 {
+		for suffix := range suffixes {
+			if trimmed := strings.TrimSuffix(words[0], suffix); trimmed != "" {
+				words[0] = trimmed
+			}
+		}
+	}
+
+	return strings.Join(words, "-")
+}
 
-	for _, suffix := range suffixes {
-		if strings.HasSuffix(name, suffix) {
-			name = strings.TrimSuffix(name, suffix)
-			break
+// isVersionWord reports whether a word is an API version such as v1 or v2beta
+func isVersionWord(word string) bool {
+	return len(word) > 1 && word[0] == 'v' && word[1] >= '0' && word[1] <= '9'
+}
+
+// corroborate adds evidence to the relationships between two resources that
+// describe the same link as via and reports whether there were any
+func (rd *RelationshipDetector) corroborate(resourceMap map[string]*models.Resource, a, b, via string, evidence models.Evidence) bool {
+	found := false
+	for _, pair := range [][2]string{{a, b}, {b, a}} {
+		source := rd.findResource(resourceMap, pair[0])
+		if source == nil {
+			continue
+		}
+		for i, rel := range source.Relationships {
+			if rel.Resource == pair[1] && rd.sameLink(rel.Via, via, a, b) {
+				addEvidence(&source.Relationships[i], evidence)
+				found = true
+			}
 		}
 	}
+	return found
+}
 
-	return name
+// findResource looks a raw name up by exact match, then by normalized key
+func (rd *RelationshipDetector) findResource(resourceMap map[string]*models.Resource, name string) *models.Resource {
+	if resource := resourceMap[name]; resource != nil {
+		return resource
+	}
+	return resourceMap[rd.names.Key(name)]
 }
 
 // extractSchemaNameFromRef extracts schema name from a $ref path
@@ -238,30 +435,138 @@ func (rd *RelationshipDetector) extractSchemaNameFromRef(ref string) string {
 	return ref
 }
 
-// addRelationship safely adds a relationship to a resource
-func (rd *RelationshipDetector) addRelationship(resourceMap map[string]*models.Resource, fromResource, toResource, relType, via, strength string) {
-	source := resourceMap[fromResource]
+// addRelationship adds a relationship to a resource, or adds the evidence to
+// the same relationship found earlier, and returns it. The evidence also
+// corroborates the other relationships that describe the same link, and a
+// new relationship starts with their evidence. It returns nil when the
+// source resource is unknown.
+func (rd *RelationshipDetector) addRelationship(resourceMap map[string]*models.Resource, fromResource, toResource, relType, via, cardinality string, required bool, evidence ...models.Evidence) *models.Relationship {
+	source := rd.findResource(resourceMap, fromResource)
 	if source == nil {
-		return
+		return nil
+	}
+	fromResource = source.Name
+	if target := rd.findResource(resourceMap, toResource); target != nil {
+		toResource = target.Name
 	}
 
-	// Check if relationship already exists
-	for _, existing := range source.Relationships {
-		if existing.Resource == toResource && existing.Type == relType && existing.Via == via {
-			return // Relationship already exists
+	index := slices.IndexFunc(source.Relationships, func(existing models.Relationship) bool {
+		return existing.Resource == toResource && existing.Type == relType && existing.Via == via
+	})
+	if index < 0 {
+		gathered := rd.linkEvidence(resourceMap, fromResource, toResource, via)
+		source.Relationships = append(source.Relationships, models.Relationship{
+			Resource:    toResource,
+			Type:        relType,
+			Via:         via,
+			Description: rd.generateRelationshipDescription(fromResource, toResource, relType),
+			Cardinality: cardinality,
+			Required:    required,
+		})
+		index = len(source.Relationships) - 1
+		for _, e := range gathered {
+			addEvidence(&source.Relationships[index], e)
 		}
 	}
 
-	// Add new relationship
-	relationship := models.Relationship{
-		Resource:    toResource,
-		Type:        relType,
-		Via:         via,
-		Description: rd.generateRelationshipDescription(fromResource, toResource, relType),
-		Strength:    strength,
+	relationship := &source.Relationships[index]
+	for _, e := range evidence {
+		addEvidence(relationship, e)
+		rd.corroborate(resourceMap, fromResource, toResource, via, e)
 	}
+	return relationship
+}
 
-	source.Relationships = append(source.Relationships, relationship)
+// linkEvidence returns the evidence of the relationships between two
+// resources that describe the same link as via
+func (rd *RelationshipDetector) linkEvidence(resourceMap map[string]*models.Resource, a, b, via string) []models.Evidence {
+	var evidence []models.Evidence
+	for _, pair := range [][2]string{{a, b}, {b, a}} {
+		source := rd.findResource(resourceMap, pair[0])
+		if source == nil {
+			continue
+		}
+		for _, rel := range source.Relationships {
+			if rel.Resource == pair[1] && rd.sameLink(rel.Via, via, a, b) {
+				evidence = append(evidence, rel.Evidence...)
+			}
+		}
+	}
+	return evidence
+}
+
+// sameLink reports whether two vias between resources a and b describe the
+// same link: customer and customerId name the same property, and a foreign
+// key naming either resource repeats their path hierarchy
+func (rd *RelationshipDetector) sameLink(x, y, a, b string) bool {
+	if x == y {
+		return true
+	}
+	if y == pathHierarchyVia {
+		x, y = y, x
+	}
+	if x == pathHierarchyVia {
+		key := rd.linkKey(y)
+		return key == rd.names.Key(a) || key == rd.names.Key(b)
+	}
+	return rd.linkKey(x) == rd.linkKey(y)
+}
+
+// linkKey normalizes a via for comparison: the last property of a dotted
+// path without an Id or Ids suffix, e.g. "customer" for customerId
+func (rd *RelationshipDetector) linkKey(via string) string {
+	via = via[strings.LastIndex(via, ".")+1:]
+	if matches := rd.foreignKeysRegex.FindStringSubmatch(via); len(matches) > 1 {
+		via = matches[1]
+	} else if matches := rd.foreignKeyRegex.FindStringSubmatch(via); len(matches) > 1 {
+		via = matches[1]
+	}
+	return rd.names.Key(via)
+}
+
+// addEvidence records an observation on a relationship and rescores it
+func addEvidence(rel *models.Relationship, evidence models.Evidence) {
+	if !slices.Contains(rel.Evidence, evidence) {
+		rel.Evidence = append(rel.Evidence, evidence)
+	}
+	rel.Confidence = relationshipConfidence(rel.Evidence)
+	rel.Strength = relationshipStrength(rel.Confidence)
+}
+
+// relationshipConfidence combines the strongest evidence of each kind as
+// independent observations: 1 - (1 - w1)(1 - w2)... Repeating one kind of
+// evidence, such as the same nesting under many paths, adds nothing.
+func relationshipConfidence(evidence []models.Evidence) float64 {
+	strongest := make(map[string]float64)
+	for _, e := range evidence {
+		strongest[e.Kind] = math.Max(strongest[e.Kind], e.Weight)
+	}
+	doubt := 1.0
+	for _, weight := range strongest {
+		doubt *= 1 - weight
+	}
+	return math.Round((1-doubt)*100) / 100
+}
+
+// relationshipStrength labels a confidence as strong, medium or weak
+func relationshipStrength(confidence float64) string {
+	switch {
+	case confidence >= 0.8:
+		return "strong"
+	case confidence >= 0.5:
+		return "medium"
+	default:
+		return "weak"
+	}
+}
+
+// FilterRelationships removes relationships below a minimum confidence
+func FilterRelationships(resources []models.Resource, minConfidence float64) {
+	for i := range resources {
+		resources[i].Relationships = slices.DeleteFunc(resources[i].Relationships, func(rel models.Relationship) bool {
+			return rel.Confidence < minConfidence
+		})
+	}
 }
 
 // generateRelationshipDescription creates a human-readable description
@@ -269,12 +574,18 @@ func (rd *RelationshipDetector) generateRelationshipDescription(from, to, relTyp
 	switch relType {
 	case "has_many":
 		return from + " contains multiple " + to + " resources"
+	case "has_one":
+		return from + " has one " + to + " resource"
 	case "belongs_to":
 		return from + " belongs to a " + to + " resource"
 	case "references":
 		return from + " references a " + to + " resource"
 	case "referenced_by":
 		return from + " is referenced by " + to + " resources"
+	case "associated_with":
+		return from + " is associated with multiple " + to + " resources"
+	case "navigable_via":
+		return from + " links to " + to + " resources"
 	default:
 		return from + " has a " + relType + " relationship with " + to
 	}
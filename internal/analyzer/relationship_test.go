@@ -1,6 +1,8 @@
 package analyzer
 
 import (
+	"reflect"
+	"slices"
 	"testing"
 
 	"github.com/orchard9/api-godoc/internal/parser"
@@ -27,10 +29,10 @@ func TestRelationshipDetection(t *testing.T) {
 			},
 			expected: map[string][]models.Relationship{
 				"users": {
-					{Resource: "posts", Type: "has_many", Via: "path hierarchy", Strength: "strong"},
+					{Resource: "posts", Type: "has_many", Via: "path hierarchy", Strength: "strong", Cardinality: models.CardinalityOneToMany},
 				},
 				"posts": {
-					{Resource: "users", Type: "belongs_to", Via: "path hierarchy", Strength: "strong"},
+					{Resource: "users", Type: "belongs_to", Via: "path hierarchy", Strength: "strong", Cardinality: models.CardinalityManyToOne, Required: true},
 				},
 			},
 		},
@@ -51,10 +53,10 @@ func TestRelationshipDetection(t *testing.T) {
 			},
 			expected: map[string][]models.Relationship{
 				"orders": {
-					{Resource: "items", Type: "has_many", Via: "path hierarchy", Strength: "strong"},
+					{Resource: "items", Type: "has_many", Via: "path hierarchy", Strength: "strong", Cardinality: models.CardinalityOneToMany},
 				},
 				"items": {
-					{Resource: "orders", Type: "belongs_to", Via: "path hierarchy", Strength: "strong"},
+					{Resource: "orders", Type: "belongs_to", Via: "path hierarchy", Strength: "strong", Cardinality: models.CardinalityManyToOne, Required: true},
 				},
 			},
 		},
@@ -114,7 +116,9 @@ func TestRelationshipDetection(t *testing.T) {
 						if actualRel.Resource == expectedRel.Resource &&
 							actualRel.Type == expectedRel.Type &&
 							actualRel.Via == expectedRel.Via &&
-							actualRel.Strength == expectedRel.Strength {
+							actualRel.Strength == expectedRel.Strength &&
+							actualRel.Cardinality == expectedRel.Cardinality &&
+							actualRel.Required == expectedRel.Required {
 							found = true
 							break
 						}
@@ -128,6 +132,353 @@ func TestRelationshipDetection(t *testing.T) {
 	}
 }
 
+func TestRelationshipCardinality(t *testing.T) {
+	minOne := 1
+	spec := &parser.OpenAPISpec{
+		Paths: map[string]parser.PathItem{
+			"/orders":             {Get: &parser.Operation{}},
+			"/customers":          {Get: &parser.Operation{}},
+			"/products":           {Get: &parser.Operation{}},
+			"/lines":              {Get: &parser.Operation{}},
+			"/invoices":           {Get: &parser.Operation{}},
+			"/posts":              {Get: &parser.Operation{}},
+			"/tags":               {Get: &parser.Operation{}},
+			"/users/{id}/profile": {Get: &parser.Operation{}},
+		},
+		Components: &parser.Components{
+			Schemas: map[string]parser.Schema{
+				"Order": {
+					Type:     "object",
+					Required: []string{"customer", "lines"},
+					Properties: map[string]parser.Schema{
+						"customer":   {Ref: "#/components/schemas/Customer"},
+						"lines":      {Type: "array", MinItems: &minOne, Items: &parser.Schema{Ref: "#/components/schemas/Line"}},
+						"invoice":    {AllOf: []parser.Schema{{Ref: "#/components/schemas/Invoice"}}, Nullable: true},
+						"productIds": {Type: "array", Items: &parser.Schema{Type: "string"}},
+					},
+				},
+				"Invoice": {
+					Type: "object",
+					Properties: map[string]parser.Schema{
+						"order": {Ref: "#/components/schemas/Order"},
+					},
+				},
+				"Post": {
+					Type: "object",
+					Properties: map[string]parser.Schema{
+						"tags": {Type: "array", Items: &parser.Schema{Ref: "#/components/schemas/Tag"}},
+					},
+				},
+				"Tag": {
+					Type: "object",
+					Properties: map[string]parser.Schema{
+						"posts": {Type: "array", Items: &parser.Schema{Ref: "#/components/schemas/Post"}},
+					},
+				},
+			},
+		},
+	}
+
+	resources := NewResourceAnalyzer().ExtractResources(spec)
+	NewRelationshipDetector().DetectRelationships(resources, spec)
+
+	type key struct{ from, to, via string }
+	actual := make(map[key]models.Relationship)
+	for _, resource := range resources {
+		for _, rel := range resource.Relationships {
+			actual[key{resource.Name, rel.Resource, rel.Via}] = rel
+		}
+	}
+
+	tests := []struct {
+		from, to, via string
+		cardinality   string
+		required      bool
+	}{
+		{"orders", "customers", "customer", models.CardinalityManyToOne, true},
+		{"orders", "lines", "lines", models.CardinalityOneToMany, true},
+		{"lines", "orders", "lines", models.CardinalityManyToOne, false},
+		{"orders", "invoices", "invoice", models.CardinalityOneToOne, false},
+		{"invoices", "orders", "order", models.CardinalityOneToOne, false},
+		{"orders", "products", "productIds", models.CardinalityManyToMany, false},
+		{"posts", "tags", "tags", models.CardinalityManyToMany, false},
+		{"tags", "posts", "posts", models.CardinalityManyToMany, false},
+		{"users", "profile", "path hierarchy", models.CardinalityOneToOne, false},
+		{"profile", "users", "path hierarchy", models.CardinalityOneToOne, true},
+	}
+
+	for _, tt := range tests {
+		rel, ok := actual[key{tt.from, tt.to, tt.via}]
+		if !ok {
+			t.Errorf("Missing relationship %s -> %s via %s", tt.from, tt.to, tt.via)
+			continue
+		}
+		if rel.Cardinality != tt.cardinality || rel.Required != tt.required {
+			t.Errorf("%s -> %s via %s: expected %s (required %v), got %s (required %v)",
+				tt.from, tt.to, tt.via, tt.cardinality, tt.required, rel.Cardinality, rel.Required)
+		}
+	}
+	if rel := actual[key{"users", "profile", "path hierarchy"}]; rel.Type != "has_one" {
+		t.Errorf("Expected users has_one profile, got %s", rel.Type)
+	}
+}
+
+func TestRelationshipAssociations(t *testing.T) {
+	spec := &parser.OpenAPISpec{
+		Paths: map[string]parser.PathItem{
+			"/groups/{groupId}":                  {Get: &parser.Operation{}},
+			"/groups/{groupId}/members":          {Get: &parser.Operation{}},
+			"/groups/{groupId}/members/{userId}": {Put: &parser.Operation{}, Delete: &parser.Operation{}},
+			"/users/{userId}":                    {Get: &parser.Operation{}},
+			"/users/{userId}/posts/{postId}":     {Get: &parser.Operation{}},
+			"/projects":                          {Get: &parser.Operation{}},
+			"/assignments":                       {Post: &parser.Operation{}},
+			"/assignments/{assignmentId}":        {Delete: &parser.Operation{}},
+		},
+		Components: &parser.Components{
+			Schemas: map[string]parser.Schema{
+				"Assignment": {
+					Type: "object",
+					Properties: map[string]parser.Schema{
+						"id":        {Type: "string"},
+						"userId":    {Type: "string"},
+						"projectId": {Type: "string"},
+						"role":      {Type: "string"},
+						"createdAt": {Type: "string"},
+					},
+				},
+			},
+		},
+	}
+
+	resources := NewResourceAnalyzer().ExtractResources(spec)
+	NewRelationshipDetector().DetectRelationships(resources, spec)
+
+	actual := make(map[string][]models.Relationship)
+	for _, resource := range resources {
+		actual[resource.Name] = resource.Relationships
+	}
+
+	members := []string{"GET /groups/{groupId}/members", "PUT /groups/{groupId}/members/{userId}", "DELETE /groups/{groupId}/members/{userId}"}
+	assignments := []string{"POST /assignments", "DELETE /assignments/{assignmentId}"}
+	tests := []struct {
+		from, to    string
+		association string
+		operations  []string
+	}{
+		{"groups", "users", "members", members},
+		{"users", "groups", "members", members},
+		{"projects", "users", "assignments", assignments},
+		{"users", "projects", "assignments", assignments},
+	}
+
+	for _, tt := range tests {
+		var found *models.Relationship
+		for i, rel := range actual[tt.from] {
+			if rel.Resource == tt.to {
+				if found != nil {
+					t.Errorf("Expected a single relationship %s -> %s, got %+v", tt.from, tt.to, actual[tt.from])
+				}
+				found = &actual[tt.from][i]
+			}
+		}
+		if found == nil {
+			t.Errorf("Missing association %s -> %s", tt.from, tt.to)
+			continue
+		}
+		if found.Type != "associated_with" || found.Cardinality != models.CardinalityManyToMany || found.Association != tt.association {
+			t.Errorf("%s -> %s: expected many-to-many through %s, got %+v", tt.from, tt.to, tt.association, *found)
+		}
+		if !reflect.DeepEqual(found.Operations, tt.operations) {
+			t.Errorf("%s -> %s: expected operations %v, got %v", tt.from, tt.to, tt.operations, found.Operations)
+		}
+	}
+
+	// Join resources no longer chain the sides together
+	for _, join := range []string{"members", "assignments"} {
+		if len(actual[join]) != 0 {
+			t.Errorf("Expected %s to have no relationships, got %+v", join, actual[join])
+		}
+	}
+	// Plain nesting is not an association
+	if rels := actual["posts"]; len(rels) != 1 || rels[0].Type != "belongs_to" || rels[0].Resource != "users" {
+		t.Errorf("Expected posts to belong to users, got %+v", rels)
+	}
+}
+
+func TestRelationshipEvidence(t *testing.T) {
+	spec := &parser.OpenAPISpec{
+		Paths: map[string]parser.PathItem{
+			"/orders/{orderId}":                {Get: &parser.Operation{}},
+			"/orders/{orderId}/items":          {Get: &parser.Operation{}},
+			"/orders/{orderId}/items/{itemId}": {Get: &parser.Operation{}},
+			"/customers":                       {Get: &parser.Operation{}},
+			"/users/{userId}":                  {Get: &parser.Operation{}},
+			"/users/{userId}/posts":            {Get: &parser.Operation{}},
+		},
+		Components: &parser.Components{
+			Schemas: map[string]parser.Schema{
+				"Order": {
+					Type: "object",
+					Properties: map[string]parser.Schema{
+						"customerId": {Type: "string"},
+					},
+				},
+				"Post": {
+					Type: "object",
+					Properties: map[string]parser.Schema{
+						"userId":   {Type: "string"},
+						"author":   {Ref: "#/components/schemas/User"},
+						"reviewer": {Ref: "#/components/schemas/User"},
+					},
+				},
+				"User": {Type: "object", Properties: map[string]parser.Schema{"name": {Type: "string"}}},
+			},
+		},
+	}
+
+	resources := NewResourceAnalyzer().ExtractResources(spec)
+	NewRelationshipDetector().DetectRelationships(resources, spec)
+
+	relationships := make(map[string]models.Relationship)
+	for _, resource := range resources {
+		for _, rel := range resource.Relationships {
+			key := resource.Name + " " + rel.Type + " " + rel.Resource + " via " + rel.Via
+			if _, exists := relationships[key]; exists {
+				t.Errorf("Expected one %s relationship", key)
+			}
+			relationships[key] = rel
+		}
+	}
+
+	postOwner := []models.Evidence{
+		{Kind: models.EvidencePathHierarchy, Source: "/users/{userId}/posts", Weight: 0.9},
+		{Kind: models.EvidenceParameter, Source: "GET /users/{userId}/posts", Weight: 0.6},
+		{Kind: models.EvidenceNaming, Source: "#/components/schemas/Post/properties/userId", Weight: 0.5},
+	}
+
+	tests := []struct {
+		name       string
+		confidence float64
+		strength   string
+		evidence   []models.Evidence
+	}{
+		{
+			// Nested paths and their parameters corroborate each other
+			name:       "items belongs_to orders via path hierarchy",
+			confidence: 0.96,
+			strength:   "strong",
+			evidence: []models.Evidence{
+				{Kind: models.EvidencePathHierarchy, Source: "/orders/{orderId}/items", Weight: 0.9},
+				{Kind: models.EvidencePathHierarchy, Source: "/orders/{orderId}/items/{itemId}", Weight: 0.9},
+				{Kind: models.EvidenceParameter, Source: "GET /orders/{orderId}/items", Weight: 0.6},
+				{Kind: models.EvidenceParameter, Source: "GET /orders/{orderId}/items/{itemId}", Weight: 0.6},
+			},
+		},
+		{
+			name:       "orders references customers via customerId",
+			confidence: 0.5,
+			strength:   "medium",
+			evidence: []models.Evidence{
+				{Kind: models.EvidenceNaming, Source: "#/components/schemas/Order/properties/customerId", Weight: 0.5},
+			},
+		},
+		{
+			// The userId property is the foreign key of the nesting, so
+			// the path and the property support the same link
+			name:       "posts belongs_to users via path hierarchy",
+			confidence: 0.98,
+			strength:   "strong",
+			evidence:   postOwner,
+		},
+		{
+			name:       "posts references users via userId",
+			confidence: 0.98,
+			strength:   "strong",
+			evidence:   postOwner,
+		},
+		{
+			// Other properties are other links and keep their own evidence
+			name:       "posts references users via author",
+			confidence: 0.8,
+			strength:   "strong",
+			evidence: []models.Evidence{
+				{Kind: models.EvidenceSchemaRef, Source: "#/components/schemas/Post/properties/author", Weight: 0.8},
+			},
+		},
+		{
+			name:       "posts references users via reviewer",
+			confidence: 0.8,
+			strength:   "strong",
+			evidence: []models.Evidence{
+				{Kind: models.EvidenceSchemaRef, Source: "#/components/schemas/Post/properties/reviewer", Weight: 0.8},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rel, ok := relationships[tt.name]
+			if !ok {
+				t.Fatalf("Missing relationship %s", tt.name)
+			}
+			if rel.Confidence != tt.confidence || rel.Strength != tt.strength {
+				t.Errorf("Expected confidence %v (%s), got %v (%s)", tt.confidence, tt.strength, rel.Confidence, rel.Strength)
+			}
+			if len(rel.Evidence) != len(tt.evidence) {
+				t.Errorf("Expected evidence %+v, got %+v", tt.evidence, rel.Evidence)
+			}
+			for _, e := range tt.evidence {
+				if !slices.Contains(rel.Evidence, e) {
+					t.Errorf("Missing evidence %+v in %+v", e, rel.Evidence)
+				}
+			}
+		})
+	}
+}
+
+func TestRelationshipConfidence(t *testing.T) {
+	tests := []struct {
+		name       string
+		weights    map[string][]float64
+		confidence float64
+		strength   string
+	}{
+		{"no evidence", nil, 0, "weak"},
+		{"single naming match", map[string][]float64{models.EvidenceNaming: {0.5}}, 0.5, "medium"},
+		{"repeated kind counts once", map[string][]float64{models.EvidencePathHierarchy: {0.9, 0.9, 0.9}}, 0.9, "strong"},
+		{"independent kinds combine", map[string][]float64{models.EvidenceSchemaRef: {0.8}, models.EvidenceNaming: {0.5}}, 0.9, "strong"},
+		{"weak evidence", map[string][]float64{models.EvidenceNaming: {0.3}}, 0.3, "weak"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var evidence []models.Evidence
+			for kind, weights := range tt.weights {
+				for _, weight := range weights {
+					evidence = append(evidence, models.Evidence{Kind: kind, Weight: weight})
+				}
+			}
+			confidence := relationshipConfidence(evidence)
+			if confidence != tt.confidence || relationshipStrength(confidence) != tt.strength {
+				t.Errorf("Expected %v (%s), got %v (%s)", tt.confidence, tt.strength, confidence, relationshipStrength(confidence))
+			}
+		})
+	}
+
+	resources := []models.Resource{{
+		Name: "orders",
+		Relationships: []models.Relationship{
+			{Resource: "customers", Confidence: 0.9},
+			{Resource: "stores", Confidence: 0.4},
+		},
+	}}
+	FilterRelationships(resources, 0.5)
+	if rels := resources[0].Relationships; len(rels) != 1 || rels[0].Resource != "customers" {
+		t.Errorf("Expected only the customers relationship, got %+v", rels)
+	}
+}
+
 func TestPathSegmentExtraction(t *testing.T) {
 	tests := []struct {
 		name     string
@@ -278,7 +629,7 @@ func TestSchemaNameConversion(t *testing.T) {
 		{
 			name:         "schema with Request suffix",
 			schemaName:   "CreateProjectRequest",
-			expectedName: "createproject",
+			expectedName: "create-project",
 		},
 		{
 			name:         "schema with Model suffix",
@@ -295,6 +646,11 @@ func TestSchemaNameConversion(t *testing.T) {
 			schemaName:   "ProductDTO",
 			expectedName: "product",
 		},
+		{
+			name:         "gRPC gateway schema",
+			schemaName:   "v1ApiKey",
+			expectedName: "api-key",
+		},
 	}
 
 	detector := NewRelationshipDetector()
@@ -4,6 +4,7 @@ import (
 	"regexp"
 	"strings"
 
+	"github.com/orchard9/api-godoc/internal/config"
 	"github.com/orchard9/api-godoc/internal/parser"
 	"github.com/orchard9/api-godoc/pkg/models"
 )
@@ -11,15 +12,31 @@ import (
 // ResourceAnalyzer handles resource extraction from OpenAPI paths
 type ResourceAnalyzer struct {
 	pathVariableRegex *regexp.Regexp
+	strategy          GroupingStrategy
+	separateNested    bool
+	names             *NameNormalizer
+	segments          *SegmentRules
 }
 
 // NewResourceAnalyzer creates a new resource analyzer
 func NewResourceAnalyzer() *ResourceAnalyzer {
 	return &ResourceAnalyzer{
 		pathVariableRegex: regexp.MustCompile(`\{[^}]+\}`),
+		strategy:          pathStrategy{},
+		names:             NewNameNormalizer(config.NamingConfig{}),
+		segments:          &SegmentRules{},
 	}
 }
 
+// SetGroupingStrategy changes how operations are assigned to resources.
+// A nil strategy restores path-based grouping.
+func (ra *ResourceAnalyzer) SetGroupingStrategy(strategy GroupingStrategy) {
+	if strategy == nil {
+		strategy = pathStrategy{}
+	}
+	ra.strategy = strategy
+}
+
 // ExtractResources analyzes OpenAPI paths to identify business resources
 func (ra *ResourceAnalyzer) ExtractResources(spec *parser.OpenAPISpec) []models.Resource {
 	resourceMap := make(map[string]*models.Resource)
@@ -27,19 +44,42 @@ func (ra *ResourceAnalyzer) ExtractResources(spec *parser.OpenAPISpec) []models.
 	// First pass: analyze all paths to identify true resources vs actions
 	resourcePatterns := ra.analyzePathPatterns(spec.Paths)
 
-	// Process each path in the spec
+	// Process each operation in the spec
 	for path, pathItem := range spec.Paths {
-		resourceNames := ra.extractResourcesForPath(path, resourcePatterns)
+		pathResources := ra.qualifyResources(path, ra.extractResourcesForPath(path, resourcePatterns))
+
+		for _, mo := range pathItemOperations(pathItem) {
+			resourceNames := ra.strategy.Resources(OperationContext{
+				Path:          path,
+				Method:        mo.method,
+				Operation:     mo.operation,
+				PathResources: pathResources,
+			})
+			// Strict strategies keep operations they cannot place visible
+			if len(resourceNames) == 0 && len(pathResources) > 0 {
+				resourceNames = []string{ungroupedResource}
+			}
 
-		// Add operations to the appropriate resources
-		for _, resourceName := range resourceNames {
-			ra.addOperationsToResource(resourceMap, resourceName, path, pathItem)
+			// Add the operation to the appropriate resources
+			for _, resourceName := range resourceNames {
+				ra.addOperationToResource(resourceMap, resourceName, path, mo, pathItem.Parameters, spec)
+			}
 		}
 	}
 
+	// Classify operations as list, get, create, ... from path shape and schemas
+	ra.classifyOperations(resourceMap, spec.Paths)
+
+	// Link nested resources and derive canonical paths
+	ra.buildHierarchy(resourceMap, spec.Paths)
+
+	// Merge singular/plural and casing variants of the same resource
+	resourceMap = ra.normalizeResources(resourceMap)
+
 	// Convert map to slice
 	resources := make([]models.Resource, 0, len(resourceMap))
 	for _, resource := range resourceMap {
+		resource.IsCollection = isCollectionResource(*resource)
 		resources = append(resources, *resource)
 	}
 
@@ -60,8 +100,8 @@ func (ra *ResourceAnalyzer) analyzePathPatterns(paths map[string]parser.PathItem
 		// Extract all potential resource names from the path
 		segments := ra.extractResourceNames(path)
 
-		// Check if this path has variables
-		hasVariables := ra.pathVariableRegex.MatchString(path)
+		// Check if this path has variables outside any stripped prefix
+		hasVariables := ra.pathVariableRegex.MatchString(strings.Join(ra.analysisSegments(path), "/"))
 
 		// Update patterns for each resource segment
 		for _, segment := range segments {
@@ -84,18 +124,11 @@ func (ra *ResourceAnalyzer) analyzePathPatterns(paths map[string]parser.PathItem
 
 // extractResourceNames identifies resource names from a path
 func (ra *ResourceAnalyzer) extractResourceNames(path string) []string {
-	// Remove path variables (e.g., {id}, {user_id})
-	cleanPath := ra.pathVariableRegex.ReplaceAllString(path, "")
-
-	// Split by slashes and filter out empty strings
-	segments := strings.Split(cleanPath, "/")
 	var resourceNames []string
 
-	for _, segment := range segments {
-		segment = strings.TrimSpace(segment)
+	for _, segment := range ra.analysisSegments(path) {
+		segment = ra.resourceSegment(segment)
 		if segment != "" && ra.isResourceName(segment) {
-			// Handle action syntax like "tasks/{id}:move" -> extract "move" from ":move"
-			segment = strings.TrimPrefix(segment, ":")
 			resourceNames = append(resourceNames, segment)
 		}
 	}
@@ -103,6 +136,15 @@ func (ra *ResourceAnalyzer) extractResourceNames(path string) []string {
 	return resourceNames
 }
 
+// resourceSegment removes path variables and custom method suffixes from a
+// path segment, so "{id}:publish" is empty and "tasks:batch-get" is "tasks"
+// and both operations stay on their parent resource
+func (ra *ResourceAnalyzer) resourceSegment(segment string) string {
+	segment = ra.pathVariableRegex.ReplaceAllString(segment, "")
+	segment, _, _ = strings.Cut(segment, ":")
+	return strings.TrimSpace(segment)
+}
+
 // extractResourcesForPath determines which resources a path should belong to
 func (ra *ResourceAnalyzer) extractResourcesForPath(path string, patterns map[string]*PathPattern) []string {
 	segments := ra.extractResourceNames(path)
@@ -118,7 +160,7 @@ func (ra *ResourceAnalyzer) extractResourcesForPath(path string, patterns map[st
 	// 3. For true resources with variables, create all resources in the chain
 	// 4. For non-nested paths, prioritize the most specific resource
 
-	pathSegments := strings.Split(strings.Trim(path, "/"), "/")
+	pathSegments := ra.analysisSegments(path)
 	resourcePositions := ra.findResourcePositions(pathSegments)
 
 	// If we only have one resource segment, it's straightforward
@@ -151,10 +193,7 @@ func (ra *ResourceAnalyzer) extractResourcesForPath(path string, patterns map[st
 func (ra *ResourceAnalyzer) findResourcePositions(pathSegments []string) []int {
 	var resourcePositions []int
 	for i, pathSegment := range pathSegments {
-		cleanSegment := ra.pathVariableRegex.ReplaceAllString(pathSegment, "")
-		cleanSegment = strings.TrimPrefix(cleanSegment, ":")
-		cleanSegment = strings.TrimSpace(cleanSegment)
-
+		cleanSegment := ra.resourceSegment(pathSegment)
 		if cleanSegment != "" && ra.isResourceName(cleanSegment) {
 			resourcePositions = append(resourcePositions, i)
 		}
@@ -209,23 +248,12 @@ func (ra *ResourceAnalyzer) selectPrimaryResource(segments []string, patterns ma
 
 // isResourceName determines if a path segment represents a resource
 func (ra *ResourceAnalyzer) isResourceName(segment string) bool {
-	// Skip common non-resource segments
-	skipSegments := map[string]bool{
-		"api":     true,
-		"v1":      true,
-		"v2":      true,
-		"v3":      true,
-		"version": true,
-		"health":  true,
-		"status":  true,
-		"ping":    true,
-	}
-
-	return !skipSegments[segment]
+	reason, _ := ra.segments.skipReason(segment)
+	return reason == ""
 }
 
-// addOperationsToResource adds operations from a PathItem to a single resource
-func (ra *ResourceAnalyzer) addOperationsToResource(resourceMap map[string]*models.Resource, resourceName string, path string, pathItem parser.PathItem) {
+// addOperationToResource adds a single operation to a resource
+func (ra *ResourceAnalyzer) addOperationToResource(resourceMap map[string]*models.Resource, resourceName string, path string, mo methodOperation, pathParams []parser.Parameter, spec *parser.OpenAPISpec) {
 	// Skip empty resource names
 	if resourceName == "" {
 		return
@@ -240,39 +268,26 @@ func (ra *ResourceAnalyzer) addOperationsToResource(resourceMap map[string]*mode
 		}
 	}
 
-	// Add all operations from the path item
-	if pathItem.Get != nil {
-		resourceMap[resourceName].Operations = append(resourceMap[resourceName].Operations, ra.createOperation("GET", path, pathItem.Get))
-	}
-	if pathItem.Post != nil {
-		resourceMap[resourceName].Operations = append(resourceMap[resourceName].Operations, ra.createOperation("POST", path, pathItem.Post))
-	}
-	if pathItem.Put != nil {
-		resourceMap[resourceName].Operations = append(resourceMap[resourceName].Operations, ra.createOperation("PUT", path, pathItem.Put))
-	}
-	if pathItem.Delete != nil {
-		resourceMap[resourceName].Operations = append(resourceMap[resourceName].Operations, ra.createOperation("DELETE", path, pathItem.Delete))
-	}
-	if pathItem.Patch != nil {
-		resourceMap[resourceName].Operations = append(resourceMap[resourceName].Operations, ra.createOperation("PATCH", path, pathItem.Patch))
-	}
-	if pathItem.Head != nil {
-		resourceMap[resourceName].Operations = append(resourceMap[resourceName].Operations, ra.createOperation("HEAD", path, pathItem.Head))
-	}
-	if pathItem.Options != nil {
-		resourceMap[resourceName].Operations = append(resourceMap[resourceName].Operations, ra.createOperation("OPTIONS", path, pathItem.Options))
-	}
+	resourceMap[resourceName].Operations = append(resourceMap[resourceName].Operations,
+		ra.createOperation(mo.method, path, mo.operation, pathParams, spec))
 }
 
-// createOperation converts a parser.Operation to models.Operation
-func (ra *ResourceAnalyzer) createOperation(method, path string, op *parser.Operation) models.Operation {
-	return models.Operation{
+// createOperation converts a parser.Operation to models.Operation, resolving
+// parameter, request body and response references against the spec
+func (ra *ResourceAnalyzer) createOperation(method, path string, op *parser.Operation, pathParams []parser.Parameter, spec *parser.OpenAPISpec) models.Operation {
+	operation := models.Operation{
 		Method:      method,
 		Path:        path,
 		Summary:     op.Summary,
 		Description: op.Description,
 		OperationID: op.OperationID,
+		Tags:        op.Tags,
+		Deprecated:  op.Deprecated,
 	}
+
+	ra.buildOperationDetails(&operation, op, pathParams, spec)
+
+	return operation
 }
 
 // generateResourceDescription creates a basic description for a resource
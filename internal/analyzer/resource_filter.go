@@ -13,6 +13,10 @@ type ResourceFilter struct {
 	Include []string // Explicit list of resources to include
 	Exclude []string // List of resources to exclude
 	Pattern string   // Regex pattern to match resource names
+
+	Incomplete        bool     // Only keep resources with capability gaps
+	IncludeCategories []string // Only keep resources in these categories
+	ExcludeCategories []string // Drop resources in these categories
 }
 
 // ResourceFilterer filters resources based on criteria
@@ -30,7 +34,8 @@ func NewResourceFilterer() ResourceFilterer {
 // FilterResources filters resources based on the provided criteria
 func (rf *resourceFilterer) FilterResources(resources []models.Resource, filter *ResourceFilter) []models.Resource {
 	// No filter means include all
-	if filter == nil || (len(filter.Include) == 0 && len(filter.Exclude) == 0 && filter.Pattern == "") {
+	if filter == nil || (len(filter.Include) == 0 && len(filter.Exclude) == 0 && filter.Pattern == "" &&
+		!filter.Incomplete && len(filter.IncludeCategories) == 0 && len(filter.ExcludeCategories) == 0) {
 		return resources
 	}
 
@@ -55,11 +60,9 @@ func (rf *resourceFilterer) FilterResources(resources []models.Resource, filter
 	// Filter resources
 	var filtered []models.Resource
 	for _, resource := range resources {
-		resourceNameLower := strings.ToLower(resource.Name)
-
-		// Check if explicitly included
+		// Check if explicitly included, by name or by a merged alias
 		if len(includeMap) > 0 {
-			if includeMap[resourceNameLower] {
+			if matchesAny(includeMap, resource) {
 				filtered = append(filtered, resource)
 				continue
 			}
@@ -68,7 +71,7 @@ func (rf *resourceFilterer) FilterResources(resources []models.Resource, filter
 		// Check if matches pattern
 		if patternRegex != nil && patternRegex.MatchString(resource.Name) {
 			// Check if not excluded
-			if !excludeMap[resourceNameLower] {
+			if !matchesAny(excludeMap, resource) {
 				filtered = append(filtered, resource)
 			}
 			continue
@@ -76,12 +79,68 @@ func (rf *resourceFilterer) FilterResources(resources []models.Resource, filter
 
 		// If no include list or pattern, include unless excluded
 		if len(includeMap) == 0 && patternRegex == nil {
-			if !excludeMap[resourceNameLower] {
+			if !matchesAny(excludeMap, resource) {
 				filtered = append(filtered, resource)
 			}
 		}
 	}
 
+	if filter.Incomplete {
+		filtered = incompleteResources(filtered)
+	}
+	if len(filter.IncludeCategories) > 0 || len(filter.ExcludeCategories) > 0 {
+		filtered = filterCategories(filtered, filter.IncludeCategories, filter.ExcludeCategories)
+	}
+
+	return filtered
+}
+
+// incompleteResources keeps resources whose capability matrix row has gaps
+func incompleteResources(resources []models.Resource) []models.Resource {
+	var incomplete []models.Resource
+	for _, resource := range resources {
+		if len(ResourceCapabilities(resource).Gaps) > 0 {
+			incomplete = append(incomplete, resource)
+		}
+	}
+	return incomplete
+}
+
+// matchesAny reports whether a resource name or alias is in a lowercase name set
+func matchesAny(names map[string]bool, resource models.Resource) bool {
+	if names[strings.ToLower(resource.Name)] {
+		return true
+	}
+	for _, alias := range resource.Aliases {
+		if names[strings.ToLower(alias)] {
+			return true
+		}
+	}
+	return false
+}
+
+// filterCategories keeps resources in the included categories and outside the excluded ones
+func filterCategories(resources []models.Resource, include, exclude []string) []models.Resource {
+	toSet := func(names []string) map[string]bool {
+		set := make(map[string]bool, len(names))
+		for _, name := range names {
+			set[strings.ToLower(name)] = true
+		}
+		return set
+	}
+	includeSet, excludeSet := toSet(include), toSet(exclude)
+
+	var filtered []models.Resource
+	for _, resource := range resources {
+		category := strings.ToLower(resource.Category)
+		if len(includeSet) > 0 && !includeSet[category] {
+			continue
+		}
+		if excludeSet[category] {
+			continue
+		}
+		filtered = append(filtered, resource)
+	}
 	return filtered
 }
 
@@ -113,6 +172,18 @@ func (f *ResourceFilter) String() string {
 		parts = append(parts, fmt.Sprintf("pattern: %s", f.Pattern))
 	}
 
+	if f.Incomplete {
+		parts = append(parts, "incomplete only")
+	}
+
+	if len(f.IncludeCategories) > 0 {
+		parts = append(parts, fmt.Sprintf("categories: %s", strings.Join(f.IncludeCategories, ", ")))
+	}
+
+	if len(f.ExcludeCategories) > 0 {
+		parts = append(parts, fmt.Sprintf("exclude categories: %s", strings.Join(f.ExcludeCategories, ", ")))
+	}
+
 	if len(parts) == 0 {
 		return "no filter"
 	}
@@ -207,3 +207,88 @@ func TestResourceFilterValidation(t *testing.T) {
 		})
 	}
 }
+
+func TestResourceFilterMatchesAliases(t *testing.T) {
+	resources := []models.Resource{
+		{Name: "api-keys", Aliases: []string{"apiKeys"}},
+		{Name: "users", Aliases: []string{"user"}},
+	}
+
+	filterer := NewResourceFilterer()
+
+	included := filterer.FilterResources(resources, &ResourceFilter{Include: []string{"apiKeys"}})
+	if len(included) != 1 || included[0].Name != "api-keys" {
+		t.Errorf("Expected include by alias to keep api-keys, got %+v", included)
+	}
+
+	excluded := filterer.FilterResources(resources, &ResourceFilter{Exclude: []string{"user"}})
+	if len(excluded) != 1 || excluded[0].Name != "api-keys" {
+		t.Errorf("Expected exclude by alias to drop users, got %+v", excluded)
+	}
+}
+
+func TestResourceFilterIncomplete(t *testing.T) {
+	resources := []models.Resource{
+		{Name: "users", Operations: []models.Operation{
+			{Method: "POST", Path: "/users", Kind: models.KindCreate},
+			{Method: "GET", Path: "/users/{id}", Kind: models.KindGet},
+			{Method: "DELETE", Path: "/users/{id}", Kind: models.KindDelete},
+		}},
+		{Name: "orders", Operations: []models.Operation{
+			{Method: "POST", Path: "/orders", Kind: models.KindCreate},
+			{Method: "GET", Path: "/orders/{id}", Kind: models.KindGet},
+		}},
+		{Name: "carts", Operations: []models.Operation{
+			{Method: "POST", Path: "/carts", Kind: models.KindCreate},
+		}},
+	}
+
+	filterer := NewResourceFilterer()
+
+	filtered := filterer.FilterResources(resources, &ResourceFilter{Incomplete: true})
+	if len(filtered) != 2 || filtered[0].Name != "orders" || filtered[1].Name != "carts" {
+		t.Errorf("Expected only incomplete orders and carts, got %+v", filtered)
+	}
+
+	filtered = filterer.FilterResources(resources, &ResourceFilter{Incomplete: true, Exclude: []string{"carts"}})
+	if len(filtered) != 1 || filtered[0].Name != "orders" {
+		t.Errorf("Expected incomplete filter to combine with exclude, got %+v", filtered)
+	}
+}
+
+func TestResourceFilterCategories(t *testing.T) {
+	resources := []models.Resource{
+		{Name: "users", Category: "core"},
+		{Name: "api-keys", Category: "auth"},
+		{Name: "health", Category: "ops"},
+	}
+
+	tests := []struct {
+		name   string
+		filter ResourceFilter
+		want   []string
+	}{
+		{"include", ResourceFilter{IncludeCategories: []string{"core", "Auth"}}, []string{"users", "api-keys"}},
+		{"exclude", ResourceFilter{ExcludeCategories: []string{"ops"}}, []string{"users", "api-keys"}},
+		{"with names", ResourceFilter{Include: []string{"users", "health"}, ExcludeCategories: []string{"ops"}}, []string{"users"}},
+	}
+
+	filterer := NewResourceFilterer()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filtered := filterer.FilterResources(resources, &tt.filter)
+			var names []string
+			for _, resource := range filtered {
+				names = append(names, resource.Name)
+			}
+			if len(names) != len(tt.want) {
+				t.Fatalf("Expected %v, got %v", tt.want, names)
+			}
+			for i := range names {
+				if names[i] != tt.want[i] {
+					t.Errorf("Expected %v, got %v", tt.want, names)
+				}
+			}
+		})
+	}
+}
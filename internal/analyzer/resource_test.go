@@ -152,7 +152,8 @@ func TestExtractResourceNames(t *testing.T) {
 		{"/v1/auth/api-keys", []string{"auth", "api-keys"}},
 		{"/v1/auth/api-keys/{id}", []string{"auth", "api-keys"}},
 		{"/v1/auth/login", []string{"auth", "login"}},
-		{"/api/v1/tasks/{id}:move", []string{"tasks", "move"}},
+		{"/api/v1/tasks/{id}:move", []string{"tasks"}},
+		{"/api/v1/tasks:batch-get", []string{"tasks"}},
 		{"/v1/projects/{id}/audits", []string{"projects", "audits"}},
 		{"/organizations/{org_id}/projects/{project_id}/tasks/{task_id}/comments", []string{"organizations", "projects", "tasks", "comments"}},
 		{"/api/v1/organizations/{org_id}/projects/{project_id}/tasks/{task_id}/comments/{comment_id}/reactions", []string{"organizations", "projects", "tasks", "comments", "reactions"}},
@@ -156,7 +156,8 @@ func (sr *schemaReducer) isTechnicalField(name string, schema *parser.Schema) bo
 		return true
 	}
 
-	// Filter links/embedded fields (HAL/HATEOAS)
+	// Filter links/embedded fields (HAL/HATEOAS); the relationship detector
+	// reports them as navigable_via relationships
 	if nameLower == "links" || nameLower == "embedded" ||
 		name == "_links" || name == "_embedded" {
 		return true
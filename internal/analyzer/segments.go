@@ -0,0 +1,189 @@
+package analyzer
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/orchard9/api-godoc/internal/config"
+	"github.com/orchard9/api-godoc/internal/parser"
+	"github.com/orchard9/api-godoc/pkg/models"
+)
+
+// Reasons a path segment is not treated as a resource
+const (
+	SkipReasonVersion  = "version"  // API version such as v1, v4beta or 2024-01-01
+	SkipReasonReserved = "reserved" // built-in non-resource word such as api or health
+	SkipReasonPattern  = "pattern"  // configured skip pattern
+	SkipReasonPrefix   = "prefix"   // configured path prefix
+)
+
+// reservedSegments are words that never name a resource
+var reservedSegments = map[string]bool{
+	"api":     true,
+	"version": true,
+	"health":  true,
+	"status":  true,
+	"ping":    true,
+}
+
+// versionSegmentRegex matches vN, vNalpha, vNbetaN, vN.N and date versions
+var versionSegmentRegex = regexp.MustCompile(`^(?i:v\d+(\.\d+)*((alpha|beta|rc)\d*)?|\d{4}-\d{2}(-\d{2})?)$`)
+
+// SegmentRules decides which path segments are resources
+type SegmentRules struct {
+	skip     []*regexp.Regexp
+	patterns []string
+	prefixes [][]string
+}
+
+// NewSegmentRules creates rules from the built-in defaults and config additions
+func NewSegmentRules(segments config.SegmentConfig) (*SegmentRules, error) {
+	rules := &SegmentRules{}
+
+	for _, pattern := range segments.Skip {
+		re, err := regexp.Compile("^(?:" + pattern + ")$")
+		if err != nil {
+			return nil, fmt.Errorf("invalid skip pattern %q: %w", pattern, err)
+		}
+		rules.skip = append(rules.skip, re)
+		rules.patterns = append(rules.patterns, pattern)
+	}
+
+	for _, prefix := range segments.StripPrefixes {
+		parts := splitPath(prefix)
+		if len(parts) == 0 {
+			return nil, fmt.Errorf("invalid strip prefix %q: prefix is empty", prefix)
+		}
+		rules.prefixes = append(rules.prefixes, parts)
+	}
+	// Longer prefixes win over their own prefixes
+	sort.SliceStable(rules.prefixes, func(i, j int) bool {
+		return len(rules.prefixes[i]) > len(rules.prefixes[j])
+	})
+
+	return rules, nil
+}
+
+// skipReason returns why a literal segment is not a resource, with the
+// matching rule for configured patterns, or "" when it is a resource
+func (r *SegmentRules) skipReason(segment string) (reason, rule string) {
+	if versionSegmentRegex.MatchString(segment) {
+		return SkipReasonVersion, ""
+	}
+	if reservedSegments[strings.ToLower(segment)] {
+		return SkipReasonReserved, ""
+	}
+	for i, re := range r.skip {
+		if re.MatchString(segment) {
+			return SkipReasonPattern, r.patterns[i]
+		}
+	}
+	return "", ""
+}
+
+// stripPrefix finds the configured prefix at the start of a path and returns
+// the range of segments it covers. The prefix may follow skipped segments, so
+// /tenants/{tenant} also matches /rest/v4beta/tenants/{tenant}. Variable
+// prefix segments such as {tenant} match any segment.
+func (r *SegmentRules) stripPrefix(segments []string) (start, end int, rule string) {
+	for start = 0; start < len(segments); start++ {
+		for _, prefix := range r.prefixes {
+			if matchesPrefix(segments[start:], prefix) {
+				return start, start + len(prefix), "/" + strings.Join(prefix, "/")
+			}
+		}
+		if reason, _ := r.skipReason(segments[start]); reason == "" {
+			break
+		}
+	}
+	return 0, 0, ""
+}
+
+// matchesPrefix reports whether segments start with a prefix
+func matchesPrefix(segments, prefix []string) bool {
+	if len(prefix) > len(segments) {
+		return false
+	}
+	for i, part := range prefix {
+		if !strings.HasPrefix(part, "{") && part != segments[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// splitPath splits a path into its non-empty segments
+func splitPath(path string) []string {
+	var segments []string
+	for _, segment := range strings.Split(path, "/") {
+		if segment != "" {
+			segments = append(segments, segment)
+		}
+	}
+	return segments
+}
+
+// SetSegmentRules changes which path segments are treated as resources.
+// Nil rules restore the built-in defaults.
+func (ra *ResourceAnalyzer) SetSegmentRules(rules *SegmentRules) {
+	if rules == nil {
+		rules = &SegmentRules{}
+	}
+	ra.segments = rules
+}
+
+// analysisSegments returns the path segments considered for resources, with
+// any configured prefix removed
+func (ra *ResourceAnalyzer) analysisSegments(path string) []string {
+	segments := splitPath(path)
+	_, end, _ := ra.segments.stripPrefix(segments)
+	return segments[end:]
+}
+
+// SkippedSegments reports the path segments that were not treated as
+// resources, why, and how many paths contain each of them
+func (ra *ResourceAnalyzer) SkippedSegments(spec *parser.OpenAPISpec) []models.SkippedSegment {
+	counts := make(map[models.SkippedSegment]int)
+
+	for path := range spec.Paths {
+		seen := make(map[models.SkippedSegment]bool)
+		segments := splitPath(path)
+
+		start, end, prefix := ra.segments.stripPrefix(segments)
+		if end > 0 {
+			seen[models.SkippedSegment{Segment: prefix, Reason: SkipReasonPrefix, Rule: prefix}] = true
+		}
+
+		for i, segment := range segments {
+			if i >= start && i < end {
+				continue
+			}
+			literal := strings.TrimSpace(ra.pathVariableRegex.ReplaceAllString(segment, ""))
+			if literal == "" || strings.Contains(literal, ":") {
+				continue
+			}
+			if reason, rule := ra.segments.skipReason(literal); reason != "" {
+				seen[models.SkippedSegment{Segment: literal, Reason: reason, Rule: rule}] = true
+			}
+		}
+
+		for skipped := range seen {
+			counts[skipped]++
+		}
+	}
+
+	skipped := make([]models.SkippedSegment, 0, len(counts))
+	for segment, paths := range counts {
+		segment.Paths = paths
+		skipped = append(skipped, segment)
+	}
+	sort.Slice(skipped, func(i, j int) bool {
+		if skipped[i].Paths != skipped[j].Paths {
+			return skipped[i].Paths > skipped[j].Paths
+		}
+		return skipped[i].Segment < skipped[j].Segment
+	})
+	return skipped
+}
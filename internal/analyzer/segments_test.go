@@ -0,0 +1,167 @@
+package analyzer
+
+import (
+	"reflect"
+	"sort"
+	"testing"
+
+	"github.com/orchard9/api-godoc/internal/config"
+	"github.com/orchard9/api-godoc/internal/parser"
+	"github.com/orchard9/api-godoc/pkg/models"
+)
+
+func TestNewSegmentRulesValidation(t *testing.T) {
+	tests := []struct {
+		name     string
+		segments config.SegmentConfig
+		wantErr  bool
+	}{
+		{"defaults", config.SegmentConfig{}, false},
+		{"valid rules", config.SegmentConfig{Skip: []string{"internal", "[a-z]{2}-[A-Z]{2}"}, StripPrefixes: []string{"/tenants/{tenant}"}}, false},
+		{"invalid pattern", config.SegmentConfig{Skip: []string{"("}}, true},
+		{"empty prefix", config.SegmentConfig{StripPrefixes: []string{"/"}}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewSegmentRules(tt.segments)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("NewSegmentRules() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestSegmentSkipReason(t *testing.T) {
+	rules, err := NewSegmentRules(config.SegmentConfig{Skip: []string{"internal", "[a-z]{2}-[A-Z]{2}"}})
+	if err != nil {
+		t.Fatalf("NewSegmentRules() error = %v", err)
+	}
+
+	tests := []struct {
+		segment string
+		reason  string
+	}{
+		{"v1", SkipReasonVersion},
+		{"v4beta", SkipReasonVersion},
+		{"v2alpha1", SkipReasonVersion},
+		{"V3", SkipReasonVersion},
+		{"v1.2", SkipReasonVersion},
+		{"2024-01-01", SkipReasonVersion},
+		{"api", SkipReasonReserved},
+		{"health", SkipReasonReserved},
+		{"internal", SkipReasonPattern},
+		{"en-US", SkipReasonPattern},
+		{"internals", ""},
+		{"users", ""},
+		{"videos", ""},
+		{"v1beta-keys", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.segment, func(t *testing.T) {
+			if reason, _ := rules.skipReason(tt.segment); reason != tt.reason {
+				t.Errorf("skipReason(%q) = %q, expected %q", tt.segment, reason, tt.reason)
+			}
+		})
+	}
+}
+
+func segmentTestSpec() *parser.OpenAPISpec {
+	op := func() *parser.Operation { return &parser.Operation{} }
+	return &parser.OpenAPISpec{
+		Paths: map[string]parser.PathItem{
+			"/rest/v4beta/tenants/{tenant}/projects":              {Get: op()},
+			"/rest/v4beta/tenants/{tenant}/projects/{id}":         {Get: op()},
+			"/rest/v4beta/tenants/{tenant}/projects/{id}/members": {Get: op()},
+			"/rest/internal/en-US/labels":                         {Get: op()},
+		},
+	}
+}
+
+func TestSegmentRulesExtractResources(t *testing.T) {
+	tests := []struct {
+		name     string
+		segments config.SegmentConfig
+		expected []string
+	}{
+		{
+			name:     "defaults",
+			expected: []string{"en-us", "members", "projects", "rest", "tenants"},
+		},
+		{
+			name: "configured rules",
+			segments: config.SegmentConfig{
+				Skip:          []string{"rest", "internal", "[a-z]{2}-[A-Z]{2}"},
+				StripPrefixes: []string{"/tenants/{tenant}"},
+			},
+			expected: []string{"labels", "members", "projects"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rules, err := NewSegmentRules(tt.segments)
+			if err != nil {
+				t.Fatalf("NewSegmentRules() error = %v", err)
+			}
+			ra := NewResourceAnalyzer()
+			ra.SetSegmentRules(rules)
+
+			var names []string
+			for _, resource := range ra.ExtractResources(segmentTestSpec()) {
+				names = append(names, resource.Name)
+			}
+			sort.Strings(names)
+			if !reflect.DeepEqual(names, tt.expected) {
+				t.Errorf("Expected resources %v, got %v", tt.expected, names)
+			}
+		})
+	}
+}
+
+func TestSegmentRulesHierarchy(t *testing.T) {
+	rules, err := NewSegmentRules(config.SegmentConfig{StripPrefixes: []string{"/rest/{version}/tenants/{tenant}"}})
+	if err != nil {
+		t.Fatalf("NewSegmentRules() error = %v", err)
+	}
+	ra := NewResourceAnalyzer()
+	ra.SetSegmentRules(rules)
+
+	for _, resource := range ra.ExtractResources(segmentTestSpec()) {
+		switch resource.Name {
+		case "projects":
+			if resource.Parent != "" || resource.PathPattern != "/rest/v4beta/tenants/{tenant}/projects/{id}" {
+				t.Errorf("Expected top-level projects with full path pattern, got parent %q pattern %q", resource.Parent, resource.PathPattern)
+			}
+		case "members":
+			if resource.Parent != "projects" {
+				t.Errorf("Expected members under projects, got %q", resource.Parent)
+			}
+		case "tenants":
+			t.Error("Expected stripped tenants prefix not to become a resource")
+		}
+	}
+}
+
+func TestSkippedSegments(t *testing.T) {
+	rules, err := NewSegmentRules(config.SegmentConfig{
+		Skip:          []string{"rest", "internal"},
+		StripPrefixes: []string{"/tenants/{tenant}"},
+	})
+	if err != nil {
+		t.Fatalf("NewSegmentRules() error = %v", err)
+	}
+	ra := NewResourceAnalyzer()
+	ra.SetSegmentRules(rules)
+
+	expected := []models.SkippedSegment{
+		{Segment: "rest", Reason: SkipReasonPattern, Rule: "rest", Paths: 4},
+		{Segment: "/tenants/{tenant}", Reason: SkipReasonPrefix, Rule: "/tenants/{tenant}", Paths: 3},
+		{Segment: "v4beta", Reason: SkipReasonVersion, Paths: 3},
+		{Segment: "internal", Reason: SkipReasonPattern, Rule: "internal", Paths: 1},
+	}
+	if skipped := ra.SkippedSegments(segmentTestSpec()); !reflect.DeepEqual(skipped, expected) {
+		t.Errorf("Expected skipped segments %+v, got %+v", expected, skipped)
+	}
+}
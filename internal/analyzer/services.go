@@ -0,0 +1,126 @@
+package analyzer
+
+import (
+	"cmp"
+	"slices"
+
+	"github.com/orchard9/api-godoc/internal/parser"
+	"github.com/orchard9/api-godoc/pkg/models"
+)
+
+// ServiceSpec is one analyzed specification of a multi-service system
+type ServiceSpec struct {
+	Name     string
+	Spec     *parser.OpenAPISpec
+	Analysis *models.APIAnalysis
+}
+
+// DetectServiceDependencies finds foreign keys and parameters of one service
+// that name a resource another service owns, e.g. a billingAccountId
+// property in invoicing when only billing serves billing-accounts. Names
+// that match a resource of the service itself stay internal.
+func (rd *RelationshipDetector) DetectServiceDependencies(services []ServiceSpec) []models.ServiceDependency {
+	resourceMaps := make([]map[string]*models.Resource, len(services))
+	for i, service := range services {
+		resourceMaps[i] = rd.indexResources(service.Analysis.Resources)
+	}
+
+	var dependencies []models.ServiceDependency
+	for i, service := range services {
+		references := make(map[string][]models.CrossReference)
+		add := func(resource, via, base string, evidence models.Evidence) {
+			if rd.findResource(resourceMaps[i], base) != nil {
+				return
+			}
+			for j, owner := range services {
+				target := rd.findResource(resourceMaps[j], base)
+				if j == i || target == nil {
+					continue
+				}
+				references[owner.Name] = addCrossReference(references[owner.Name], resource, via, target.Name, evidence)
+			}
+		}
+		rd.serviceForeignKeys(resourceMaps[i], service.Spec, add)
+		rd.serviceParameters(resourceMaps[i], service.Spec, add)
+
+		for _, owner := range services {
+			if refs := references[owner.Name]; len(refs) > 0 {
+				slices.SortFunc(refs, func(a, b models.CrossReference) int {
+					return cmp.Or(cmp.Compare(a.Resource, b.Resource), cmp.Compare(a.Via, b.Via), cmp.Compare(a.Target, b.Target))
+				})
+				dependencies = append(dependencies, models.ServiceDependency{From: service.Name, To: owner.Name, References: refs})
+			}
+		}
+	}
+	return dependencies
+}
+
+// serviceForeignKeys reports schema properties named like foreign keys,
+// customerId or productIds, with the resource owning the schema
+func (rd *RelationshipDetector) serviceForeignKeys(resourceMap map[string]*models.Resource, spec *parser.OpenAPISpec, add func(resource, via, base string, evidence models.Evidence)) {
+	if spec.Components == nil {
+		return
+	}
+	for _, schemaName := range sortedKeys(spec.Components.Schemas) {
+		resource := schemaName
+		if owner := rd.findResource(resourceMap, rd.schemaToResourceName(schemaName)); owner != nil {
+			resource = owner.Name
+		}
+		properties := spec.Components.Schemas[schemaName].Properties
+		for _, propertyName := range sortedKeys(properties) {
+			pattern := rd.foreignKeyRegex
+			if properties[propertyName].Type == "array" {
+				pattern = rd.foreignKeysRegex
+			}
+			if matches := pattern.FindStringSubmatch(propertyName); len(matches) > 1 {
+				source := "#/components/schemas/" + schemaName + "/properties/" + propertyName
+				add(resource, propertyName, matches[1], newEvidence(models.EvidenceNaming, source))
+			}
+		}
+	}
+}
+
+// serviceParameters reports path and query parameters named like foreign
+// keys with the resource owning the operation
+func (rd *RelationshipDetector) serviceParameters(resourceMap map[string]*models.Resource, spec *parser.OpenAPISpec, add func(resource, via, base string, evidence models.Evidence)) {
+	owners := operationOwners(resourceMap)
+	for _, path := range sortedPaths(spec.Paths) {
+		pathItem := spec.Paths[path]
+		for _, op := range pathItemOperations(pathItem) {
+			operation := op.method + " " + path
+			resource := path
+			if owner := owners[operation]; owner != nil {
+				resource = owner.Name
+			}
+
+			var names []string
+			for _, match := range rd.pathParamRegex.FindAllStringSubmatch(path, -1) {
+				names = append(names, match[1])
+			}
+			for _, parameter := range slices.Concat(pathItem.Parameters, op.operation.Parameters) {
+				if parameter = resolveParameter(spec, parameter); parameter.In == "query" {
+					names = append(names, parameter.Name)
+				}
+			}
+			for _, name := range names {
+				if matches := rd.foreignKeyRegex.FindStringSubmatch(name); len(matches) > 1 {
+					add(resource, name, matches[1], newEvidence(models.EvidenceParameter, operation))
+				}
+			}
+		}
+	}
+}
+
+// addCrossReference adds a reference, or the evidence to the same reference
+// found earlier
+func addCrossReference(references []models.CrossReference, resource, via, target string, evidence models.Evidence) []models.CrossReference {
+	for i, ref := range references {
+		if ref.Resource == resource && ref.Via == via && ref.Target == target {
+			if !slices.Contains(ref.Evidence, evidence) {
+				references[i].Evidence = append(references[i].Evidence, evidence)
+			}
+			return references
+		}
+	}
+	return append(references, models.CrossReference{Resource: resource, Via: via, Target: target, Evidence: []models.Evidence{evidence}})
+}
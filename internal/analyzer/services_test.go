@@ -0,0 +1,80 @@
+package analyzer
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/orchard9/api-godoc/internal/parser"
+	"github.com/orchard9/api-godoc/pkg/models"
+)
+
+func TestServiceDependencies(t *testing.T) {
+	specs := map[string]*parser.OpenAPISpec{
+		"billing": {
+			Paths: map[string]parser.PathItem{
+				"/billing-accounts":                    {Get: &parser.Operation{}},
+				"/billing-accounts/{billingAccountId}": {Get: &parser.Operation{}},
+				"/payments":                            {Get: &parser.Operation{}},
+			},
+			Components: &parser.Components{
+				Schemas: map[string]parser.Schema{
+					"Payment": {
+						Type: "object",
+						Properties: map[string]parser.Schema{
+							"billingAccountId": {Type: "string"},
+							"invoiceId":        {Type: "string"},
+						},
+					},
+				},
+			},
+		},
+		"invoicing": {
+			Paths: map[string]parser.PathItem{
+				"/invoices": {Get: &parser.Operation{
+					Parameters: []parser.Parameter{{Name: "billingAccountId", In: "query"}},
+				}},
+				"/invoices/{invoiceId}": {Get: &parser.Operation{}},
+			},
+			Components: &parser.Components{
+				Schemas: map[string]parser.Schema{
+					"Invoice": {
+						Type: "object",
+						Properties: map[string]parser.Schema{
+							"billingAccountId": {Type: "string"},
+							"customerId":       {Type: "string"},
+							"paymentIds":       {Type: "array", Items: &parser.Schema{Type: "string"}},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	var services []ServiceSpec
+	for _, name := range []string{"billing", "invoicing"} {
+		resources := NewResourceAnalyzer().ExtractResources(specs[name])
+		services = append(services, ServiceSpec{Name: name, Spec: specs[name], Analysis: &models.APIAnalysis{Resources: resources}})
+	}
+
+	expected := []models.ServiceDependency{
+		{From: "billing", To: "invoicing", References: []models.CrossReference{
+			{Resource: "payments", Via: "invoiceId", Target: "invoices", Evidence: []models.Evidence{
+				newEvidence(models.EvidenceNaming, "#/components/schemas/Payment/properties/invoiceId"),
+			}},
+		}},
+		{From: "invoicing", To: "billing", References: []models.CrossReference{
+			{Resource: "invoices", Via: "billingAccountId", Target: "billing-accounts", Evidence: []models.Evidence{
+				newEvidence(models.EvidenceNaming, "#/components/schemas/Invoice/properties/billingAccountId"),
+				newEvidence(models.EvidenceParameter, "GET /invoices"),
+			}},
+			{Resource: "invoices", Via: "paymentIds", Target: "payments", Evidence: []models.Evidence{
+				newEvidence(models.EvidenceNaming, "#/components/schemas/Invoice/properties/paymentIds"),
+			}},
+		}},
+	}
+
+	dependencies := NewRelationshipDetector().DetectServiceDependencies(services)
+	if !reflect.DeepEqual(dependencies, expected) {
+		t.Errorf("Expected dependencies\n%+v\ngot\n%+v", expected, dependencies)
+	}
+}
@@ -0,0 +1,72 @@
+// Package config loads project-level analysis settings from a YAML file
+package config
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Config holds analysis settings that override built-in heuristics
+type Config struct {
+	Naming     NamingConfig   `yaml:"naming"`
+	Segments   SegmentConfig  `yaml:"segments"`
+	Categories []CategoryRule `yaml:"categories"`
+	Contexts   []ContextRule  `yaml:"contexts"`
+}
+
+// NamingConfig customizes how resource names are normalized and displayed
+type NamingConfig struct {
+	Irregular    map[string]string `yaml:"irregular"`    // singular -> plural, e.g. person: people
+	Uncountable  []string          `yaml:"uncountable"`  // words with no plural form, e.g. metadata
+	Aliases      map[string]string `yaml:"aliases"`      // alias -> canonical resource name
+	DisplayNames map[string]string `yaml:"displayNames"` // canonical resource name -> display name
+}
+
+// SegmentConfig customizes which path segments are treated as resources
+type SegmentConfig struct {
+	Skip          []string `yaml:"skip"`          // regular expressions matching whole segments that are never resources
+	StripPrefixes []string `yaml:"stripPrefixes"` // path prefixes ignored during analysis, e.g. /tenants/{tenant}
+}
+
+// CategoryRule assigns matching resources to a category ahead of the built-in heuristics
+type CategoryRule struct {
+	Category  string   `yaml:"category"`
+	Resources []string `yaml:"resources"` // regular expressions matching whole resource names
+	Paths     []string `yaml:"paths"`     // path prefixes, e.g. /admin
+	Tags      []string `yaml:"tags"`      // operation tags
+}
+
+// ContextRule pins matching resources to a bounded context instead of
+// leaving them to clustering
+type ContextRule struct {
+	Context   string   `yaml:"context"`
+	Resources []string `yaml:"resources"` // regular expressions matching whole resource names
+	Paths     []string `yaml:"paths"`     // path prefixes, e.g. /billing
+	Tags      []string `yaml:"tags"`      // operation tags
+}
+
+// Load reads a configuration file
+func Load(path string) (*Config, error) {
+	data, err := os.ReadFile(path) // #nosec G304 - User-provided config file path is intended
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config file: %w", err)
+	}
+	return Parse(data)
+}
+
+// Parse decodes configuration from YAML (or JSON) data
+func Parse(data []byte) (*Config, error) {
+	var cfg Config
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	// An empty file is a valid, empty configuration
+	if err := decoder.Decode(&cfg); err != nil && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("failed to parse config: %w", err)
+	}
+	return &cfg, nil
+}
@@ -0,0 +1,52 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		wantErr bool
+	}{
+		{"empty file", "", false},
+		{"naming overrides", "naming:\n  irregular:\n    person: people\n  aliases:\n    keys: api-keys\n", false},
+		{"json", `{"naming": {"uncountable": ["metadata"]}}`, false},
+		{"segment rules", "segments:\n  skip:\n    - internal\n  stripPrefixes:\n    - /tenants/{tenant}\n", false},
+		{"context rules", "contexts:\n  - context: identity\n    resources: [users, groups]\n    tags: [IdentityService]\n", false},
+		{"unknown field", "nmaing:\n  aliases: {}\n", true},
+		{"invalid yaml", "naming: [", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Parse([]byte(tt.data))
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Parse() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "api-godoc.yaml")
+	data := "naming:\n  displayNames:\n    api-keys: API Keys\n"
+	if err := os.WriteFile(path, []byte(data), 0600); err != nil {
+		t.Fatalf("Failed to write config: %v", err)
+	}
+
+	cfg, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if cfg.Naming.DisplayNames["api-keys"] != "API Keys" {
+		t.Errorf("Expected display name override, got %v", cfg.Naming.DisplayNames)
+	}
+
+	if _, err := Load(filepath.Join(t.TempDir(), "missing.yaml")); err == nil {
+		t.Error("Expected error for missing file")
+	}
+}
@@ -16,11 +16,12 @@ func (c *converter) convertComponents(swagger map[string]interface{}) map[string
 	// Convert parameters
 	if parameters, ok := swagger["parameters"].(map[string]interface{}); ok {
 		convertedParams := make(map[string]interface{})
+		requestBodies := make(map[string]interface{})
 		for name, param := range parameters {
 			if p, ok := param.(map[string]interface{}); ok {
 				if p["in"] == "body" {
 					// Body parameters become request bodies in OpenAPI 3.x
-					// Store them separately for now
+					requestBodies[name] = c.convertBodyParameter(p)
 					continue
 				}
 				convertedParams[name] = c.convertParameter(p)
@@ -29,6 +30,9 @@ func (c *converter) convertComponents(swagger map[string]interface{}) map[string
 		if len(convertedParams) > 0 {
 			components["parameters"] = convertedParams
 		}
+		if len(requestBodies) > 0 {
+			components["requestBodies"] = requestBodies
+		}
 	}
 
 	// Convert responses
@@ -67,6 +71,13 @@ func (c *converter) convertSchema(schema interface{}) interface{} {
 		// Handle $ref
 		if ref, ok := s["$ref"].(string); ok {
 			converted["$ref"] = c.convertRef(ref)
+			// Siblings of $ref are ignored in 3.0, so a nullable ref needs an allOf wrapper
+			if nullable, ok := s[extNullable].(bool); ok && nullable {
+				return map[string]interface{}{
+					"allOf":    []interface{}{converted},
+					"nullable": true,
+				}
+			}
 			return converted
 		}
 
@@ -117,6 +128,9 @@ func (c *converter) convertSchema(schema interface{}) interface{} {
 			}
 		}
 
+		// Map vendor extensions (x-nullable, x-example, x-ms-enum) to native keywords
+		c.convertSchemaExtensions(s, converted)
+
 		return converted
 	default:
 		return s
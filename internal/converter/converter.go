@@ -10,6 +10,9 @@ import (
 type Converter interface {
 	// Convert transforms a Swagger 2.0 spec to OpenAPI 3.x format
 	Convert(swagger []byte) ([]byte, error)
+
+	// Warnings returns non-fatal issues found during the last conversion
+	Warnings() []string
 }
 
 // New creates a new converter instance
@@ -17,7 +20,17 @@ func New() Converter {
 	return &converter{}
 }
 
-type converter struct{}
+type converter struct {
+	parameters     map[string]interface{} // global Swagger 2.0 parameters, used to resolve $refs
+	requestBodies  map[string]interface{} // OpenAPI 3.x shared request bodies, used when downgrading
+	bodyParameters map[string]string      // shared request body name -> Swagger 2.0 parameter name
+	warnings       []string
+}
+
+// Warnings implements the Converter interface
+func (c *converter) Warnings() []string {
+	return c.warnings
+}
 
 // Convert implements the Converter interface
 func (c *converter) Convert(swagger []byte) ([]byte, error) {
@@ -27,6 +40,23 @@ func (c *converter) Convert(swagger []byte) ([]byte, error) {
 		return nil, fmt.Errorf("failed to parse swagger spec: %w", err)
 	}
 
+	c.warnings = nil
+	openAPISpec, err := c.convertSwagger(swaggerSpec)
+	if err != nil {
+		return nil, err
+	}
+
+	// Marshal to JSON
+	result, err := json.MarshalIndent(openAPISpec, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal openapi spec: %w", err)
+	}
+
+	return result, nil
+}
+
+// convertSwagger converts a decoded Swagger 2.0 document to OpenAPI 3.0
+func (c *converter) convertSwagger(swaggerSpec map[string]interface{}) (map[string]interface{}, error) {
 	// Validate Swagger version
 	version, ok := swaggerSpec["swagger"].(string)
 	if !ok || version != "2.0" {
@@ -38,6 +68,9 @@ func (c *converter) Convert(swagger []byte) ([]byte, error) {
 		return nil, fmt.Errorf("missing required field: info")
 	}
 
+	// Remember shared parameters for $ref resolution
+	c.parameters, _ = swaggerSpec["parameters"].(map[string]interface{})
+
 	// Create OpenAPI 3.x spec
 	openAPISpec := map[string]interface{}{
 		"openapi": "3.0.3",
@@ -55,12 +88,19 @@ func (c *converter) Convert(swagger []byte) ([]byte, error) {
 	}
 
 	// Convert paths
-	if paths, ok := swaggerSpec["paths"].(map[string]interface{}); ok {
-		openAPISpec["paths"] = c.convertPaths(paths)
-	} else {
-		openAPISpec["paths"] = map[string]interface{}{}
+	paths := map[string]interface{}{}
+	if swaggerPaths, ok := swaggerSpec["paths"].(map[string]interface{}); ok {
+		paths = c.convertPaths(swaggerPaths)
 	}
 
+	// Fold x-ms-paths into paths where OpenAPI 3.x can express them
+	if msPaths, ok := swaggerSpec[extMSPaths].(map[string]interface{}); ok {
+		if remaining := c.mergeMSPaths(paths, c.convertPaths(msPaths)); len(remaining) > 0 {
+			openAPISpec[extMSPaths] = remaining
+		}
+	}
+	openAPISpec["paths"] = paths
+
 	// Convert components (definitions, securityDefinitions, etc.)
 	components := c.convertComponents(swaggerSpec)
 	if len(components) > 0 {
@@ -82,11 +122,5 @@ func (c *converter) Convert(swagger []byte) ([]byte, error) {
 		openAPISpec["externalDocs"] = docs
 	}
 
-	// Marshal to JSON
-	result, err := json.MarshalIndent(openAPISpec, "", "  ")
-	if err != nil {
-		return nil, fmt.Errorf("failed to marshal openapi spec: %w", err)
-	}
-
-	return result, nil
+	return openAPISpec, nil
 }
@@ -2,6 +2,7 @@ package converter
 
 import (
 	"encoding/json"
+	"strings"
 	"testing"
 )
 
@@ -208,3 +209,181 @@ func TestConvertRealWorldSpecs(t *testing.T) {
 		})
 	}
 }
+
+func TestConvertSharedBodyParameters(t *testing.T) {
+	swagger := `{
+		"swagger": "2.0",
+		"info": {"title": "Test", "version": "1.0"},
+		"parameters": {
+			"UserBody": {
+				"name": "body",
+				"in": "body",
+				"required": true,
+				"schema": {"$ref": "#/definitions/User"}
+			},
+			"Limit": {"name": "limit", "in": "query", "type": "integer"}
+		},
+		"definitions": {
+			"User": {"type": "object"}
+		},
+		"paths": {
+			"/users": {
+				"post": {
+					"parameters": [
+						{"$ref": "#/parameters/UserBody"},
+						{"$ref": "#/parameters/Limit"},
+						{"$ref": "#/parameters/Missing"}
+					],
+					"responses": {"201": {"description": "Created"}}
+				}
+			}
+		}
+	}`
+
+	c := New()
+	got, err := c.Convert([]byte(swagger))
+	if err != nil {
+		t.Fatalf("Convert() error = %v", err)
+	}
+
+	var result map[string]interface{}
+	if err := json.Unmarshal(got, &result); err != nil {
+		t.Fatalf("Failed to unmarshal result: %v", err)
+	}
+
+	components := result["components"].(map[string]interface{})
+	requestBodies, ok := components["requestBodies"].(map[string]interface{})
+	if !ok || requestBodies["UserBody"] == nil {
+		t.Fatalf("Expected UserBody in components.requestBodies, got %v", components["requestBodies"])
+	}
+	if params := components["parameters"].(map[string]interface{}); params["UserBody"] != nil {
+		t.Error("Body parameter should not be converted into components.parameters")
+	}
+
+	post := result["paths"].(map[string]interface{})["/users"].(map[string]interface{})["post"].(map[string]interface{})
+	requestBody := post["requestBody"].(map[string]interface{})
+	if ref := requestBody["$ref"]; ref != "#/components/requestBodies/UserBody" {
+		t.Errorf("Expected requestBody ref to be rewritten, got %v", ref)
+	}
+
+	// The unresolved reference is dropped rather than left dangling
+	params := post["parameters"].([]interface{})
+	if len(params) != 1 {
+		t.Fatalf("Expected 1 parameter, got %v", params)
+	}
+	if ref := params[0].(map[string]interface{})["$ref"]; ref != "#/components/parameters/Limit" {
+		t.Errorf("Expected parameter ref to be rewritten, got %v", ref)
+	}
+
+	warnings := c.Warnings()
+	if len(warnings) != 1 || !strings.Contains(warnings[0], "#/parameters/Missing") {
+		t.Errorf("Expected one warning for the unresolved reference, got %v", warnings)
+	}
+}
+
+func TestConvertVendorExtensions(t *testing.T) {
+	swagger := `{
+		"swagger": "2.0",
+		"info": {"title": "Test", "version": "1.0"},
+		"definitions": {
+			"Vault": {
+				"type": "object",
+				"properties": {
+					"owner": {"$ref": "#/definitions/User", "x-nullable": true},
+					"nickname": {"type": "string", "x-nullable": true, "x-example": "vault-1"},
+					"sku": {
+						"type": "string",
+						"x-ms-enum": {"name": "Sku", "values": [{"value": "standard"}, {"value": "premium"}]}
+					}
+				}
+			},
+			"User": {"type": "object"}
+		},
+		"paths": {
+			"/vaults": {
+				"parameters": [{"name": "api-version", "in": "query", "type": "string"}],
+				"get": {
+					"x-ms-pageable": {"nextLinkName": "nextLink"},
+					"parameters": [{"name": "top", "in": "query", "type": "integer", "x-example": 10}],
+					"responses": {"200": {"description": "OK"}}
+				}
+			}
+		},
+		"x-ms-paths": {
+			"/vaults?op=purge": {
+				"parameters": [{"name": "op", "in": "query", "type": "string", "enum": ["purge"]}],
+				"post": {
+					"x-ms-long-running-operation": true,
+					"responses": {"202": {"description": "Accepted"}}
+				}
+			},
+			"/vaults?op=list": {
+				"get": {"responses": {"200": {"description": "OK"}}}
+			}
+		}
+	}`
+
+	c := New()
+	got, err := c.Convert([]byte(swagger))
+	if err != nil {
+		t.Fatalf("Convert() error = %v", err)
+	}
+
+	var result map[string]interface{}
+	if err := json.Unmarshal(got, &result); err != nil {
+		t.Fatalf("Failed to unmarshal result: %v", err)
+	}
+
+	props := result["components"].(map[string]interface{})["schemas"].(map[string]interface{})["Vault"].(map[string]interface{})["properties"].(map[string]interface{})
+
+	owner := props["owner"].(map[string]interface{})
+	if owner["nullable"] != true || owner["allOf"] == nil {
+		t.Errorf("Expected nullable $ref to be wrapped in allOf, got %v", owner)
+	}
+
+	nickname := props["nickname"].(map[string]interface{})
+	if nickname["nullable"] != true || nickname["example"] != "vault-1" {
+		t.Errorf("Expected x-nullable and x-example to map to native keywords, got %v", nickname)
+	}
+
+	sku := props["sku"].(map[string]interface{})
+	if enum, ok := sku["enum"].([]interface{}); !ok || len(enum) != 2 {
+		t.Errorf("Expected x-ms-enum values to become enum, got %v", sku["enum"])
+	}
+
+	paths := result["paths"].(map[string]interface{})
+	vaults := paths["/vaults"].(map[string]interface{})
+	get := vaults["get"].(map[string]interface{})
+	if get["x-ms-pageable"] == nil {
+		t.Error("Expected x-ms-pageable to be preserved on the operation")
+	}
+	param := get["parameters"].([]interface{})[0].(map[string]interface{})
+	if param["example"] != float64(10) {
+		t.Errorf("Expected parameter x-example to become example, got %v", param["example"])
+	}
+
+	post, ok := vaults["post"].(map[string]interface{})
+	if !ok || post["x-ms-long-running-operation"] != true {
+		t.Errorf("Expected x-ms-paths POST to merge into /vaults with its extension, got %v", vaults["post"])
+	}
+
+	// Path-level parameters of the entry move onto its operations only
+	if params, _ := post["parameters"].([]interface{}); len(params) != 1 || params[0].(map[string]interface{})["name"] != "op" {
+		t.Errorf("Expected the entry's op parameter on the merged POST, got %v", post["parameters"])
+	}
+	if params := vaults["parameters"].([]interface{}); len(params) != 1 || params[0].(map[string]interface{})["name"] != "api-version" {
+		t.Errorf("Expected /vaults to keep only its own path-level parameters, got %v", params)
+	}
+	if len(get["parameters"].([]interface{})) != 1 {
+		t.Errorf("Expected GET /vaults to keep its own parameters, got %v", get["parameters"])
+	}
+
+	remaining, ok := result["x-ms-paths"].(map[string]interface{})
+	if !ok || remaining["/vaults?op=list"] == nil {
+		t.Errorf("Expected conflicting x-ms-paths entry to be kept, got %v", result["x-ms-paths"])
+	}
+	warnings := c.Warnings()
+	if len(warnings) != 2 || !strings.Contains(warnings[0], "conflicts") || !strings.Contains(warnings[1], "?op=purge") {
+		t.Errorf("Expected warnings for the conflicting entry and the dropped query string, got %v", warnings)
+	}
+}
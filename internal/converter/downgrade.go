@@ -0,0 +1,575 @@
+package converter
+
+import (
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+)
+
+// downgradeTo20 converts an OpenAPI 3.0 document to Swagger 2.0 for gateways
+// that only accept the older format. Constructs with no 2.0 equivalent are
+// dropped and reported as warnings.
+func (c *converter) downgradeTo20(doc map[string]interface{}) map[string]interface{} {
+	swagger := map[string]interface{}{
+		"swagger": "2.0",
+	}
+
+	for _, key := range []string{"info", "security", "tags", "externalDocs"} {
+		if value, ok := doc[key]; ok {
+			swagger[key] = value
+		}
+	}
+
+	c.downgradeServers(asSlice(doc["servers"]), swagger)
+
+	components := asMap(doc["components"])
+	c.downgradeComponents(components, swagger)
+
+	paths := make(map[string]interface{})
+	for path, pathItem := range asMap(doc["paths"]) {
+		paths[path] = c.downgradePathItem(path, asMap(pathItem))
+	}
+	swagger["paths"] = paths
+
+	// Rewrite every 3.x reference to its 2.0 location
+	rewriteRefs(swagger)
+
+	return swagger
+}
+
+// downgradeServers derives host, basePath and schemes from the servers list
+func (c *converter) downgradeServers(servers []interface{}, swagger map[string]interface{}) {
+	if len(servers) == 0 {
+		return
+	}
+
+	first, err := url.Parse(stringValue(asMap(servers[0])["url"]))
+	if err != nil {
+		c.warnings = append(c.warnings, fmt.Sprintf("invalid server URL: %v", err))
+		return
+	}
+
+	if first.Host != "" {
+		swagger["host"] = first.Host
+	}
+	if first.Path != "" && first.Path != "/" {
+		swagger["basePath"] = first.Path
+	}
+
+	// Collect the schemes of every server that shares the first server's host
+	var schemes []interface{}
+	seen := make(map[string]bool)
+	for _, server := range servers {
+		u, err := url.Parse(stringValue(asMap(server)["url"]))
+		if err != nil || u.Host != first.Host || u.Scheme == "" || seen[u.Scheme] {
+			continue
+		}
+		seen[u.Scheme] = true
+		schemes = append(schemes, u.Scheme)
+	}
+	if len(schemes) > 0 {
+		swagger["schemes"] = schemes
+	}
+
+	// Servers with another host or base path cannot be expressed in 2.0
+	dropped := 0
+	for _, server := range servers[1:] {
+		u, err := url.Parse(stringValue(asMap(server)["url"]))
+		if err != nil || u.Host != first.Host || strings.TrimSuffix(u.Path, "/") != strings.TrimSuffix(first.Path, "/") {
+			dropped++
+		}
+	}
+	if dropped > 0 {
+		c.warnings = append(c.warnings, fmt.Sprintf("%d servers dropped: Swagger 2.0 supports a single host and basePath", dropped))
+	}
+}
+
+// downgradeComponents moves components into their Swagger 2.0 locations
+func (c *converter) downgradeComponents(components map[string]interface{}, swagger map[string]interface{}) {
+	if schemas := asMap(components["schemas"]); len(schemas) > 0 {
+		definitions := make(map[string]interface{})
+		for name, schema := range schemas {
+			definitions[name] = c.downgradeSchema(schema)
+		}
+		swagger["definitions"] = definitions
+	}
+
+	parameters := make(map[string]interface{})
+	for name, param := range asMap(components["parameters"]) {
+		parameters[name] = c.downgradeParameter(asMap(param))
+	}
+	// Shared request bodies become shared body parameters, renamed when a
+	// parameter has the same name. Form bodies have no shared 2.0 equivalent
+	// and are expanded into formData parameters at each operation.
+	c.requestBodies = asMap(components["requestBodies"])
+	c.bodyParameters = make(map[string]string)
+	for _, name := range sortedKeys(c.requestBodies) {
+		bodyParam, _ := c.downgradeRequestBody(asMap(c.requestBodies[name]))
+		if _, form := bodyParam.([]interface{}); form {
+			continue
+		}
+		paramName := name
+		if _, exists := parameters[paramName]; exists {
+			paramName = name + "Body"
+			for n := 2; parameters[paramName] != nil; n++ {
+				paramName = fmt.Sprintf("%sBody%d", name, n)
+			}
+			c.warnings = append(c.warnings, fmt.Sprintf("request body %s was renamed to parameter %s because a parameter has the same name", name, paramName))
+		}
+		parameters[paramName] = bodyParam
+		c.bodyParameters[name] = paramName
+	}
+	if len(parameters) > 0 {
+		swagger["parameters"] = parameters
+	}
+
+	if responses := asMap(components["responses"]); len(responses) > 0 {
+		converted := make(map[string]interface{})
+		for name, response := range responses {
+			converted[name], _ = c.downgradeResponse(asMap(response))
+		}
+		swagger["responses"] = converted
+	}
+
+	if schemes := asMap(components["securitySchemes"]); len(schemes) > 0 {
+		definitions := make(map[string]interface{})
+		for name, scheme := range schemes {
+			if def := c.downgradeSecurityScheme(name, asMap(scheme)); def != nil {
+				definitions[name] = def
+			}
+		}
+		swagger["securityDefinitions"] = definitions
+	}
+
+	for _, key := range []string{"links", "callbacks"} {
+		if len(asMap(components[key])) > 0 {
+			c.warnings = append(c.warnings, fmt.Sprintf("components.%s are not supported in Swagger 2.0 and were dropped", key))
+		}
+	}
+}
+
+// downgradePathItem converts the operations of a single path
+func (c *converter) downgradePathItem(path string, pathItem map[string]interface{}) map[string]interface{} {
+	converted := make(map[string]interface{})
+
+	if params := asSlice(pathItem["parameters"]); len(params) > 0 {
+		var downgraded []interface{}
+		for _, param := range params {
+			downgraded = append(downgraded, c.downgradeParameter(asMap(param)))
+		}
+		converted["parameters"] = downgraded
+	}
+
+	for _, method := range httpMethods {
+		op, ok := pathItem[method].(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if method == "trace" {
+			c.warnings = append(c.warnings, fmt.Sprintf("TRACE %s is not supported in Swagger 2.0 and was dropped", path))
+			continue
+		}
+		converted[method] = c.downgradeOperation(method, path, op)
+	}
+
+	return converted
+}
+
+// downgradeOperation converts a single operation from OpenAPI 3.x to Swagger 2.0
+func (c *converter) downgradeOperation(method, path string, operation map[string]interface{}) map[string]interface{} {
+	converted := make(map[string]interface{})
+
+	simpleFields := []string{"summary", "description", "operationId", "tags", "deprecated", "security", "externalDocs"}
+	for _, field := range simpleFields {
+		if value, ok := operation[field]; ok {
+			converted[field] = value
+		}
+	}
+	copyExtensions(operation, converted)
+
+	var parameters []interface{}
+	for _, param := range asSlice(operation["parameters"]) {
+		parameters = append(parameters, c.downgradeParameter(asMap(param)))
+	}
+
+	if body, ok := operation["requestBody"].(map[string]interface{}); ok {
+		bodyParams, consumes := c.downgradeRequestBody(body)
+		if formParams, ok := bodyParams.([]interface{}); ok {
+			parameters = append(parameters, formParams...)
+		} else {
+			parameters = append(parameters, bodyParams)
+		}
+		if len(consumes) > 0 {
+			converted["consumes"] = consumes
+		}
+	}
+	if len(parameters) > 0 {
+		converted["parameters"] = parameters
+	}
+
+	responses := make(map[string]interface{})
+	produces := make(map[string]bool)
+	for status, response := range asMap(operation["responses"]) {
+		var contentTypes []string
+		responses[status], contentTypes = c.downgradeResponse(asMap(response))
+		for _, ct := range contentTypes {
+			produces[ct] = true
+		}
+	}
+	converted["responses"] = responses
+	if len(produces) > 0 {
+		converted["produces"] = sortedKeys(produces)
+	}
+
+	if _, ok := operation["callbacks"]; ok {
+		c.warnings = append(c.warnings, fmt.Sprintf("callbacks on %s %s were dropped", strings.ToUpper(method), path))
+	}
+
+	return converted
+}
+
+// downgradeParameter flattens a 3.x parameter schema into 2.0 parameter fields
+func (c *converter) downgradeParameter(param map[string]interface{}) map[string]interface{} {
+	if ref, ok := param["$ref"].(string); ok {
+		return map[string]interface{}{"$ref": ref}
+	}
+
+	converted := make(map[string]interface{})
+	for _, field := range []string{"name", "in", "description", "required", "allowEmptyValue"} {
+		if value, ok := param[field]; ok {
+			converted[field] = value
+		}
+	}
+	copyExtensions(param, converted)
+
+	schema := asMap(param["schema"])
+	if schema == nil {
+		for _, mediaType := range asMap(param["content"]) {
+			schema = asMap(asMap(mediaType)["schema"])
+			break
+		}
+	}
+	flattenSchema(c.downgradeSchema(schema), converted)
+
+	if param["in"] == "cookie" {
+		c.warnings = append(c.warnings, fmt.Sprintf("cookie parameter %v converted to a header parameter", param["name"]))
+		converted["in"] = "header"
+	}
+
+	return converted
+}
+
+// downgradeRequestBody converts a request body into a body parameter, or into
+// formData parameters for form content. It returns the parameter(s) and the
+// content types for the operation's consumes list.
+func (c *converter) downgradeRequestBody(body map[string]interface{}) (interface{}, []interface{}) {
+	if ref, ok := body["$ref"].(string); ok {
+		name, shared := strings.CutPrefix(ref, "#/components/requestBodies/")
+		target := asMap(c.requestBodies[name])
+		switch {
+		case !shared || target == nil:
+			return map[string]interface{}{"$ref": ref}, nil
+		case c.bodyParameters[name] != "":
+			return map[string]interface{}{"$ref": "#/parameters/" + c.bodyParameters[name]}, consumesOf(target)
+		case target["$ref"] == nil:
+			// Shared form bodies are expanded where they are used
+			return c.downgradeRequestBody(target)
+		}
+		return map[string]interface{}{"$ref": ref}, nil
+	}
+
+	content := asMap(body["content"])
+	contentTypes := sortedKeys(content)
+	consumes := consumesOf(body)
+
+	// Form content becomes one formData parameter per property
+	for _, ct := range contentTypes {
+		if ct != "application/x-www-form-urlencoded" && ct != "multipart/form-data" {
+			continue
+		}
+		schema := asMap(asMap(content[ct])["schema"])
+		required := make(map[string]bool)
+		for _, name := range asSlice(schema["required"]) {
+			required[stringValue(name)] = true
+		}
+
+		var formParams []interface{}
+		for _, name := range sortedKeys(asMap(schema["properties"])) {
+			param := map[string]interface{}{
+				"name":     name,
+				"in":       "formData",
+				"required": required[name],
+			}
+			prop := c.downgradeSchema(asMap(schema["properties"])[name])
+			if asMap(prop)["format"] == "binary" {
+				param["type"] = "file"
+			} else {
+				flattenSchema(prop, param)
+			}
+			formParams = append(formParams, param)
+		}
+		return formParams, consumes
+	}
+
+	param := map[string]interface{}{
+		"name": "body",
+		"in":   "body",
+	}
+	if desc, ok := body["description"]; ok {
+		param["description"] = desc
+	}
+	if req, ok := body["required"]; ok {
+		param["required"] = req
+	}
+	if len(contentTypes) > 0 {
+		param["schema"] = c.downgradeSchema(asMap(content[contentTypes[0]])["schema"])
+	}
+
+	return param, consumes
+}
+
+// downgradeResponse converts a response, returning it and its content types
+func (c *converter) downgradeResponse(response map[string]interface{}) (map[string]interface{}, []string) {
+	if ref, ok := response["$ref"].(string); ok {
+		return map[string]interface{}{"$ref": ref}, nil
+	}
+
+	converted := map[string]interface{}{
+		"description": response["description"],
+	}
+	if converted["description"] == nil {
+		converted["description"] = "Response"
+	}
+
+	content := asMap(response["content"])
+	contentTypes := sortedKeys(content)
+	if len(contentTypes) > 0 {
+		mediaType := asMap(content[contentTypes[0]])
+		if schema, ok := mediaType["schema"]; ok {
+			converted["schema"] = c.downgradeSchema(schema)
+		}
+		examples := make(map[string]interface{})
+		for _, ct := range contentTypes {
+			if example, ok := asMap(content[ct])["example"]; ok {
+				examples[ct] = example
+			}
+		}
+		if len(examples) > 0 {
+			converted["examples"] = examples
+		}
+	}
+
+	if headers := asMap(response["headers"]); len(headers) > 0 {
+		convertedHeaders := make(map[string]interface{})
+		for name, header := range headers {
+			h := asMap(header)
+			convertedHeader := make(map[string]interface{})
+			if desc, ok := h["description"]; ok {
+				convertedHeader["description"] = desc
+			}
+			flattenSchema(c.downgradeSchema(h["schema"]), convertedHeader)
+			convertedHeaders[name] = convertedHeader
+		}
+		converted["headers"] = convertedHeaders
+	}
+
+	if len(asMap(response["links"])) > 0 {
+		c.warnings = append(c.warnings, "response links are not supported in Swagger 2.0 and were dropped")
+	}
+
+	return converted, contentTypes
+}
+
+// downgradeSchema converts 3.0-only schema keywords to Swagger 2.0 equivalents
+func (c *converter) downgradeSchema(schema interface{}) interface{} {
+	s, ok := schema.(map[string]interface{})
+	if !ok {
+		return schema
+	}
+
+	converted := make(map[string]interface{})
+	for key, value := range s {
+		converted[key] = value
+	}
+
+	if nullable, ok := converted["nullable"]; ok {
+		delete(converted, "nullable")
+		converted["x-nullable"] = nullable
+	}
+	delete(converted, "writeOnly")
+
+	// oneOf/anyOf have no 2.0 equivalent; keep the first alternative
+	for _, key := range []string{"oneOf", "anyOf"} {
+		if alternatives := asSlice(converted[key]); len(alternatives) > 0 {
+			delete(converted, key)
+			c.warnings = append(c.warnings, fmt.Sprintf("%s is not supported in Swagger 2.0; kept the first alternative", key))
+			for k, v := range asMap(c.downgradeSchema(alternatives[0])) {
+				if _, exists := converted[k]; !exists {
+					converted[k] = v
+				}
+			}
+		}
+	}
+
+	if props := asMap(converted["properties"]); props != nil {
+		convertedProps := make(map[string]interface{})
+		for name, prop := range props {
+			convertedProps[name] = c.downgradeSchema(prop)
+		}
+		converted["properties"] = convertedProps
+	}
+	for _, key := range []string{"items", "additionalProperties", "not"} {
+		if sub, ok := converted[key].(map[string]interface{}); ok {
+			converted[key] = c.downgradeSchema(sub)
+		}
+	}
+	if allOf := asSlice(converted["allOf"]); allOf != nil {
+		var convertedAllOf []interface{}
+		for _, sub := range allOf {
+			convertedAllOf = append(convertedAllOf, c.downgradeSchema(sub))
+		}
+		converted["allOf"] = convertedAllOf
+	}
+
+	return converted
+}
+
+// downgradeSecurityScheme reverses convertSecurityScheme
+func (c *converter) downgradeSecurityScheme(name string, scheme map[string]interface{}) map[string]interface{} {
+	converted := make(map[string]interface{})
+	if desc, ok := scheme["description"]; ok {
+		converted["description"] = desc
+	}
+
+	switch scheme["type"] {
+	case "http":
+		if scheme["scheme"] != "basic" {
+			// Bearer tokens are the usual 2.0 workaround: an Authorization header API key
+			converted["type"] = "apiKey"
+			converted["name"] = "Authorization"
+			converted["in"] = "header"
+			return converted
+		}
+		converted["type"] = "basic"
+
+	case "apiKey":
+		converted["type"] = "apiKey"
+		converted["name"] = scheme["name"]
+		converted["in"] = scheme["in"]
+
+	case "oauth2":
+		converted["type"] = "oauth2"
+		flows := asMap(scheme["flows"])
+		flowNames := map[string]string{
+			"implicit":          "implicit",
+			"password":          "password",
+			"clientCredentials": "application",
+			"authorizationCode": "accessCode",
+		}
+		for _, flowName := range []string{"authorizationCode", "implicit", "password", "clientCredentials"} {
+			flow, ok := flows[flowName].(map[string]interface{})
+			if !ok {
+				continue
+			}
+			converted["flow"] = flowNames[flowName]
+			for _, key := range []string{"authorizationUrl", "tokenUrl", "scopes"} {
+				if value, ok := flow[key]; ok {
+					converted[key] = value
+				}
+			}
+			break
+		}
+
+	default:
+		c.warnings = append(c.warnings, fmt.Sprintf("security scheme %s of type %v is not supported in Swagger 2.0 and was dropped", name, scheme["type"]))
+		return nil
+	}
+
+	return converted
+}
+
+// flattenSchema copies parameter-compatible schema fields onto a 2.0 parameter or header
+func flattenSchema(schema interface{}, target map[string]interface{}) {
+	s := asMap(schema)
+	for _, key := range []string{
+		"type", "format", "items", "default", "maximum", "exclusiveMaximum",
+		"minimum", "exclusiveMinimum", "maxLength", "minLength", "pattern",
+		"maxItems", "minItems", "uniqueItems", "enum", "multipleOf", "x-nullable",
+	} {
+		if value, ok := s[key]; ok {
+			target[key] = value
+		}
+	}
+	if _, ok := target["type"]; !ok {
+		target["type"] = "string"
+	}
+}
+
+// rewriteRefs rewrites OpenAPI 3.x component references to Swagger 2.0 locations
+func rewriteRefs(node interface{}) {
+	switch n := node.(type) {
+	case map[string]interface{}:
+		for key, value := range n {
+			if ref, ok := value.(string); ok && key == "$ref" {
+				n[key] = downgradeRef(ref)
+				continue
+			}
+			rewriteRefs(value)
+		}
+	case []interface{}:
+		for _, item := range n {
+			rewriteRefs(item)
+		}
+	}
+}
+
+// downgradeRef converts a single OpenAPI 3.x reference to Swagger 2.0 format
+func downgradeRef(ref string) string {
+	replacements := []struct{ from, to string }{
+		{"#/components/schemas/", "#/definitions/"},
+		{"#/components/parameters/", "#/parameters/"},
+		{"#/components/requestBodies/", "#/parameters/"},
+		{"#/components/responses/", "#/responses/"},
+	}
+	for _, r := range replacements {
+		if strings.HasPrefix(ref, r.from) {
+			return r.to + strings.TrimPrefix(ref, r.from)
+		}
+	}
+	return ref
+}
+
+// consumesOf returns the content types of a request body for a consumes list
+func consumesOf(body map[string]interface{}) []interface{} {
+	var consumes []interface{}
+	for _, ct := range sortedKeys(asMap(body["content"])) {
+		consumes = append(consumes, ct)
+	}
+	return consumes
+}
+
+// copyExtensions copies x- vendor extensions from one object to another
+func copyExtensions(from, to map[string]interface{}) {
+	for key, value := range from {
+		if strings.HasPrefix(key, "x-") {
+			to[key] = value
+		}
+	}
+}
+
+// sortedKeys returns the keys of a map in sorted order
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// stringValue returns v as a string, or "" if it is not one
+func stringValue(v interface{}) string {
+	s, _ := v.(string)
+	return s
+}
@@ -0,0 +1,260 @@
+package converter
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// keyOrder ranks well-known OpenAPI keys so keys a conversion adds follow
+// the conventional layout of a spec instead of alphabetical order. Unknown
+// keys are sorted alphabetically after ranked keys, and vendor extensions
+// come last.
+var keyOrder = rankKeys(
+	// Document header
+	"swagger", "openapi", "info", "jsonSchemaDialect", "host", "basePath", "schemes",
+	"consumes", "produces", "servers",
+	// Identity and descriptive fields shared by most objects
+	"$ref", "name", "in", "tags", "title", "summary", "description",
+	"termsOfService", "contact", "license", "version", "externalDocs",
+	// Path items and operations
+	"get", "put", "post", "delete", "options", "head", "patch", "trace",
+	"operationId", "deprecated", "required",
+	// Schemas
+	"type", "format", "items", "properties", "additionalProperties",
+	"allOf", "anyOf", "oneOf", "not", "enum", "default", "nullable", "example", "examples",
+	// Operation and document bodies
+	"parameters", "requestBody", "responses", "callbacks", "security",
+	"paths", "webhooks", "components", "definitions", "securityDefinitions",
+	// Components and media
+	"schemas", "requestBodies", "headers", "securitySchemes", "links",
+	"content", "schema",
+)
+
+// rankKeys builds a key-to-position lookup
+func rankKeys(keys ...string) map[string]int {
+	ranks := make(map[string]int, len(keys))
+	for i, key := range keys {
+		if _, exists := ranks[key]; !exists {
+			ranks[key] = i
+		}
+	}
+	return ranks
+}
+
+// KeyOrder records the key order of every object in a source document, so
+// a converted document keeps the order of its paths, properties and other
+// keys
+type KeyOrder struct {
+	byPath   map[string][]string // keys of the object at a JSON pointer
+	byKeySet map[string][]string // keys of the first object with a key set
+}
+
+// ReadKeyOrder records the key order of a JSON or YAML document. Documents
+// that cannot be parsed yield an empty order.
+func ReadKeyOrder(data []byte) *KeyOrder {
+	order := &KeyOrder{byPath: make(map[string][]string), byKeySet: make(map[string][]string)}
+	var root yaml.Node
+	if err := yaml.Unmarshal(data, &root); err == nil && len(root.Content) > 0 {
+		order.record("", root.Content[0])
+	}
+	return order
+}
+
+// record walks a YAML node and records the key order of its mappings
+func (o *KeyOrder) record(path string, node *yaml.Node) {
+	switch node.Kind {
+	case yaml.MappingNode:
+		keys := make([]string, 0, len(node.Content)/2)
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			key := node.Content[i].Value
+			keys = append(keys, key)
+			o.record(path+"/"+escapePointer(key), node.Content[i+1])
+		}
+		o.byPath[path] = keys
+		if signature := keySet(keys); o.byKeySet[signature] == nil {
+			o.byKeySet[signature] = keys
+		}
+	case yaml.SequenceNode:
+		for i, item := range node.Content {
+			o.record(path+"/"+strconv.Itoa(i), item)
+		}
+	case yaml.DocumentNode, yaml.AliasNode:
+		if len(node.Content) > 0 {
+			o.record(path, node.Content[0])
+		}
+	}
+}
+
+// source returns the input keys of an output object: those of the object at
+// the same path, or else of an object with the same keys, such as the
+// properties of a schema moved from definitions to components
+func (o *KeyOrder) source(path string, keys []string) []string {
+	if o == nil {
+		return nil
+	}
+	if source, ok := o.byPath[path]; ok {
+		return source
+	}
+	return o.byKeySet[keySet(keys)]
+}
+
+// keySet returns an order-independent signature of a set of keys
+func keySet(keys []string) string {
+	sorted := slices.Clone(keys)
+	slices.Sort(sorted)
+	return strings.Join(sorted, "\x00")
+}
+
+// escapePointer escapes a key as a JSON pointer segment
+func escapePointer(key string) string {
+	return strings.NewReplacer("~", "~0", "/", "~1").Replace(key)
+}
+
+// orderedKeys returns map keys in output order. Keys found in the source
+// document keep their input order; keys the conversion added take their
+// ranked place among them.
+func orderedKeys(m map[string]interface{}, path string, order *KeyOrder) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+
+	sort.Slice(keys, func(i, j int) bool {
+		ki, kj := keys[i], keys[j]
+		xi, xj := strings.HasPrefix(ki, "x-"), strings.HasPrefix(kj, "x-")
+		if xi != xj {
+			return xj
+		}
+		ri, oki := keyOrder[ki]
+		rj, okj := keyOrder[kj]
+		switch {
+		case oki && okj:
+			return ri < rj
+		case oki != okj:
+			return oki
+		}
+		return ki < kj
+	})
+
+	// Fill the ranked slots of input keys with those keys in input order
+	var input []string
+	for _, key := range order.source(path, keys) {
+		if _, ok := m[key]; ok {
+			input = append(input, key)
+		}
+	}
+	if len(input) == 0 {
+		return keys
+	}
+	next := 0
+	for i, key := range keys {
+		if slices.Contains(input, key) {
+			keys[i] = input[next]
+			next++
+		}
+	}
+	return keys
+}
+
+// Marshal encodes a document as "json" or "yaml". Keys keep the order of
+// the source document recorded in order; a nil order, or keys the source
+// does not have, follow a stable conventional order.
+func Marshal(doc map[string]interface{}, format string, order *KeyOrder) ([]byte, error) {
+	switch format {
+	case "json":
+		var buf bytes.Buffer
+		if err := writeJSON(&buf, doc, "", order); err != nil {
+			return nil, err
+		}
+		var indented bytes.Buffer
+		if err := json.Indent(&indented, buf.Bytes(), "", "  "); err != nil {
+			return nil, fmt.Errorf("failed to format JSON: %w", err)
+		}
+		indented.WriteByte('\n')
+		return indented.Bytes(), nil
+
+	case "yaml":
+		var buf bytes.Buffer
+		encoder := yaml.NewEncoder(&buf)
+		encoder.SetIndent(2)
+		if err := encoder.Encode(toYAMLNode(doc, "", order)); err != nil {
+			return nil, fmt.Errorf("failed to marshal YAML: %w", err)
+		}
+		if err := encoder.Close(); err != nil {
+			return nil, fmt.Errorf("failed to marshal YAML: %w", err)
+		}
+		return buf.Bytes(), nil
+	}
+
+	return nil, fmt.Errorf("unsupported output format: %s (expected json or yaml)", format)
+}
+
+// writeJSON writes compact JSON with map keys in output order
+func writeJSON(buf *bytes.Buffer, value interface{}, path string, order *KeyOrder) error {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		buf.WriteByte('{')
+		for i, key := range orderedKeys(v, path, order) {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+			encodedKey, _ := json.Marshal(key)
+			buf.Write(encodedKey)
+			buf.WriteByte(':')
+			if err := writeJSON(buf, v[key], path+"/"+escapePointer(key), order); err != nil {
+				return err
+			}
+		}
+		buf.WriteByte('}')
+	case []interface{}:
+		buf.WriteByte('[')
+		for i, item := range v {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+			if err := writeJSON(buf, item, path+"/"+strconv.Itoa(i), order); err != nil {
+				return err
+			}
+		}
+		buf.WriteByte(']')
+	default:
+		encoded, err := json.Marshal(v)
+		if err != nil {
+			return fmt.Errorf("failed to marshal JSON: %w", err)
+		}
+		buf.Write(encoded)
+	}
+	return nil
+}
+
+// toYAMLNode builds a YAML node tree with map keys in output order
+func toYAMLNode(value interface{}, path string, order *KeyOrder) *yaml.Node {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		node := &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+		for _, key := range orderedKeys(v, path, order) {
+			keyNode := &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: key}
+			node.Content = append(node.Content, keyNode, toYAMLNode(v[key], path+"/"+escapePointer(key), order))
+		}
+		return node
+	case []interface{}:
+		node := &yaml.Node{Kind: yaml.SequenceNode, Tag: "!!seq"}
+		for i, item := range v {
+			node.Content = append(node.Content, toYAMLNode(item, path+"/"+strconv.Itoa(i), order))
+		}
+		return node
+	default:
+		node := &yaml.Node{}
+		if err := node.Encode(v); err != nil {
+			return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!null", Value: "null"}
+		}
+		return node
+	}
+}
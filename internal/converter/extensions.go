@@ -0,0 +1,149 @@
+package converter
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Known vendor extensions that encode OpenAPI 3.x concepts in Swagger 2.0 specs
+const (
+	extNullable = "x-nullable"
+	extExample  = "x-example"
+	extMSEnum   = "x-ms-enum"
+	extMSPaths  = "x-ms-paths"
+)
+
+// convertSchemaExtensions maps schema vendor extensions to native OpenAPI 3.x
+// keywords. Unknown extensions are copied unchanged.
+func (c *converter) convertSchemaExtensions(schema, converted map[string]interface{}) {
+	copyExtensions(schema, converted)
+
+	if nullable, ok := schema[extNullable].(bool); ok {
+		delete(converted, extNullable)
+		if _, exists := converted["nullable"]; !exists {
+			converted["nullable"] = nullable
+		}
+	}
+
+	if example, ok := schema[extExample]; ok {
+		delete(converted, extExample)
+		if _, exists := converted["example"]; !exists {
+			converted["example"] = example
+		}
+	}
+
+	if enum := msEnumValues(schema[extMSEnum]); len(enum) > 0 {
+		if _, exists := converted["enum"]; !exists {
+			converted["enum"] = enum
+		}
+	}
+}
+
+// msEnumValues extracts the values list of an x-ms-enum extension
+func msEnumValues(ext interface{}) []interface{} {
+	var values []interface{}
+	for _, v := range asSlice(asMap(ext)["values"]) {
+		if value, ok := asMap(v)["value"]; ok {
+			values = append(values, value)
+		}
+	}
+	return values
+}
+
+// convertParameterExtensions maps parameter vendor extensions; x-example
+// becomes the parameter example and the rest are copied unchanged
+func (c *converter) convertParameterExtensions(param, converted map[string]interface{}) {
+	copyExtensions(param, converted)
+
+	if example, ok := param[extExample]; ok {
+		delete(converted, extExample)
+		converted["example"] = example
+	}
+
+	if schema, ok := converted["schema"].(map[string]interface{}); ok {
+		if nullable, ok := param[extNullable].(bool); ok {
+			delete(converted, extNullable)
+			schema["nullable"] = nullable
+		}
+		if enum := msEnumValues(param[extMSEnum]); len(enum) > 0 {
+			if _, exists := schema["enum"]; !exists {
+				schema["enum"] = enum
+			}
+		}
+	}
+}
+
+// mergeMSPaths folds x-ms-paths entries into the regular paths. Azure uses
+// x-ms-paths for paths that differ only by query string, which OpenAPI 3.x
+// cannot express; the operations of entries whose base path and methods are
+// free are merged with the entry's path-level parameters, the rest stay
+// under x-ms-paths and are reported as warnings.
+func (c *converter) mergeMSPaths(paths map[string]interface{}, msPaths map[string]interface{}) map[string]interface{} {
+	remaining := make(map[string]interface{})
+
+	for _, path := range sortedKeys(msPaths) {
+		item := asMap(msPaths[path])
+		basePath, query, _ := strings.Cut(path, "?")
+
+		target := asMap(paths[basePath])
+		if target == nil {
+			target = make(map[string]interface{})
+		}
+
+		var methods []string
+		conflict := false
+		for _, method := range httpMethods {
+			if _, ok := item[method]; !ok {
+				continue
+			}
+			methods = append(methods, method)
+			if _, exists := target[method]; exists {
+				conflict = true
+			}
+		}
+		if conflict {
+			remaining[path] = item
+			c.warnings = append(c.warnings, fmt.Sprintf("x-ms-paths entry %s conflicts with %s and was kept as an extension", path, basePath))
+			continue
+		}
+
+		pathParams, _ := item["parameters"].([]interface{})
+		for _, method := range methods {
+			op := asMap(item[method])
+			if op != nil && len(pathParams) > 0 {
+				op["parameters"] = withPathParameters(pathParams, op["parameters"])
+			}
+			target[method] = item[method]
+		}
+		paths[basePath] = target
+		if query != "" && len(methods) > 0 {
+			c.warnings = append(c.warnings, fmt.Sprintf("x-ms-paths entry %s was merged into %s without its query string ?%s", path, basePath, query))
+		}
+	}
+
+	return remaining
+}
+
+// withPathParameters returns the parameters of an operation preceded by the
+// path-level parameters it does not override by name and location
+func withPathParameters(pathParams []interface{}, opParams interface{}) []interface{} {
+	own, _ := opParams.([]interface{})
+	overridden := func(param interface{}) bool {
+		p := asMap(param)
+		for _, o := range own {
+			q := asMap(o)
+			if p != nil && q != nil && p["name"] == q["name"] && p["in"] == q["in"] && p["$ref"] == q["$ref"] {
+				return true
+			}
+		}
+		return false
+	}
+
+	var merged []interface{}
+	for _, param := range pathParams {
+		if !overridden(param) {
+			merged = append(merged, param)
+		}
+	}
+	return append(merged, own...)
+}
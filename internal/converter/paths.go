@@ -1,5 +1,10 @@
 package converter
 
+import (
+	"fmt"
+	"strings"
+)
+
 // convertPaths converts Swagger 2.0 paths to OpenAPI 3.x format
 func (c *converter) convertPaths(paths map[string]interface{}) map[string]interface{} {
 	convertedPaths := make(map[string]interface{})
@@ -8,6 +13,14 @@ func (c *converter) convertPaths(paths map[string]interface{}) map[string]interf
 		if pathObj, ok := pathItem.(map[string]interface{}); ok {
 			convertedPath := make(map[string]interface{})
 
+			// Path-level parameters apply to every operation; body parameters
+			// have no path-level equivalent in OpenAPI 3.x
+			if params, ok := pathObj["parameters"].([]interface{}); ok {
+				if convertedParams, _ := c.convertParameters(params); len(convertedParams) > 0 {
+					convertedPath["parameters"] = convertedParams
+				}
+			}
+
 			// Convert each operation (get, post, put, delete, etc.)
 			for method, operation := range pathObj {
 				if opObj, ok := operation.(map[string]interface{}); ok {
@@ -34,6 +47,10 @@ func (c *converter) convertOperation(operation map[string]interface{}) map[strin
 		}
 	}
 
+	// Keep vendor extensions; x-ms-pageable and x-ms-long-running-operation
+	// have no native equivalent and are read by the pattern detector
+	copyExtensions(operation, converted)
+
 	// Convert parameters
 	if params, ok := operation["parameters"].([]interface{}); ok {
 		convertedParams, requestBody := c.convertParameters(params)
@@ -74,6 +91,22 @@ func (c *converter) convertParameters(params []interface{}) ([]interface{}, map[
 
 	for _, param := range params {
 		if p, ok := param.(map[string]interface{}); ok {
+			if ref, ok := p["$ref"].(string); ok {
+				// Shared parameter: body refs point at components.requestBodies
+				shared, ok := c.sharedParameter(ref)
+				switch {
+				case !ok:
+					// A dangling components reference would make the output invalid
+				case shared != nil && shared["in"] == "body":
+					requestBody = map[string]interface{}{
+						"$ref": "#/components/requestBodies/" + strings.TrimPrefix(ref, "#/parameters/"),
+					}
+				default:
+					parameters = append(parameters, map[string]interface{}{"$ref": c.convertRef(ref)})
+				}
+				continue
+			}
+
 			if p["in"] == "body" {
 				// Convert body parameter to requestBody
 				requestBody = c.convertBodyParameter(p)
@@ -87,6 +120,22 @@ func (c *converter) convertParameters(params []interface{}) ([]interface{}, map[
 	return parameters, requestBody
 }
 
+// sharedParameter resolves a #/parameters/ reference. Other references
+// resolve to nil. Unresolvable references are dropped with a warning.
+func (c *converter) sharedParameter(ref string) (map[string]interface{}, bool) {
+	name, ok := strings.CutPrefix(ref, "#/parameters/")
+	if !ok {
+		return nil, true
+	}
+
+	param, ok := c.parameters[name].(map[string]interface{})
+	if !ok {
+		c.warnings = append(c.warnings, fmt.Sprintf("dropped unresolved parameter reference: %s", ref))
+		return nil, false
+	}
+	return param, true
+}
+
 // convertParameter converts a non-body parameter
 func (c *converter) convertParameter(param map[string]interface{}) map[string]interface{} {
 	converted := make(map[string]interface{})
@@ -125,6 +174,9 @@ func (c *converter) convertParameter(param map[string]interface{}) map[string]in
 
 	converted["schema"] = schema
 
+	// Map vendor extensions (x-example, x-nullable, x-ms-enum)
+	c.convertParameterExtensions(param, converted)
+
 	return converted
 }
 
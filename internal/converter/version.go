@@ -0,0 +1,375 @@
+package converter
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Supported target versions for ConvertVersion
+const (
+	Version20 = "2.0"
+	Version30 = "3.0"
+	Version31 = "3.1"
+)
+
+// VersionConverter converts specifications between OpenAPI versions
+type VersionConverter interface {
+	// ConvertVersion converts a JSON or YAML spec to the target version (2.0, 3.0 or 3.1)
+	ConvertVersion(data []byte, target string) (map[string]interface{}, error)
+
+	// Warnings returns non-fatal issues found during the last conversion
+	Warnings() []string
+}
+
+// NewVersionConverter creates a new version converter instance
+func NewVersionConverter() VersionConverter {
+	return &converter{}
+}
+
+// ConvertVersion implements the VersionConverter interface
+func (c *converter) ConvertVersion(data []byte, target string) (map[string]interface{}, error) {
+	c.warnings = nil
+
+	doc, err := Decode(data)
+	if err != nil {
+		return nil, err
+	}
+
+	source, err := DetectVersion(doc)
+	if err != nil {
+		return nil, err
+	}
+
+	switch target {
+	case Version20, Version30, Version31:
+	default:
+		return nil, fmt.Errorf("unsupported target version: %s (expected 2.0, 3.0 or 3.1)", target)
+	}
+
+	// Bring Swagger 2.0 input up to OpenAPI 3.0 first; every other path starts from 3.x
+	if source == Version20 {
+		if target == Version20 {
+			return doc, nil
+		}
+		doc, err = c.convertSwagger(doc)
+		if err != nil {
+			return nil, err
+		}
+		if doc, err = normalize(doc); err != nil {
+			return nil, err
+		}
+		source = Version30
+	}
+
+	// 3.1 is handled as 3.0 plus a final upgrade step
+	if source == Version31 && target != Version31 {
+		c.downgradeTo30(doc)
+	}
+
+	switch target {
+	case Version31:
+		if source == Version30 {
+			c.upgradeTo31(doc)
+		}
+	case Version20:
+		doc = c.downgradeTo20(doc)
+	}
+
+	return doc, nil
+}
+
+// Decode parses a JSON or YAML document into a generic map
+func Decode(data []byte) (map[string]interface{}, error) {
+	var doc map[string]interface{}
+	if err := json.Unmarshal(data, &doc); err == nil {
+		return doc, nil
+	}
+
+	var yamlDoc interface{}
+	if err := yaml.Unmarshal(data, &yamlDoc); err != nil {
+		return nil, fmt.Errorf("unable to parse as JSON or YAML: %w", err)
+	}
+
+	// Round-trip through JSON so YAML and JSON input share the same value types
+	jsonData, err := json.Marshal(yamlDoc)
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert YAML to JSON: %w", err)
+	}
+	if err := json.Unmarshal(jsonData, &doc); err != nil {
+		return nil, fmt.Errorf("failed to decode document: %w", err)
+	}
+	if doc == nil {
+		return nil, fmt.Errorf("document is empty")
+	}
+
+	return doc, nil
+}
+
+// DetectVersion returns the major.minor spec version of a decoded document
+func DetectVersion(doc map[string]interface{}) (string, error) {
+	if v, ok := doc["swagger"].(string); ok {
+		if v != "2.0" {
+			return "", fmt.Errorf("unsupported Swagger version: %s", v)
+		}
+		return Version20, nil
+	}
+
+	if v, ok := doc["openapi"].(string); ok {
+		switch {
+		case strings.HasPrefix(v, "3.0"):
+			return Version30, nil
+		case strings.HasPrefix(v, "3.1"):
+			return Version31, nil
+		}
+		return "", fmt.Errorf("unsupported OpenAPI version: %s", v)
+	}
+
+	return "", fmt.Errorf("missing version field (openapi or swagger)")
+}
+
+// normalize round-trips a document through JSON so nested values use generic types
+func normalize(doc map[string]interface{}) (map[string]interface{}, error) {
+	data, err := json.Marshal(doc)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal document: %w", err)
+	}
+
+	var result map[string]interface{}
+	if err := json.Unmarshal(data, &result); err != nil {
+		return nil, fmt.Errorf("failed to decode document: %w", err)
+	}
+	return result, nil
+}
+
+// upgradeTo31 rewrites an OpenAPI 3.0 document in place as OpenAPI 3.1
+func (c *converter) upgradeTo31(doc map[string]interface{}) {
+	doc["openapi"] = "3.1.0"
+	forEachSchema(doc, upgradeSchemaTo31)
+}
+
+// upgradeSchemaTo31 converts 3.0-only schema keywords to their JSON Schema equivalents
+func upgradeSchemaTo31(schema map[string]interface{}) {
+	if nullable, ok := schema["nullable"].(bool); ok {
+		delete(schema, "nullable")
+		if nullable {
+			allowNull(schema)
+		}
+	}
+
+	// example becomes a single-item examples array
+	if example, ok := schema["example"]; ok {
+		delete(schema, "example")
+		if _, exists := schema["examples"]; !exists {
+			schema["examples"] = []interface{}{example}
+		}
+	}
+
+	// Boolean exclusive bounds take over the numeric bound
+	upgradeExclusiveBound(schema, "exclusiveMinimum", "minimum")
+	upgradeExclusiveBound(schema, "exclusiveMaximum", "maximum")
+}
+
+// allowNull replaces nullable: true. Typed schemas get a "null" member of the
+// type array, and $ref, allOf and other untyped schemas are wrapped as
+// anyOf: [<schema>, {type: "null"}]. An enum must list null to accept it.
+func allowNull(schema map[string]interface{}) {
+	if enum, ok := schema["enum"].([]interface{}); ok && !slices.Contains(enum, nil) {
+		schema["enum"] = append(enum, nil)
+	}
+	if t, ok := schema["type"].(string); ok {
+		schema["type"] = []interface{}{t, "null"}
+		return
+	}
+	if len(schema) == 0 {
+		return
+	}
+
+	inner := make(map[string]interface{}, len(schema))
+	for key, value := range schema {
+		inner[key] = value
+		delete(schema, key)
+	}
+	schema["anyOf"] = []interface{}{inner, map[string]interface{}{"type": "null"}}
+}
+
+// upgradeExclusiveBound turns {minimum: n, exclusiveMinimum: true} into {exclusiveMinimum: n}
+func upgradeExclusiveBound(schema map[string]interface{}, exclusiveKey, boundKey string) {
+	exclusive, ok := schema[exclusiveKey].(bool)
+	if !ok {
+		return
+	}
+
+	delete(schema, exclusiveKey)
+	if bound, ok := schema[boundKey]; ok && exclusive {
+		schema[exclusiveKey] = bound
+		delete(schema, boundKey)
+	}
+}
+
+// downgradeTo30 rewrites an OpenAPI 3.1 document in place as OpenAPI 3.0
+func (c *converter) downgradeTo30(doc map[string]interface{}) {
+	doc["openapi"] = "3.0.3"
+	if _, ok := doc["webhooks"]; ok {
+		delete(doc, "webhooks")
+		c.warnings = append(c.warnings, "webhooks are not supported before OpenAPI 3.1 and were dropped")
+	}
+	forEachSchema(doc, downgradeSchemaTo30)
+}
+
+// downgradeSchemaTo30 reverses upgradeSchemaTo31
+func downgradeSchemaTo30(schema map[string]interface{}) {
+	// anyOf: [<schema>, {type: "null"}] is a nullable schema
+	if anyOf, ok := schema["anyOf"].([]interface{}); ok && len(schema) == 1 && len(anyOf) == 2 {
+		for i, sub := range anyOf {
+			if t, ok := sub.(map[string]interface{}); ok && len(t) == 1 && t["type"] == "null" {
+				if other, ok := anyOf[1-i].(map[string]interface{}); ok {
+					delete(schema, "anyOf")
+					for key, value := range other {
+						schema[key] = value
+					}
+					schema["nullable"] = true
+				}
+				break
+			}
+		}
+	}
+
+	if types, ok := schema["type"].([]interface{}); ok {
+		var remaining []interface{}
+		for _, t := range types {
+			if t == "null" {
+				schema["nullable"] = true
+				continue
+			}
+			remaining = append(remaining, t)
+		}
+		switch len(remaining) {
+		case 0:
+			// type: ["null"] only allows null, which 3.0 cannot say without a type
+			delete(schema, "type")
+		case 1:
+			schema["type"] = remaining[0]
+		default:
+			schema["type"] = remaining
+		}
+	}
+
+	if examples, ok := schema["examples"].([]interface{}); ok {
+		delete(schema, "examples")
+		if len(examples) > 0 {
+			schema["example"] = examples[0]
+		}
+	}
+
+	for exclusiveKey, boundKey := range map[string]string{"exclusiveMinimum": "minimum", "exclusiveMaximum": "maximum"} {
+		if bound, ok := schema[exclusiveKey].(float64); ok {
+			schema[boundKey] = bound
+			schema[exclusiveKey] = true
+		}
+	}
+}
+
+// forEachSchema calls fn for every schema object in an OpenAPI 3.x document,
+// including nested property, item and composition schemas
+func forEachSchema(doc map[string]interface{}, fn func(schema map[string]interface{})) {
+	visit := func(schema interface{}) { walkSchema(schema, fn) }
+
+	if components, ok := doc["components"].(map[string]interface{}); ok {
+		for _, schema := range asMap(components["schemas"]) {
+			visit(schema)
+		}
+		for _, param := range asMap(components["parameters"]) {
+			visitParameterSchemas(param, visit)
+		}
+		for _, body := range asMap(components["requestBodies"]) {
+			visitContentSchemas(body, visit)
+		}
+		for _, response := range asMap(components["responses"]) {
+			visitResponseSchemas(response, visit)
+		}
+		for _, header := range asMap(components["headers"]) {
+			visitParameterSchemas(header, visit)
+		}
+	}
+
+	for _, pathItem := range asMap(doc["paths"]) {
+		item := asMap(pathItem)
+		for _, param := range asSlice(item["parameters"]) {
+			visitParameterSchemas(param, visit)
+		}
+		for _, method := range httpMethods {
+			op := asMap(item[method])
+			for _, param := range asSlice(op["parameters"]) {
+				visitParameterSchemas(param, visit)
+			}
+			visitContentSchemas(op["requestBody"], visit)
+			for _, response := range asMap(op["responses"]) {
+				visitResponseSchemas(response, visit)
+			}
+		}
+	}
+}
+
+// httpMethods lists the operation keys of a path item
+var httpMethods = []string{"get", "put", "post", "delete", "options", "head", "patch", "trace"}
+
+// walkSchema applies fn to a schema and all of its subschemas
+func walkSchema(schema interface{}, fn func(schema map[string]interface{})) {
+	s, ok := schema.(map[string]interface{})
+	if !ok {
+		return
+	}
+
+	fn(s)
+
+	for _, prop := range asMap(s["properties"]) {
+		walkSchema(prop, fn)
+	}
+	for _, key := range []string{"items", "additionalProperties", "not"} {
+		walkSchema(s[key], fn)
+	}
+	for _, key := range []string{"allOf", "anyOf", "oneOf"} {
+		for _, sub := range asSlice(s[key]) {
+			walkSchema(sub, fn)
+		}
+	}
+}
+
+// visitParameterSchemas visits the schema of a parameter or header object
+func visitParameterSchemas(param interface{}, visit func(interface{})) {
+	p := asMap(param)
+	visit(p["schema"])
+	visitContentSchemas(p, visit)
+}
+
+// visitResponseSchemas visits content and header schemas of a response object
+func visitResponseSchemas(response interface{}, visit func(interface{})) {
+	r := asMap(response)
+	visitContentSchemas(r, visit)
+	for _, header := range asMap(r["headers"]) {
+		visitParameterSchemas(header, visit)
+	}
+}
+
+// visitContentSchemas visits every media type schema of an object with content
+func visitContentSchemas(obj interface{}, visit func(interface{})) {
+	for _, mediaType := range asMap(asMap(obj)["content"]) {
+		visit(asMap(mediaType)["schema"])
+	}
+}
+
+// asMap returns v as a map, or nil if it is not one
+func asMap(v interface{}) map[string]interface{} {
+	m, _ := v.(map[string]interface{})
+	return m
+}
+
+// asSlice returns v as a slice, or nil if it is not one
+func asSlice(v interface{}) []interface{} {
+	s, _ := v.([]interface{})
+	return s
+}
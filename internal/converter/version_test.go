@@ -0,0 +1,453 @@
+package converter
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const openAPI30Spec = `
+openapi: 3.0.3
+info:
+  title: Test
+  version: "1.0"
+servers:
+  - url: https://api.example.com/v1
+paths:
+  /users:
+    post:
+      operationId: createUser
+      requestBody:
+        required: true
+        content:
+          application/json:
+            schema:
+              $ref: '#/components/schemas/User'
+      responses:
+        "201":
+          description: Created
+          content:
+            application/json:
+              schema:
+                $ref: '#/components/schemas/User'
+components:
+  schemas:
+    User:
+      type: object
+      properties:
+        nickname:
+          type: string
+          nullable: true
+          example: bob
+        age:
+          type: integer
+          minimum: 0
+          exclusiveMinimum: true
+`
+
+func TestConvertVersionUpgradeTo31(t *testing.T) {
+	c := NewVersionConverter()
+	doc, err := c.ConvertVersion([]byte(openAPI30Spec), Version31)
+	if err != nil {
+		t.Fatalf("ConvertVersion() error = %v", err)
+	}
+
+	if doc["openapi"] != "3.1.0" {
+		t.Errorf("Expected openapi 3.1.0, got %v", doc["openapi"])
+	}
+
+	props := asMap(asMap(asMap(asMap(doc["components"])["schemas"])["User"])["properties"])
+
+	nickname := asMap(props["nickname"])
+	if !reflect.DeepEqual(nickname["type"], []interface{}{"string", "null"}) {
+		t.Errorf("Expected nullable to become a type array, got %v", nickname["type"])
+	}
+	if _, ok := nickname["nullable"]; ok {
+		t.Error("Expected nullable keyword to be removed")
+	}
+	if !reflect.DeepEqual(nickname["examples"], []interface{}{"bob"}) {
+		t.Errorf("Expected example to become examples, got %v", nickname["examples"])
+	}
+
+	age := asMap(props["age"])
+	if age["exclusiveMinimum"] != float64(0) {
+		t.Errorf("Expected numeric exclusiveMinimum, got %v", age["exclusiveMinimum"])
+	}
+	if _, ok := age["minimum"]; ok {
+		t.Error("Expected minimum to be folded into exclusiveMinimum")
+	}
+}
+
+func TestConvertVersionRoundTrip31To30(t *testing.T) {
+	c := NewVersionConverter()
+	upgraded, err := c.ConvertVersion([]byte(openAPI30Spec), Version31)
+	if err != nil {
+		t.Fatalf("ConvertVersion() error = %v", err)
+	}
+	data, err := Marshal(upgraded, "yaml", nil)
+	if err != nil {
+		t.Fatalf("Marshal() error = %v", err)
+	}
+
+	doc, err := c.ConvertVersion(data, Version30)
+	if err != nil {
+		t.Fatalf("ConvertVersion() error = %v", err)
+	}
+
+	props := asMap(asMap(asMap(asMap(doc["components"])["schemas"])["User"])["properties"])
+	nickname := asMap(props["nickname"])
+	if nickname["type"] != "string" || nickname["nullable"] != true || nickname["example"] != "bob" {
+		t.Errorf("Expected 3.0 nullable string with example, got %v", nickname)
+	}
+	age := asMap(props["age"])
+	if age["minimum"] != float64(0) || age["exclusiveMinimum"] != true {
+		t.Errorf("Expected boolean exclusiveMinimum with minimum, got %v", age)
+	}
+}
+
+func TestConvertVersionDowngradeTo20(t *testing.T) {
+	c := NewVersionConverter()
+	doc, err := c.ConvertVersion([]byte(openAPI30Spec), Version20)
+	if err != nil {
+		t.Fatalf("ConvertVersion() error = %v", err)
+	}
+
+	if doc["swagger"] != "2.0" {
+		t.Errorf("Expected swagger 2.0, got %v", doc["swagger"])
+	}
+	if doc["host"] != "api.example.com" || doc["basePath"] != "/v1" {
+		t.Errorf("Expected host and basePath from servers, got %v %v", doc["host"], doc["basePath"])
+	}
+	if !reflect.DeepEqual(doc["schemes"], []interface{}{"https"}) {
+		t.Errorf("Expected https scheme, got %v", doc["schemes"])
+	}
+
+	user := asMap(asMap(doc["definitions"])["User"])
+	nickname := asMap(asMap(user["properties"])["nickname"])
+	if nickname["x-nullable"] != true {
+		t.Errorf("Expected nullable to become x-nullable, got %v", nickname)
+	}
+
+	post := asMap(asMap(asMap(doc["paths"])["/users"])["post"])
+	params := asSlice(post["parameters"])
+	if len(params) != 1 {
+		t.Fatalf("Expected one body parameter, got %v", params)
+	}
+	body := asMap(params[0])
+	if body["in"] != "body" || asMap(body["schema"])["$ref"] != "#/definitions/User" {
+		t.Errorf("Expected body parameter referencing #/definitions/User, got %v", body)
+	}
+	if !reflect.DeepEqual(post["consumes"], []interface{}{"application/json"}) {
+		t.Errorf("Expected consumes from request content, got %v", post["consumes"])
+	}
+
+	created := asMap(asMap(post["responses"])["201"])
+	if asMap(created["schema"])["$ref"] != "#/definitions/User" {
+		t.Errorf("Expected response schema ref to be rewritten, got %v", created["schema"])
+	}
+}
+
+func TestConvertVersionDowngradeServers(t *testing.T) {
+	tests := []struct {
+		name     string
+		servers  string
+		warnings int
+	}{
+		{"relative server", "  - url: /api\n", 0},
+		{"schemes of one host", "  - url: https://api.example.com/v1\n  - url: http://api.example.com/v1\n", 0},
+		{"other hosts", "  - url: https://api.example.com/v1\n  - url: https://staging.example.com/v1\n  - url: https://api.example.com/v2\n", 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			spec := "openapi: 3.0.3\ninfo:\n  title: Test\n  version: \"1.0\"\nservers:\n" + tt.servers + "paths: {}\n"
+			c := NewVersionConverter()
+			if _, err := c.ConvertVersion([]byte(spec), Version20); err != nil {
+				t.Fatalf("ConvertVersion() error = %v", err)
+			}
+			if len(c.Warnings()) != tt.warnings {
+				t.Errorf("Expected %d warnings, got %v", tt.warnings, c.Warnings())
+			}
+		})
+	}
+}
+
+func TestConvertVersionDowngradeSharedRequestBodies(t *testing.T) {
+	spec := `
+openapi: 3.0.3
+info:
+  title: Test
+  version: "1.0"
+paths:
+  /pets:
+    post:
+      parameters:
+        - $ref: '#/components/parameters/Pet'
+      requestBody:
+        $ref: '#/components/requestBodies/Pet'
+      responses:
+        "201":
+          description: Created
+  /uploads:
+    post:
+      requestBody:
+        $ref: '#/components/requestBodies/Upload'
+      responses:
+        "201":
+          description: Created
+components:
+  parameters:
+    Pet:
+      name: pet
+      in: query
+      schema:
+        type: string
+  requestBodies:
+    Pet:
+      content:
+        application/json:
+          schema:
+            type: object
+    Upload:
+      content:
+        multipart/form-data:
+          schema:
+            type: object
+            required: [file]
+            properties:
+              file:
+                type: string
+                format: binary
+              note:
+                type: string
+`
+	c := NewVersionConverter()
+	doc, err := c.ConvertVersion([]byte(spec), Version20)
+	if err != nil {
+		t.Fatalf("ConvertVersion() error = %v", err)
+	}
+
+	// The body is renamed instead of overwriting the query parameter
+	parameters := asMap(doc["parameters"])
+	if asMap(parameters["Pet"])["in"] != "query" || asMap(parameters["PetBody"])["in"] != "body" {
+		t.Errorf("Expected the Pet query parameter and the PetBody body parameter, got %v", parameters)
+	}
+	if _, ok := parameters["Upload"]; ok {
+		t.Errorf("Expected the form body to stay out of shared parameters, got %v", parameters["Upload"])
+	}
+	if len(c.Warnings()) != 1 || !strings.Contains(c.Warnings()[0], "PetBody") {
+		t.Errorf("Expected a rename warning, got %v", c.Warnings())
+	}
+
+	pets := asMap(asMap(asMap(doc["paths"])["/pets"])["post"])
+	expected := []interface{}{
+		map[string]interface{}{"$ref": "#/parameters/Pet"},
+		map[string]interface{}{"$ref": "#/parameters/PetBody"},
+	}
+	if !reflect.DeepEqual(pets["parameters"], expected) {
+		t.Errorf("Expected %v, got %v", expected, pets["parameters"])
+	}
+	if !reflect.DeepEqual(pets["consumes"], []interface{}{"application/json"}) {
+		t.Errorf("Expected consumes from the shared body, got %v", pets["consumes"])
+	}
+
+	uploads := asMap(asMap(asMap(doc["paths"])["/uploads"])["post"])
+	params := asSlice(uploads["parameters"])
+	if len(params) != 2 || asMap(params[0])["in"] != "formData" || asMap(params[0])["type"] != "file" || asMap(params[0])["required"] != true {
+		t.Errorf("Expected the form body expanded into formData parameters, got %v", params)
+	}
+}
+
+func TestConvertVersionFromSwagger(t *testing.T) {
+	swagger := `{
+		"swagger": "2.0",
+		"info": {"title": "Test", "version": "1.0"},
+		"definitions": {"User": {"type": "object", "properties": {"id": {"type": "string"}}}},
+		"paths": {}
+	}`
+
+	c := NewVersionConverter()
+	doc, err := c.ConvertVersion([]byte(swagger), Version31)
+	if err != nil {
+		t.Fatalf("ConvertVersion() error = %v", err)
+	}
+	if doc["openapi"] != "3.1.0" {
+		t.Errorf("Expected openapi 3.1.0, got %v", doc["openapi"])
+	}
+	if asMap(asMap(doc["components"])["schemas"])["User"] == nil {
+		t.Error("Expected definitions to be converted to components.schemas")
+	}
+}
+
+func TestConvertVersionErrors(t *testing.T) {
+	c := NewVersionConverter()
+
+	if _, err := c.ConvertVersion([]byte(openAPI30Spec), "4.0"); err == nil {
+		t.Error("Expected error for unsupported target version")
+	}
+	if _, err := c.ConvertVersion([]byte(`{"openapi": "2.5.0"}`), Version31); err == nil {
+		t.Error("Expected error for unsupported source version")
+	}
+}
+
+func TestMarshalStableKeyOrder(t *testing.T) {
+	doc := map[string]interface{}{
+		"x-internal": true,
+		"paths":      map[string]interface{}{},
+		"info":       map[string]interface{}{"version": "1.0", "title": "Test"},
+		"openapi":    "3.1.0",
+		"zebra":      1,
+	}
+
+	for _, format := range []string{"json", "yaml"} {
+		t.Run(format, func(t *testing.T) {
+			first, err := Marshal(doc, format, nil)
+			if err != nil {
+				t.Fatalf("Marshal() error = %v", err)
+			}
+			second, _ := Marshal(doc, format, nil)
+			if string(first) != string(second) {
+				t.Error("Expected identical output across runs")
+			}
+
+			output := string(first)
+			order := []string{"openapi", "info", "title", "version", "paths", "zebra", "x-internal"}
+			last := -1
+			for _, key := range order {
+				idx := strings.Index(output, key)
+				if idx < last {
+					t.Errorf("Expected %q to appear after previous keys in:\n%s", key, output)
+				}
+				last = idx
+			}
+		})
+	}
+
+	if _, err := Marshal(doc, "xml", nil); err == nil {
+		t.Error("Expected error for unsupported format")
+	}
+}
+
+func TestMarshalPreservesInputKeyOrder(t *testing.T) {
+	swagger := `{
+		"swagger": "2.0",
+		"info": {"version": "1.0", "title": "Zoo"},
+		"paths": {
+			"/zebras": {"get": {"responses": {"200": {"description": "OK"}}}},
+			"/apes": {"get": {"responses": {"200": {"description": "OK"}}}}
+		},
+		"definitions": {
+			"Zebra": {"type": "object", "properties": {"stripes": {"type": "integer"}, "name": {"type": "string"}, "id": {"type": "string"}}}
+		}
+	}`
+
+	for _, target := range []string{Version30, Version31} {
+		c := NewVersionConverter()
+		doc, err := c.ConvertVersion([]byte(swagger), target)
+		if err != nil {
+			t.Fatalf("ConvertVersion(%s) error = %v", target, err)
+		}
+		for _, format := range []string{"json", "yaml"} {
+			data, err := Marshal(doc, format, ReadKeyOrder([]byte(swagger)))
+			if err != nil {
+				t.Fatalf("Marshal() error = %v", err)
+			}
+			output := string(data)
+			order := []string{"openapi", "version", "title", "/zebras", "/apes", "stripes", "name", `id`}
+			last := -1
+			for _, key := range order {
+				idx := strings.Index(output, key)
+				if idx < last {
+					t.Errorf("%s %s: expected %q after previous keys in:\n%s", target, format, key, output)
+				}
+				last = idx
+			}
+		}
+	}
+}
+
+func TestUpgradeSchemaTo31(t *testing.T) {
+	tests := []struct {
+		name     string
+		schema   map[string]interface{}
+		expected map[string]interface{}
+	}{
+		{
+			name:     "nullable string",
+			schema:   map[string]interface{}{"type": "string", "nullable": true},
+			expected: map[string]interface{}{"type": []interface{}{"string", "null"}},
+		},
+		{
+			name:     "nullable enum",
+			schema:   map[string]interface{}{"type": "string", "enum": []interface{}{"open", "closed"}, "nullable": true},
+			expected: map[string]interface{}{"type": []interface{}{"string", "null"}, "enum": []interface{}{"open", "closed", nil}},
+		},
+		{
+			name:   "nullable reference",
+			schema: map[string]interface{}{"$ref": "#/components/schemas/User", "nullable": true},
+			expected: map[string]interface{}{"anyOf": []interface{}{
+				map[string]interface{}{"$ref": "#/components/schemas/User"},
+				map[string]interface{}{"type": "null"},
+			}},
+		},
+		{
+			name:   "nullable allOf",
+			schema: map[string]interface{}{"allOf": []interface{}{map[string]interface{}{"$ref": "#/components/schemas/User"}}, "nullable": true},
+			expected: map[string]interface{}{"anyOf": []interface{}{
+				map[string]interface{}{"allOf": []interface{}{map[string]interface{}{"$ref": "#/components/schemas/User"}}},
+				map[string]interface{}{"type": "null"},
+			}},
+		},
+		{
+			name:     "not nullable",
+			schema:   map[string]interface{}{"$ref": "#/components/schemas/User", "nullable": false},
+			expected: map[string]interface{}{"$ref": "#/components/schemas/User"},
+		},
+	}
+
+	for _, tt := range tests {
+		upgradeSchemaTo31(tt.schema)
+		if !reflect.DeepEqual(tt.schema, tt.expected) {
+			t.Errorf("%s: upgradeSchemaTo31() = %v, want %v", tt.name, tt.schema, tt.expected)
+		}
+	}
+}
+
+func TestDowngradeSchemaTo30(t *testing.T) {
+	tests := []struct {
+		name     string
+		schema   map[string]interface{}
+		expected map[string]interface{}
+	}{
+		{
+			name:     "nullable string",
+			schema:   map[string]interface{}{"type": []interface{}{"string", "null"}},
+			expected: map[string]interface{}{"type": "string", "nullable": true},
+		},
+		{
+			name:     "null only",
+			schema:   map[string]interface{}{"type": []interface{}{"null"}},
+			expected: map[string]interface{}{"nullable": true},
+		},
+		{
+			name: "nullable reference",
+			schema: map[string]interface{}{"anyOf": []interface{}{
+				map[string]interface{}{"$ref": "#/components/schemas/User"},
+				map[string]interface{}{"type": "null"},
+			}},
+			expected: map[string]interface{}{"$ref": "#/components/schemas/User", "nullable": true},
+		},
+		{
+			name:     "exclusive bound",
+			schema:   map[string]interface{}{"type": "integer", "exclusiveMinimum": float64(0)},
+			expected: map[string]interface{}{"type": "integer", "minimum": float64(0), "exclusiveMinimum": true},
+		},
+	}
+
+	for _, tt := range tests {
+		downgradeSchemaTo30(tt.schema)
+		if !reflect.DeepEqual(tt.schema, tt.expected) {
+			t.Errorf("%s: downgradeSchemaTo30() = %v, want %v", tt.name, tt.schema, tt.expected)
+		}
+	}
+}
@@ -0,0 +1,380 @@
+package graph
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"strings"
+	"unicode"
+
+	"github.com/orchard9/api-godoc/pkg/models"
+)
+
+// Export formats
+const (
+	FormatDOT       = "dot"       // Graphviz
+	FormatPlantUML  = "plantuml"  // PlantUML object diagram
+	FormatD2        = "d2"        // D2 (Terrastruct)
+	FormatGraphML   = "graphml"   // GraphML for yEd, Gephi and networkx
+	FormatCytoscape = "cytoscape" // Cytoscape.js elements JSON
+
+	FormatMermaidER    = "mermaid-er"    // Mermaid entity relationship diagram with fields
+	FormatMermaidClass = "mermaid-class" // Mermaid class diagram with fields
+)
+
+// Formats lists the export formats
+var Formats = []string{FormatDOT, FormatPlantUML, FormatD2, FormatGraphML, FormatCytoscape, FormatMermaidER, FormatMermaidClass}
+
+// Export writes a graph in one of the export formats
+func Export(g *Graph, format string) (string, error) {
+	switch format {
+	case FormatDOT:
+		return exportDOT(g), nil
+	case FormatPlantUML:
+		return exportPlantUML(g), nil
+	case FormatD2:
+		return exportD2(g), nil
+	case FormatGraphML:
+		return exportGraphML(g)
+	case FormatCytoscape:
+		return exportCytoscape(g)
+	case FormatMermaidER:
+		return exportMermaidER(g), nil
+	case FormatMermaidClass:
+		return exportMermaidClass(g), nil
+	}
+	return "", fmt.Errorf("unknown graph format: %s (expected %s)", format, strings.Join(Formats, ", "))
+}
+
+// exportDOT writes a Graphviz digraph with one cluster subgraph per cluster
+func exportDOT(g *Graph) string {
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("digraph %s {\n", dotQuote(g.Title)))
+	sb.WriteString("    rankdir=LR;\n")
+	sb.WriteString("    node [shape=box, style=rounded];\n")
+
+	writeNodes := func(indent string, nodes []Node) {
+		for _, node := range nodes {
+			sb.WriteString(fmt.Sprintf("%s%s [label=%s, operations=%d, category=%s, tag=%s];\n", indent, dotQuote(node.ID),
+				dotQuote(fmt.Sprintf("%s\\n%d ops", node.Label, node.Operations)), node.Operations, dotQuote(node.Category), dotQuote(node.Tag)))
+		}
+	}
+	if len(g.Clusters) == 0 {
+		writeNodes("    ", g.Nodes)
+	}
+	for _, cluster := range g.Clusters {
+		sb.WriteString(fmt.Sprintf("    subgraph %s {\n", dotQuote("cluster_"+cluster)))
+		sb.WriteString(fmt.Sprintf("        label=%s;\n", dotQuote(cluster)))
+		writeNodes("        ", g.ClusterNodes(cluster))
+		sb.WriteString("    }\n")
+	}
+
+	for _, edge := range g.Edges {
+		sb.WriteString(fmt.Sprintf("    %s -> %s [label=%s, type=%s, cardinality=%s, confidence=%.2f, via=%s];\n",
+			dotQuote(edge.From), dotQuote(edge.To), dotQuote(edgeLabel(edge)), dotQuote(edge.Type),
+			dotQuote(edge.Cardinality), edge.Confidence, dotQuote(edge.Via)))
+	}
+	sb.WriteString("}\n")
+	return sb.String()
+}
+
+// exportPlantUML writes a PlantUML object diagram with one package per
+// cluster and the cardinality on both ends of each edge
+func exportPlantUML(g *Graph) string {
+	var sb strings.Builder
+	sb.WriteString("@startuml\n")
+	if g.Title != "" {
+		sb.WriteString(fmt.Sprintf("title %s\n", g.Title))
+	}
+
+	writeNodes := func(indent string, nodes []Node) {
+		for _, node := range nodes {
+			sb.WriteString(fmt.Sprintf("%sobject \"%s\" as %s {\n", indent, plantUMLEscape(node.Label), identifier(node.ID)))
+			sb.WriteString(fmt.Sprintf("%s    operations = %d\n", indent, node.Operations))
+			if node.Category != "" {
+				sb.WriteString(fmt.Sprintf("%s    category = %s\n", indent, node.Category))
+			}
+			if node.Tag != "" {
+				sb.WriteString(fmt.Sprintf("%s    tag = %s\n", indent, plantUMLEscape(node.Tag)))
+			}
+			sb.WriteString(indent + "}\n")
+		}
+	}
+	if len(g.Clusters) == 0 {
+		writeNodes("", g.Nodes)
+	}
+	for _, cluster := range g.Clusters {
+		sb.WriteString(fmt.Sprintf("package \"%s\" {\n", plantUMLEscape(cluster)))
+		writeNodes("    ", g.ClusterNodes(cluster))
+		sb.WriteString("}\n")
+	}
+
+	for _, edge := range g.Edges {
+		from, to := cardinalityEnds(edge.Cardinality)
+		sb.WriteString(fmt.Sprintf("%s %s--> %s%s : %s\n", identifier(edge.From), from, to, identifier(edge.To), plantUMLEscape(edgeLabel(edge))))
+	}
+	sb.WriteString("@enduml\n")
+	return sb.String()
+}
+
+// exportD2 writes a D2 diagram with one container per cluster
+func exportD2(g *Graph) string {
+	var sb strings.Builder
+	if g.Title != "" {
+		sb.WriteString(fmt.Sprintf("title: %s {\n  near: top-center\n  shape: text\n}\n\n", d2Quote(g.Title)))
+	}
+	sb.WriteString("direction: right\n\n")
+
+	key := make(map[string]string)
+	writeNodes := func(indent, prefix string, nodes []Node) {
+		for _, node := range nodes {
+			key[node.ID] = prefix + d2Quote(node.ID)
+			sb.WriteString(fmt.Sprintf("%s%s: {\n", indent, d2Quote(node.ID)))
+			sb.WriteString(fmt.Sprintf("%s  label: %s\n", indent, d2Quote(fmt.Sprintf("%s\n%d ops", node.Label, node.Operations))))
+			sb.WriteString(fmt.Sprintf("%s  tooltip: %s\n", indent, d2Quote(nodeSummary(node))))
+			sb.WriteString(indent + "}\n")
+		}
+	}
+	if len(g.Clusters) == 0 {
+		writeNodes("", "", g.Nodes)
+	}
+	for _, cluster := range g.Clusters {
+		sb.WriteString(fmt.Sprintf("%s: {\n", d2Quote("cluster "+cluster)))
+		sb.WriteString(fmt.Sprintf("  label: %s\n", d2Quote(cluster)))
+		writeNodes("  ", d2Quote("cluster "+cluster)+".", g.ClusterNodes(cluster))
+		sb.WriteString("}\n")
+	}
+
+	if len(g.Edges) > 0 {
+		sb.WriteString("\n")
+	}
+	for _, edge := range g.Edges {
+		sb.WriteString(fmt.Sprintf("%s -> %s: %s\n", key[edge.From], key[edge.To], d2Quote(edgeLabel(edge))))
+	}
+	return sb.String()
+}
+
+// GraphML document structure
+type (
+	graphML struct {
+		XMLName xml.Name     `xml:"graphml"`
+		XMLNS   string       `xml:"xmlns,attr"`
+		Keys    []graphMLKey `xml:"key"`
+		Graph   graphMLGraph `xml:"graph"`
+	}
+	graphMLKey struct {
+		ID   string `xml:"id,attr"`
+		For  string `xml:"for,attr"`
+		Name string `xml:"attr.name,attr"`
+		Type string `xml:"attr.type,attr"`
+	}
+	graphMLGraph struct {
+		ID          string        `xml:"id,attr"`
+		EdgeDefault string        `xml:"edgedefault,attr"`
+		Nodes       []graphMLNode `xml:"node"`
+		Edges       []graphMLEdge `xml:"edge,omitempty"`
+	}
+	graphMLNode struct {
+		ID    string        `xml:"id,attr"`
+		Data  []graphMLData `xml:"data"`
+		Graph *graphMLGraph `xml:"graph,omitempty"`
+	}
+	graphMLEdge struct {
+		ID     string        `xml:"id,attr"`
+		Source string        `xml:"source,attr"`
+		Target string        `xml:"target,attr"`
+		Data   []graphMLData `xml:"data"`
+	}
+	graphMLData struct {
+		Key   string `xml:"key,attr"`
+		Value string `xml:",chardata"`
+	}
+)
+
+// exportGraphML writes GraphML with the node and edge attributes declared as
+// keys and each cluster as a node holding a nested graph
+func exportGraphML(g *Graph) (string, error) {
+	doc := graphML{
+		XMLNS: "http://graphml.graphdrawing.org/xmlns",
+		Keys: []graphMLKey{
+			{"label", "node", "label", "string"},
+			{"operations", "node", "operations", "int"},
+			{"category", "node", "category", "string"},
+			{"tag", "node", "tag", "string"},
+			{"type", "edge", "type", "string"},
+			{"via", "edge", "via", "string"},
+			{"cardinality", "edge", "cardinality", "string"},
+			{"confidence", "edge", "confidence", "double"},
+		},
+		Graph: graphMLGraph{ID: "resources", EdgeDefault: "directed"},
+	}
+
+	nodes := func(nodes []Node) []graphMLNode {
+		var result []graphMLNode
+		for _, node := range nodes {
+			result = append(result, graphMLNode{ID: node.ID, Data: []graphMLData{
+				{"label", node.Label},
+				{"operations", fmt.Sprint(node.Operations)},
+				{"category", node.Category},
+				{"tag", node.Tag},
+			}})
+		}
+		return result
+	}
+	if len(g.Clusters) == 0 {
+		doc.Graph.Nodes = nodes(g.Nodes)
+	}
+	for _, cluster := range g.Clusters {
+		doc.Graph.Nodes = append(doc.Graph.Nodes, graphMLNode{
+			ID:    "cluster:" + cluster,
+			Data:  []graphMLData{{"label", cluster}},
+			Graph: &graphMLGraph{ID: "cluster:" + cluster + ":", EdgeDefault: "directed", Nodes: nodes(g.ClusterNodes(cluster))},
+		})
+	}
+	for i, edge := range g.Edges {
+		doc.Graph.Edges = append(doc.Graph.Edges, graphMLEdge{ID: fmt.Sprintf("e%d", i), Source: edge.From, Target: edge.To, Data: []graphMLData{
+			{"type", edge.Type},
+			{"via", edge.Via},
+			{"cardinality", edge.Cardinality},
+			{"confidence", fmt.Sprintf("%.2f", edge.Confidence)},
+		}})
+	}
+
+	data, err := xml.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal GraphML: %w", err)
+	}
+	return xml.Header + string(data) + "\n", nil
+}
+
+// Cytoscape.js elements
+type (
+	cytoscapeElements struct {
+		Nodes []cytoscapeElement `json:"nodes"`
+		Edges []cytoscapeElement `json:"edges"`
+	}
+	cytoscapeElement struct {
+		Data map[string]any `json:"data"`
+	}
+)
+
+// exportCytoscape writes Cytoscape.js elements JSON with each cluster as a
+// compound parent node
+func exportCytoscape(g *Graph) (string, error) {
+	elements := cytoscapeElements{Nodes: []cytoscapeElement{}, Edges: []cytoscapeElement{}}
+	for _, cluster := range g.Clusters {
+		elements.Nodes = append(elements.Nodes, cytoscapeElement{Data: map[string]any{"id": "cluster:" + cluster, "label": cluster}})
+	}
+	for _, node := range g.Nodes {
+		data := map[string]any{
+			"id":         node.ID,
+			"label":      node.Label,
+			"operations": node.Operations,
+			"category":   node.Category,
+			"tag":        node.Tag,
+		}
+		if node.Cluster != "" {
+			data["parent"] = "cluster:" + node.Cluster
+		}
+		elements.Nodes = append(elements.Nodes, cytoscapeElement{Data: data})
+	}
+	for i, edge := range g.Edges {
+		elements.Edges = append(elements.Edges, cytoscapeElement{Data: map[string]any{
+			"id":          fmt.Sprintf("e%d", i),
+			"source":      edge.From,
+			"target":      edge.To,
+			"label":       edgeLabel(edge),
+			"type":        edge.Type,
+			"via":         edge.Via,
+			"cardinality": edge.Cardinality,
+			"confidence":  edge.Confidence,
+		}})
+	}
+
+	data, err := json.MarshalIndent(map[string]any{"elements": elements}, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal Cytoscape JSON: %w", err)
+	}
+	return string(data) + "\n", nil
+}
+
+// nodeSummary lists the attributes of a node, e.g. "operations: 4, category: core, tag: users"
+func nodeSummary(node Node) string {
+	summary := fmt.Sprintf("operations: %d", node.Operations)
+	if node.Category != "" {
+		summary += ", category: " + node.Category
+	}
+	if node.Tag != "" {
+		summary += ", tag: " + node.Tag
+	}
+	return summary
+}
+
+// edgeLabel summarizes an edge as "references customerId (N:1, 0.50)"
+func edgeLabel(edge Edge) string {
+	label := edge.Type
+	if edge.Via != "" && edge.Via != "path hierarchy" {
+		label += " " + edge.Via
+	}
+	details := []string{fmt.Sprintf("%.2f", edge.Confidence)}
+	if short := ShortCardinality(edge.Cardinality); short != "" {
+		details = append([]string{short}, details...)
+	}
+	return fmt.Sprintf("%s (%s)", label, strings.Join(details, ", "))
+}
+
+// ShortCardinality abbreviates a cardinality as 1:1, 1:N, N:1 or N:M
+func ShortCardinality(cardinality string) string {
+	switch cardinality {
+	case models.CardinalityOneToOne:
+		return "1:1"
+	case models.CardinalityOneToMany:
+		return "1:N"
+	case models.CardinalityManyToOne:
+		return "N:1"
+	case models.CardinalityManyToMany:
+		return "N:M"
+	}
+	return ""
+}
+
+// cardinalityEnds returns the PlantUML multiplicities of both ends of an
+// edge, e.g. "1" and "*" for one-to-many
+func cardinalityEnds(cardinality string) (string, string) {
+	switch cardinality {
+	case models.CardinalityOneToOne:
+		return `"1" `, `"1" `
+	case models.CardinalityOneToMany:
+		return `"1" `, `"*" `
+	case models.CardinalityManyToOne:
+		return `"*" `, `"1" `
+	case models.CardinalityManyToMany:
+		return `"*" `, `"*" `
+	}
+	return "", ""
+}
+
+// identifier converts a name to an identifier, e.g. "api-keys" to "api_keys"
+func identifier(name string) string {
+	return strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_' {
+			return r
+		}
+		return '_'
+	}, name)
+}
+
+// dotQuote quotes a Graphviz string
+func dotQuote(s string) string {
+	return `"` + strings.ReplaceAll(s, `"`, `\"`) + `"`
+}
+
+// d2Quote quotes a D2 string or key
+func d2Quote(s string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(s) + `"`
+}
+
+// plantUMLEscape keeps quotes and line breaks out of PlantUML labels
+func plantUMLEscape(s string) string {
+	return strings.NewReplacer(`"`, "'", "\n", " ").Replace(s)
+}
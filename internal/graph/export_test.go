@@ -0,0 +1,100 @@
+package graph
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"strings"
+	"testing"
+)
+
+func TestExport(t *testing.T) {
+	g, err := New(testAnalysis(), ClusterCategory)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	tests := []struct {
+		format   string
+		expected []string
+	}{
+		{FormatDOT, []string{
+			`digraph "Blog API" {`,
+			`    subgraph "cluster_core" {`,
+			`        "users" [label="User\n2 ops", operations=2, category="core", tag="people"];`,
+			`    "users" -> "posts" [label="has_many (1:N, 0.96)", type="has_many", cardinality="one-to-many", confidence=0.96, via="path hierarchy"];`,
+		}},
+		{FormatPlantUML, []string{
+			"@startuml\n",
+			"package \"auth\" {\n    object \"api-keys\" as api_keys {\n        operations = 1\n        category = auth\n    }\n}\n",
+			`users "1" --> "*" posts : has_many (1:N, 0.96)`,
+			"@enduml\n",
+		}},
+		{FormatD2, []string{
+			"\"cluster core\": {\n  label: \"core\"\n  \"posts\": {\n",
+			`  label: "User\n2 ops"`,
+			`tooltip: "operations: 2, category: core, tag: people"`,
+			`"cluster core"."users" -> "cluster core"."posts": "has_many (1:N, 0.96)"`,
+		}},
+		{FormatGraphML, []string{
+			`<key id="operations" for="node" attr.name="operations" attr.type="int"></key>`,
+			`<node id="cluster:core">`,
+			`<data key="tag">people</data>`,
+			`<edge id="e1" source="users" target="posts">`,
+			`<data key="confidence">0.96</data>`,
+		}},
+		{FormatCytoscape, []string{
+			`"id": "cluster:core"`,
+			`"parent": "cluster:core"`,
+			`"cardinality": "one-to-many"`,
+			`"confidence": 0.96`,
+		}},
+	}
+
+	for _, tt := range tests {
+		output, err := Export(g, tt.format)
+		if err != nil {
+			t.Fatalf("Export(%s) error = %v", tt.format, err)
+		}
+		for _, content := range tt.expected {
+			if !strings.Contains(output, content) {
+				t.Errorf("Expected %s output to contain %q, got:\n%s", tt.format, content, output)
+			}
+		}
+	}
+
+	if _, err := Export(g, "svg"); err == nil {
+		t.Error("Expected an error for an unknown format")
+	}
+}
+
+func TestExportWellFormed(t *testing.T) {
+	g, err := New(testAnalysis(), ClusterNone)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	output, err := Export(g, FormatGraphML)
+	if err != nil {
+		t.Fatalf("Export(graphml) error = %v", err)
+	}
+	if err := xml.Unmarshal([]byte(output), new(struct{})); err != nil {
+		t.Errorf("Expected well-formed GraphML, got %v", err)
+	}
+
+	output, err = Export(g, FormatCytoscape)
+	if err != nil {
+		t.Fatalf("Export(cytoscape) error = %v", err)
+	}
+	var elements struct {
+		Elements struct {
+			Nodes []map[string]map[string]any `json:"nodes"`
+			Edges []map[string]map[string]any `json:"edges"`
+		} `json:"elements"`
+	}
+	if err := json.Unmarshal([]byte(output), &elements); err != nil {
+		t.Fatalf("Expected valid JSON, got %v", err)
+	}
+	if len(elements.Elements.Nodes) != 3 || len(elements.Elements.Edges) != 2 {
+		t.Errorf("Expected 3 nodes and 2 edges without clusters, got %d and %d", len(elements.Elements.Nodes), len(elements.Elements.Edges))
+	}
+}
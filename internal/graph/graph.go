@@ -0,0 +1,329 @@
+// Package graph builds the resource relationship graph of an analysis and
+// exports it for graph tools
+package graph
+
+import (
+	"cmp"
+	"fmt"
+	"path"
+	"slices"
+	"strings"
+
+	"github.com/orchard9/api-godoc/pkg/models"
+)
+
+// Cluster modes group nodes by a resource attribute
+const (
+	ClusterCategory = "category" // core, admin, auth, ...
+	ClusterTag      = "tag"      // most used operation tag
+	ClusterNone     = "none"
+)
+
+// Graph is a directed graph of resources and their relationships
+type Graph struct {
+	Title    string
+	Nodes    []Node
+	Edges    []Edge
+	Clusters []string // cluster names in order; empty when not clustered
+
+	distance map[string]int // hops from the focus resource, set by Focus
+}
+
+// Node is a resource
+type Node struct {
+	ID         string  `json:"id"`    // resource name
+	Label      string  `json:"label"` // display name
+	Operations int     `json:"operations"`
+	Category   string  `json:"category,omitempty"`
+	Tag        string  `json:"tag,omitempty"`     // most used operation tag
+	Cluster    string  `json:"cluster,omitempty"` // category or tag the node is grouped under
+	Entry      string  `json:"entry,omitempty"`   // call that reads the resource, e.g. GET /users/{userId}
+	Fields     []Field `json:"fields,omitempty"`  // key fields first
+}
+
+// Field is a field of a resource schema
+type Field struct {
+	Name     string `json:"name"`
+	Type     string `json:"type"` // format, type or schema name; arrays end in []
+	Required bool   `json:"required,omitempty"`
+	Key      string `json:"key,omitempty"` // PK for the identifier, FK for a relationship field
+}
+
+// Edge is a relationship from one resource to another
+type Edge struct {
+	From        string  `json:"from"`
+	To          string  `json:"to"`
+	Type        string  `json:"type"` // has_many, belongs_to, references, ...
+	Via         string  `json:"via,omitempty"`
+	Cardinality string  `json:"cardinality,omitempty"`
+	Confidence  float64 `json:"confidence"`
+	Operation   string  `json:"operation,omitempty"` // call that follows the edge, e.g. GET /users/{userId}/posts
+}
+
+// New builds the graph of an analysis, clustering resources by category,
+// tag or not at all. Relationships to resources outside the analysis are
+// left out.
+func New(analysis *models.APIAnalysis, cluster string) (*Graph, error) {
+	switch cluster {
+	case "", ClusterNone, ClusterCategory, ClusterTag:
+	default:
+		return nil, fmt.Errorf("unknown cluster mode: %s (expected category, tag or none)", cluster)
+	}
+
+	g := &Graph{Title: analysis.Title}
+	known := make(map[string]*models.Resource)
+	for i, resource := range analysis.Resources {
+		known[resource.Name] = &analysis.Resources[i]
+	}
+
+	for _, resource := range analysis.Resources {
+		node := Node{
+			ID:         resource.Name,
+			Label:      resource.DisplayName,
+			Operations: len(resource.Operations),
+			Category:   resource.Category,
+			Tag:        primaryTag(resource.Operations),
+			Entry:      entryOperation(resource),
+			Fields:     keyFields(resource),
+		}
+		if node.Label == "" {
+			node.Label = resource.Name
+		}
+		switch cluster {
+		case ClusterCategory:
+			node.Cluster = cmp.Or(node.Category, "uncategorized")
+		case ClusterTag:
+			node.Cluster = cmp.Or(node.Tag, "untagged")
+		}
+		if node.Cluster != "" && !slices.Contains(g.Clusters, node.Cluster) {
+			g.Clusters = append(g.Clusters, node.Cluster)
+		}
+		g.Nodes = append(g.Nodes, node)
+
+		for _, rel := range resource.Relationships {
+			target := known[rel.Resource]
+			if target == nil {
+				continue
+			}
+			g.Edges = append(g.Edges, Edge{
+				From:        resource.Name,
+				To:          rel.Resource,
+				Type:        rel.Type,
+				Via:         rel.Via,
+				Cardinality: rel.Cardinality,
+				Confidence:  rel.Confidence,
+				Operation:   walkOperation(resource, *target, rel),
+			})
+		}
+	}
+
+	// Sort so exports of the same API diff cleanly
+	slices.Sort(g.Clusters)
+	slices.SortFunc(g.Nodes, func(a, b Node) int { return cmp.Compare(a.ID, b.ID) })
+	slices.SortFunc(g.Edges, func(a, b Edge) int {
+		return cmp.Or(cmp.Compare(a.From, b.From), cmp.Compare(a.To, b.To), cmp.Compare(a.Type, b.Type), cmp.Compare(a.Via, b.Via))
+	})
+	return g, nil
+}
+
+// ClusterNodes returns the nodes of a cluster, or every node when the graph
+// is not clustered
+func (g *Graph) ClusterNodes(cluster string) []Node {
+	var nodes []Node
+	for _, node := range g.Nodes {
+		if node.Cluster == cluster {
+			nodes = append(nodes, node)
+		}
+	}
+	return nodes
+}
+
+// Focus keeps a resource and the resources within hops relationships of it
+// in either direction
+func (g *Graph) Focus(name string, hops int) error {
+	if hops < 0 {
+		return fmt.Errorf("hops must not be negative: %d", hops)
+	}
+	focus, err := g.Find(name)
+	if err != nil {
+		return err
+	}
+
+	distance := map[string]int{focus.ID: 0}
+	frontier := []string{focus.ID}
+	for hop := 1; hop <= hops && len(frontier) > 0; hop++ {
+		var next []string
+		for _, edge := range g.Edges {
+			for _, pair := range [][2]string{{edge.From, edge.To}, {edge.To, edge.From}} {
+				if _, seen := distance[pair[1]]; !seen && slices.Contains(frontier, pair[0]) {
+					distance[pair[1]] = hop
+					next = append(next, pair[1])
+				}
+			}
+		}
+		frontier = next
+	}
+
+	g.retain(func(id string) bool {
+		_, ok := distance[id]
+		return ok
+	})
+	g.distance = distance
+	return nil
+}
+
+// Limit keeps at most maxNodes nodes: the nearest to the focus resource
+// when focused, then the most connected. Zero keeps every node.
+func (g *Graph) Limit(maxNodes int) {
+	if maxNodes <= 0 || len(g.Nodes) <= maxNodes {
+		return
+	}
+	ranked := g.Degrees()
+	slices.SortStableFunc(ranked, func(a, b Degree) int {
+		return cmp.Compare(g.distance[a.Resource], g.distance[b.Resource])
+	})
+	keep := make(map[string]bool)
+	for _, degree := range ranked[:maxNodes] {
+		keep[degree.Resource] = true
+	}
+	g.retain(func(id string) bool { return keep[id] })
+}
+
+// LimitFields keeps at most maxFields fields per node, key fields first.
+// Zero keeps every field.
+func (g *Graph) LimitFields(maxFields int) {
+	if maxFields <= 0 {
+		return
+	}
+	for i := range g.Nodes {
+		if len(g.Nodes[i].Fields) > maxFields {
+			g.Nodes[i].Fields = g.Nodes[i].Fields[:maxFields]
+		}
+	}
+}
+
+// retain drops the nodes keep rejects, their edges and empty clusters
+func (g *Graph) retain(keep func(id string) bool) {
+	g.Nodes = slices.DeleteFunc(g.Nodes, func(node Node) bool { return !keep(node.ID) })
+	g.Edges = slices.DeleteFunc(g.Edges, func(edge Edge) bool { return !keep(edge.From) || !keep(edge.To) })
+	g.Clusters = slices.DeleteFunc(g.Clusters, func(cluster string) bool { return len(g.ClusterNodes(cluster)) == 0 })
+}
+
+// keyFields returns the fields of a resource: the identifier, then the
+// fields relationships go through, then required fields, each by name
+func keyFields(resource models.Resource) []Field {
+	var fields []Field
+	for _, f := range resource.Fields {
+		field := Field{Name: f.Name, Type: fieldType(f.Type), Required: f.Required}
+		if f.Name == "id" {
+			field.Key = "PK"
+		} else if slices.ContainsFunc(resource.Relationships, func(rel models.Relationship) bool { return rel.Via == f.Name }) {
+			field.Key = "FK"
+		}
+		fields = append(fields, field)
+	}
+
+	rank := func(field Field) int {
+		switch {
+		case field.Key == "PK":
+			return 0
+		case field.Key == "FK":
+			return 1
+		case field.Required:
+			return 2
+		}
+		return 3
+	}
+	slices.SortFunc(fields, func(a, b Field) int {
+		return cmp.Or(cmp.Compare(rank(a), rank(b)), cmp.Compare(a.Name, b.Name))
+	})
+	return fields
+}
+
+// fieldType names a field type: its format such as date-time, its type, or
+// its schema for references; arrays as their item type followed by []
+func fieldType(t models.FieldType) string {
+	switch {
+	case t.Type == "array" && t.Items != nil:
+		return fieldType(*t.Items) + "[]"
+	case t.Format != "":
+		return t.Format
+	case t.Type != "":
+		return t.Type
+	case t.Reference != "":
+		return path.Base(t.Reference)
+	}
+	return "any"
+}
+
+// primaryTag returns the tag most operations carry, the first by name on a tie
+func primaryTag(operations []models.Operation) string {
+	counts := make(map[string]int)
+	for _, op := range operations {
+		for _, tag := range op.Tags {
+			counts[tag]++
+		}
+	}
+	primary := ""
+	for tag, count := range counts {
+		if count > counts[primary] || count == counts[primary] && tag < primary {
+			primary = tag
+		}
+	}
+	return primary
+}
+
+// entryOperation returns the call that reads one item of a resource, or
+// lists it when items cannot be read one by one. Item paths such as
+// /tasks/{taskId} win over other reads such as /tasks/{taskId}/blockedBy.
+func entryOperation(resource models.Resource) string {
+	for _, op := range resource.Operations {
+		if op.Kind == models.KindGet && strings.HasSuffix(op.Path, "}") {
+			return op.Method + " " + op.Path
+		}
+	}
+	for _, kind := range []string{models.KindGet, models.KindList} {
+		for _, op := range resource.Operations {
+			if op.Kind == kind {
+				return op.Method + " " + op.Path
+			}
+		}
+	}
+	for _, op := range resource.Operations {
+		if op.Method == "GET" {
+			return op.Method + " " + op.Path
+		}
+	}
+	return ""
+}
+
+// walkOperation returns the call that follows a relationship: a read among
+// the linked or association operations, a read nested under an item of the
+// source such as GET /users/{userId}/posts, or else a read of the target
+func walkOperation(source, target models.Resource, rel models.Relationship) string {
+	for _, operation := range rel.Operations {
+		if strings.HasPrefix(operation, "GET ") {
+			return operation
+		}
+	}
+
+	preferred := models.KindGet
+	if rel.Cardinality == models.CardinalityOneToMany || rel.Cardinality == models.CardinalityManyToMany {
+		preferred = models.KindList
+	}
+	nested := ""
+	for _, op := range target.Operations {
+		if op.Method != "GET" || !slices.ContainsFunc(source.Operations, func(sourceOp models.Operation) bool {
+			return strings.HasSuffix(sourceOp.Path, "}") && strings.HasPrefix(op.Path, sourceOp.Path+"/")
+		}) {
+			continue
+		}
+		if op.Kind == preferred {
+			return op.Method + " " + op.Path
+		}
+		if nested == "" {
+			nested = op.Method + " " + op.Path
+		}
+	}
+	return cmp.Or(nested, entryOperation(target))
+}
@@ -0,0 +1,219 @@
+package graph
+
+import (
+	"reflect"
+	"slices"
+	"testing"
+
+	"github.com/orchard9/api-godoc/pkg/models"
+)
+
+// testAnalysis returns users with posts and an api-keys resource that
+// refers to a resource outside the analysis
+func testAnalysis() *models.APIAnalysis {
+	return &models.APIAnalysis{
+		Title: "Blog API",
+		Resources: []models.Resource{
+			{
+				Name: "users", DisplayName: "User", Category: models.CategoryCore,
+				Operations: []models.Operation{
+					{Method: "GET", Path: "/users", Kind: models.KindList, Tags: []string{"people"}},
+					{Method: "GET", Path: "/users/{userId}", Kind: models.KindGet, Tags: []string{"people", "admin"}},
+				},
+				Relationships: []models.Relationship{
+					{Resource: "posts", Type: "has_many", Via: "path hierarchy", Cardinality: models.CardinalityOneToMany, Confidence: 0.96},
+				},
+				Fields: []models.Field{
+					{Name: "tags", Type: models.FieldType{Type: "array", Items: &models.FieldType{Type: "string"}}},
+					{Name: "email", Type: models.FieldType{Type: "string", Format: "email"}, Required: true},
+					{Name: "id", Type: models.FieldType{Type: "string", Format: "uuid"}},
+				},
+			},
+			{
+				Name: "posts", Category: models.CategoryCore,
+				Operations: []models.Operation{{Method: "GET", Path: "/users/{userId}/posts", Kind: models.KindList, Tags: []string{"content"}}},
+				Relationships: []models.Relationship{
+					{Resource: "users", Type: "belongs_to", Via: "path hierarchy", Cardinality: models.CardinalityManyToOne, Confidence: 0.96},
+				},
+			},
+			{
+				Name: "api-keys", Category: models.CategoryAuth,
+				Operations: []models.Operation{{Method: "POST", Path: "/api-keys", Kind: models.KindCreate}},
+				Relationships: []models.Relationship{
+					{Resource: "accounts", Type: "references", Via: "accountId", Confidence: 0.5},
+				},
+				Fields: []models.Field{
+					{Name: "scope", Type: models.FieldType{Type: "Scope", Reference: "#/components/schemas/Scope"}},
+					{Name: "accountId", Type: models.FieldType{Type: "string"}},
+				},
+			},
+		},
+	}
+}
+
+func TestNew(t *testing.T) {
+	g, err := New(testAnalysis(), ClusterCategory)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	expectedNodes := []Node{
+		{ID: "api-keys", Label: "api-keys", Operations: 1, Category: "auth", Cluster: "auth", Fields: []Field{
+			{Name: "accountId", Type: "string", Key: "FK"},
+			{Name: "scope", Type: "Scope"},
+		}},
+		{ID: "posts", Label: "posts", Operations: 1, Category: "core", Tag: "content", Cluster: "core", Entry: "GET /users/{userId}/posts"},
+		{ID: "users", Label: "User", Operations: 2, Category: "core", Tag: "people", Cluster: "core", Entry: "GET /users/{userId}", Fields: []Field{
+			{Name: "id", Type: "uuid", Key: "PK"},
+			{Name: "email", Type: "email", Required: true},
+			{Name: "tags", Type: "string[]"},
+		}},
+	}
+	if !reflect.DeepEqual(g.Nodes, expectedNodes) {
+		t.Errorf("Expected nodes %+v, got %+v", expectedNodes, g.Nodes)
+	}
+	if !reflect.DeepEqual(g.Clusters, []string{"auth", "core"}) {
+		t.Errorf("Expected clusters auth and core, got %v", g.Clusters)
+	}
+	expectedEdges := []Edge{
+		{From: "posts", To: "users", Type: "belongs_to", Via: "path hierarchy", Cardinality: models.CardinalityManyToOne, Confidence: 0.96, Operation: "GET /users/{userId}"},
+		{From: "users", To: "posts", Type: "has_many", Via: "path hierarchy", Cardinality: models.CardinalityOneToMany, Confidence: 0.96, Operation: "GET /users/{userId}/posts"},
+	}
+	if !reflect.DeepEqual(g.Edges, expectedEdges) {
+		t.Errorf("Expected edges %+v, got %+v", expectedEdges, g.Edges)
+	}
+}
+
+func TestNewClusters(t *testing.T) {
+	tests := []struct {
+		cluster  string
+		expected []string
+		wantErr  bool
+	}{
+		{ClusterTag, []string{"content", "people", "untagged"}, false},
+		{ClusterNone, nil, false},
+		{"", nil, false},
+		{"context", nil, true},
+	}
+
+	for _, tt := range tests {
+		g, err := New(testAnalysis(), tt.cluster)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("New(%q) error = %v, wantErr %v", tt.cluster, err, tt.wantErr)
+			continue
+		}
+		if err == nil && !reflect.DeepEqual(g.Clusters, tt.expected) {
+			t.Errorf("New(%q) clusters = %v, want %v", tt.cluster, g.Clusters, tt.expected)
+		}
+	}
+}
+
+func TestWalkOperation(t *testing.T) {
+	groups := models.Resource{Name: "groups", Operations: []models.Operation{{Method: "GET", Path: "/groups/{groupId}", Kind: models.KindGet}}}
+	users := models.Resource{Name: "users", Operations: []models.Operation{
+		{Method: "GET", Path: "/users", Kind: models.KindList},
+		{Method: "GET", Path: "/users/{userId}/avatar", Kind: models.KindGet},
+		{Method: "GET", Path: "/users/{userId}", Kind: models.KindGet},
+	}}
+	members := models.Resource{Name: "members", Operations: []models.Operation{
+		{Method: "GET", Path: "/groups/{groupId}/members/{memberId}", Kind: models.KindGet},
+		{Method: "GET", Path: "/groups/{groupId}/members", Kind: models.KindList},
+	}}
+
+	tests := []struct {
+		name           string
+		source, target models.Resource
+		rel            models.Relationship
+		expected       string
+	}{
+		{"linked read", groups, users, models.Relationship{Operations: []string{"PUT /groups/{groupId}/users/{userId}", "GET /groups/{groupId}/users"}}, "GET /groups/{groupId}/users"},
+		{"nested list", groups, members, models.Relationship{Cardinality: models.CardinalityOneToMany}, "GET /groups/{groupId}/members"},
+		{"nested item", groups, members, models.Relationship{Cardinality: models.CardinalityOneToOne}, "GET /groups/{groupId}/members/{memberId}"},
+		{"read by identifier", members, users, models.Relationship{Cardinality: models.CardinalityManyToOne}, "GET /users/{userId}"},
+		{"unreadable target", users, models.Resource{Name: "exports"}, models.Relationship{}, ""},
+	}
+
+	for _, tt := range tests {
+		if got := walkOperation(tt.source, tt.target, tt.rel); got != tt.expected {
+			t.Errorf("%s: walkOperation() = %q, want %q", tt.name, got, tt.expected)
+		}
+	}
+}
+
+func TestFocus(t *testing.T) {
+	tests := []struct {
+		name     string
+		focus    string
+		hops     int
+		expected []string
+		wantErr  bool
+	}{
+		{"resource alone", "organizations", 0, []string{"organizations"}, false},
+		{"neighbors", "Project", 1, []string{"invoices", "organizations", "projects", "users"}, false},
+		{"two hops", "organizations", 2, []string{"invoices", "organizations", "projects", "users"}, false},
+		{"unknown resource", "payments", 1, nil, true},
+		{"negative hops", "projects", -1, nil, true},
+	}
+
+	for _, tt := range tests {
+		g := queryGraph(t)
+		err := g.Focus(tt.focus, tt.hops)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("%s: Focus() error = %v, wantErr %v", tt.name, err, tt.wantErr)
+			continue
+		}
+		if err != nil {
+			continue
+		}
+		var ids []string
+		for _, node := range g.Nodes {
+			ids = append(ids, node.ID)
+		}
+		if !reflect.DeepEqual(ids, tt.expected) {
+			t.Errorf("%s: Focus() kept %v, want %v", tt.name, ids, tt.expected)
+		}
+		for _, edge := range g.Edges {
+			if !slices.Contains(ids, edge.From) || !slices.Contains(ids, edge.To) {
+				t.Errorf("%s: Focus() kept edge %s -> %s to a dropped resource", tt.name, edge.From, edge.To)
+			}
+		}
+	}
+}
+
+func TestLimit(t *testing.T) {
+	g := queryGraph(t)
+	g.Limit(2)
+	if len(g.Nodes) != 2 || g.Nodes[0].ID != "leads" || g.Nodes[1].ID != "projects" {
+		t.Errorf("Expected the two most connected resources, got %+v", g.Nodes)
+	}
+	if len(g.Edges) != 0 {
+		t.Errorf("Expected no edges between leads and projects, got %+v", g.Edges)
+	}
+
+	// Focused graphs keep the resources nearest the focus
+	g = queryGraph(t)
+	if err := g.Focus("invoices", 2); err != nil {
+		t.Fatalf("Focus() error = %v", err)
+	}
+	g.Limit(2)
+	if len(g.Nodes) != 2 || g.Nodes[0].ID != "invoices" || g.Nodes[1].ID != "projects" {
+		t.Errorf("Expected invoices and projects, got %+v", g.Nodes)
+	}
+
+	g, err := New(testAnalysis(), ClusterCategory)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	g.Limit(0)
+	g.LimitFields(1)
+	if len(g.Nodes) != 3 {
+		t.Errorf("Expected Limit(0) to keep every node, got %d", len(g.Nodes))
+	}
+	if users := g.Nodes[2]; len(users.Fields) != 1 || users.Fields[0].Name != "id" {
+		t.Errorf("Expected only the identifier of users, got %+v", users.Fields)
+	}
+	g.Limit(1)
+	if !reflect.DeepEqual(g.Clusters, []string{"core"}) {
+		t.Errorf("Expected empty clusters to be dropped, got %v", g.Clusters)
+	}
+}
@@ -0,0 +1,185 @@
+package graph
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/orchard9/api-godoc/pkg/models"
+)
+
+// exportMermaidER writes a Mermaid entity relationship diagram with the
+// fields of each resource. Edges between the same resources are drawn once,
+// preferring has_many and references over their inverses; edges without a
+// cardinality are left out. Clusters are not drawn.
+func exportMermaidER(g *Graph) string {
+	var sb strings.Builder
+	writeMermaidTitle(&sb, g.Title)
+	sb.WriteString("erDiagram\n")
+
+	ids := mermaidIDs(g.Nodes)
+	for _, node := range g.Nodes {
+		sb.WriteString("    " + mermaidEntity(ids[node.ID], node.Label))
+		if len(node.Fields) == 0 {
+			sb.WriteString("\n")
+			continue
+		}
+		sb.WriteString(" {\n")
+		for _, field := range node.Fields {
+			line := fmt.Sprintf("        %s %s", mermaidAttribute(field.Type, "[]"), mermaidAttribute(field.Name, ""))
+			if field.Key != "" {
+				line += " " + field.Key
+			}
+			if field.Required {
+				line += ` "required"`
+			}
+			sb.WriteString(line + "\n")
+		}
+		sb.WriteString("    }\n")
+	}
+
+	edges := slices.DeleteFunc(slices.Clone(g.Edges), func(edge Edge) bool { return edge.Cardinality == "" })
+	for _, edge := range mermaidEdges(edges) {
+		line := "--"
+		if edge.Via != "path hierarchy" {
+			line = ".."
+		}
+		// Graph edges carry no required flag; a many-to-one edge always has its target
+		left, right := CrowsFootEnds(edge.Cardinality, edge.Cardinality == models.CardinalityManyToOne)
+		sb.WriteString(fmt.Sprintf("    %s %s%s%s %s : %s\n", ids[edge.From], left, line, right, ids[edge.To], `"`+mermaidText(edgeLabel(edge))+`"`))
+	}
+	return sb.String()
+}
+
+// exportMermaidClass writes a Mermaid class diagram with the fields of each
+// resource as attributes. Path hierarchies are compositions, other
+// relationships associations, drawn once per pair of resources like the
+// entity relationship diagram. Clusters are not drawn.
+func exportMermaidClass(g *Graph) string {
+	var sb strings.Builder
+	writeMermaidTitle(&sb, g.Title)
+	sb.WriteString("classDiagram\n")
+
+	ids := mermaidIDs(g.Nodes)
+	for _, node := range g.Nodes {
+		sb.WriteString("    class " + mermaidEntity(ids[node.ID], node.Label))
+		if len(node.Fields) == 0 {
+			sb.WriteString("\n")
+			continue
+		}
+		sb.WriteString(" {\n")
+		for _, field := range node.Fields {
+			sb.WriteString(fmt.Sprintf("        +%s %s\n", mermaidAttribute(field.Type, "[]"), mermaidAttribute(field.Name, "")))
+		}
+		sb.WriteString("    }\n")
+	}
+
+	for _, edge := range mermaidEdges(g.Edges) {
+		arrow := "-->"
+		if edge.Type == "has_many" && edge.Via == "path hierarchy" {
+			arrow = "*--"
+		}
+		from, to := cardinalityEnds(edge.Cardinality)
+		sb.WriteString(fmt.Sprintf("    %s %s%s %s%s : %s\n", ids[edge.From], from, arrow, to, ids[edge.To], mermaidText(edgeLabel(edge))))
+	}
+	return sb.String()
+}
+
+// inverseTypes are the reverse side of another relationship type
+var inverseTypes = map[string]bool{"belongs_to": true, "referenced_by": true}
+
+// mermaidEdges returns one edge per pair of resources, the forward side of
+// a relationship when the graph has it
+func mermaidEdges(edges []Edge) []Edge {
+	var result []Edge
+	drawn := make(map[[2]string]bool)
+	for _, inverse := range []bool{false, true} {
+		for _, edge := range edges {
+			pair := [2]string{min(edge.From, edge.To), max(edge.From, edge.To)}
+			if inverseTypes[edge.Type] != inverse || drawn[pair] {
+				continue
+			}
+			drawn[pair] = true
+			result = append(result, edge)
+		}
+	}
+	return result
+}
+
+// CrowsFootEnds returns the Mermaid erDiagram ends of a relationship, e.g.
+// "||" and "o{" for one-to-many. The left end is how many sources a target
+// has, the right end how many targets a source has; required makes the
+// right end mandatory.
+func CrowsFootEnds(cardinality string, required bool) (string, string) {
+	left, right := "||", "o|"
+	switch cardinality {
+	case models.CardinalityOneToMany:
+		right = "o{"
+	case models.CardinalityManyToOne:
+		left = "}o"
+	case models.CardinalityManyToMany:
+		left, right = "}o", "o{"
+	}
+	if required {
+		right = "|" + right[1:]
+	}
+	return left, right
+}
+
+// mermaidIDs maps node IDs to distinct Mermaid identifiers, e.g. "api-keys"
+// to "api_keys" and "tasks:batch-get" to "tasks_batch_get"
+func mermaidIDs(nodes []Node) map[string]string {
+	ids := make(map[string]string)
+	used := make(map[string]bool)
+	for _, node := range nodes {
+		base := identifier(node.ID)
+		if base == "" || !unicode.IsLetter([]rune(base)[0]) {
+			base = "r_" + base
+		}
+		id := base
+		for n := 2; used[id]; n++ {
+			id = fmt.Sprintf("%s_%d", base, n)
+		}
+		used[id] = true
+		ids[node.ID] = id
+	}
+	return ids
+}
+
+// mermaidEntity declares an entity or class, aliased to its label when the
+// identifier differs, e.g. api_keys["api-keys"]
+func mermaidEntity(id, label string) string {
+	if label == "" || label == id {
+		return id
+	}
+	return fmt.Sprintf(`%s["%s"]`, id, mermaidText(label))
+}
+
+// mermaidAttribute converts a field name or type to a Mermaid attribute
+// token, keeping letters, digits, _, - and the extra characters given
+func mermaidAttribute(s, extra string) string {
+	token := strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_' || r == '-' || strings.ContainsRune(extra, r) {
+			return r
+		}
+		return '_'
+	}, s)
+	if token == "" || !unicode.IsLetter([]rune(token)[0]) && token[0] != '_' {
+		token = "_" + token
+	}
+	return token
+}
+
+// mermaidText keeps quotes and line breaks out of Mermaid labels
+func mermaidText(s string) string {
+	return strings.NewReplacer(`"`, "'", "\n", " ").Replace(s)
+}
+
+// writeMermaidTitle writes the diagram title as front matter
+func writeMermaidTitle(sb *strings.Builder, title string) {
+	if title != "" {
+		sb.WriteString("---\ntitle: " + strconv.Quote(title) + "\n---\n")
+	}
+}
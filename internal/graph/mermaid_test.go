@@ -0,0 +1,80 @@
+package graph
+
+import "testing"
+
+func TestExportMermaidER(t *testing.T) {
+	g, err := New(testAnalysis(), ClusterCategory)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	expected := `---
+title: "Blog API"
+---
+erDiagram
+    api_keys["api-keys"] {
+        string accountId FK
+        Scope scope
+    }
+    posts
+    users["User"] {
+        uuid id PK
+        email email "required"
+        string[] tags
+    }
+    users ||--o{ posts : "has_many (1:N, 0.96)"
+`
+	if got, err := Export(g, FormatMermaidER); err != nil || got != expected {
+		t.Errorf("Export(mermaid-er) =\n%s\nwant\n%s (error %v)", got, expected, err)
+	}
+}
+
+func TestExportMermaidClass(t *testing.T) {
+	g, err := New(testAnalysis(), ClusterNone)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	g.LimitFields(1)
+
+	expected := `---
+title: "Blog API"
+---
+classDiagram
+    class api_keys["api-keys"] {
+        +string accountId
+    }
+    class posts
+    class users["User"] {
+        +uuid id
+    }
+    users "1" *-- "*" posts : has_many (1:N, 0.96)
+`
+	if got, err := Export(g, FormatMermaidClass); err != nil || got != expected {
+		t.Errorf("Export(mermaid-class) =\n%s\nwant\n%s (error %v)", got, expected, err)
+	}
+}
+
+func TestMermaidIDs(t *testing.T) {
+	ids := mermaidIDs([]Node{{ID: "api-keys"}, {ID: "api_keys"}, {ID: "tasks:batch-get"}, {ID: "2fa"}})
+	expected := map[string]string{"api-keys": "api_keys", "api_keys": "api_keys_2", "tasks:batch-get": "tasks_batch_get", "2fa": "r_2fa"}
+	for id, want := range expected {
+		if ids[id] != want {
+			t.Errorf("mermaidIDs()[%q] = %q, want %q", id, ids[id], want)
+		}
+	}
+
+	tests := []struct {
+		input, extra, expected string
+	}{
+		{"created-at", "", "created-at"},
+		{"@type", "", "_type"},
+		{"2fa", "", "_2fa"},
+		{"string[]", "[]", "string[]"},
+		{"string[]", "", "string__"},
+	}
+	for _, tt := range tests {
+		if got := mermaidAttribute(tt.input, tt.extra); got != tt.expected {
+			t.Errorf("mermaidAttribute(%q, %q) = %q, want %q", tt.input, tt.extra, got, tt.expected)
+		}
+	}
+}
@@ -0,0 +1,473 @@
+package graph
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/orchard9/api-godoc/internal/analyzer"
+	"github.com/orchard9/api-godoc/internal/config"
+)
+
+// dependencyTypes are relationships whose source depends on their target:
+// posts belong to users, orders reference customers
+var dependencyTypes = []string{"belongs_to", "references"}
+
+// maxPaths bounds how many shortest paths a query lists
+const maxPaths = 10
+
+// Query selects the questions to answer about a graph
+type Query struct {
+	From       string // shortest paths from this resource...
+	To         string // ...to this one
+	Dependents string // resources that depend on this one, directly or not
+	Cycles     bool   // dependency cycles
+	Orphans    bool   // resources without relationships
+	Degrees    int    // the most connected resources, this many
+}
+
+// QueryResult holds the answers to a query; unasked questions are nil
+type QueryResult struct {
+	Paths      []Path      `json:"paths,omitzero"`
+	Dependents []Dependent `json:"dependents,omitzero"`
+	Cycles     []Path      `json:"cycles,omitzero"`
+	Orphans    []string    `json:"orphans,omitzero"`
+	Degrees    []Degree    `json:"degrees,omitzero"`
+}
+
+// Path is a walk through resources with the calls that follow it
+type Path struct {
+	Resources []string `json:"resources"`
+	Edges     []Edge   `json:"edges"`
+	Calls     []string `json:"calls"` // e.g. GET /orgs/{orgId}, GET /orgs/{orgId}/invoices
+}
+
+// Dependent is a resource that depends on another through a path of
+// dependency relationships
+type Dependent struct {
+	Resource string `json:"resource"`
+	Depth    int    `json:"depth"` // 1 for direct dependents
+	Path     Path   `json:"path"`
+}
+
+// Degree counts the resources a resource is connected to
+type Degree struct {
+	Resource     string `json:"resource"`
+	Dependents   int    `json:"dependents"`   // resources that depend on it
+	Dependencies int    `json:"dependencies"` // resources it depends on
+	Neighbors    int    `json:"neighbors"`    // resources related in either direction
+}
+
+// Run answers every question a query asks
+func (g *Graph) Run(q Query) (*QueryResult, error) {
+	if (q.From == "") != (q.To == "") {
+		return nil, fmt.Errorf("shortest paths need both a source and a target resource")
+	}
+	if q.From == "" && q.Dependents == "" && !q.Cycles && !q.Orphans && q.Degrees <= 0 {
+		return nil, fmt.Errorf("no query: ask for paths, dependents, cycles, orphans or degrees")
+	}
+
+	result := &QueryResult{}
+	if q.From != "" {
+		paths, err := g.ShortestPaths(q.From, q.To)
+		if err != nil {
+			return nil, err
+		}
+		result.Paths = append([]Path{}, paths...)
+	}
+	if q.Dependents != "" {
+		dependents, err := g.DependentsOf(q.Dependents)
+		if err != nil {
+			return nil, err
+		}
+		result.Dependents = append([]Dependent{}, dependents...)
+	}
+	if q.Cycles {
+		result.Cycles = append([]Path{}, g.DependencyCycles()...)
+	}
+	if q.Orphans {
+		result.Orphans = []string{}
+		for _, node := range g.Orphans() {
+			result.Orphans = append(result.Orphans, node.ID)
+		}
+	}
+	if q.Degrees > 0 {
+		degrees := g.Degrees()
+		result.Degrees = degrees[:min(q.Degrees, len(degrees))]
+	}
+	return result, nil
+}
+
+// Find looks a resource up by name or display name, in any case or number
+func (g *Graph) Find(name string) (Node, error) {
+	names := analyzer.NewNameNormalizer(config.NamingConfig{})
+	for _, node := range g.Nodes {
+		if node.ID == name {
+			return node, nil
+		}
+	}
+	for _, node := range g.Nodes {
+		if names.Key(node.ID) == names.Key(name) || names.Key(node.Label) == names.Key(name) {
+			return node, nil
+		}
+	}
+	return Node{}, fmt.Errorf("unknown resource: %s", name)
+}
+
+// ShortestPaths returns the shortest walks along relationships from one
+// resource to another, at most maxPaths of them. Each hop follows the most
+// confident of the relationships between its resources.
+func (g *Graph) ShortestPaths(from, to string) ([]Path, error) {
+	source, err := g.Find(from)
+	if err != nil {
+		return nil, err
+	}
+	target, err := g.Find(to)
+	if err != nil {
+		return nil, err
+	}
+
+	// Breadth-first search recording every predecessor at the shortest distance
+	distance := map[string]int{source.ID: 0}
+	predecessors := make(map[string][]string)
+	queue := []string{source.ID}
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+		if current == target.ID {
+			break
+		}
+		for _, next := range g.neighbors(current, nil) {
+			if d, seen := distance[next]; !seen {
+				distance[next] = distance[current] + 1
+				predecessors[next] = append(predecessors[next], current)
+				queue = append(queue, next)
+			} else if d == distance[current]+1 {
+				predecessors[next] = append(predecessors[next], current)
+			}
+		}
+	}
+	if _, found := distance[target.ID]; !found {
+		return nil, nil
+	}
+
+	var walks [][]string
+	var walk func(node string, suffix []string)
+	walk = func(node string, suffix []string) {
+		if len(walks) == maxPaths {
+			return
+		}
+		suffix = append([]string{node}, suffix...)
+		if node == source.ID {
+			walks = append(walks, suffix)
+			return
+		}
+		for _, previous := range predecessors[node] {
+			walk(previous, suffix)
+		}
+	}
+	walk(target.ID, nil)
+
+	var paths []Path
+	for _, resources := range walks {
+		paths = append(paths, g.path(resources, nil))
+	}
+	return paths, nil
+}
+
+// DependentsOf returns the resources that depend on a resource, nearest
+// first, each with the shortest chain of dependencies that leads to it
+func (g *Graph) DependentsOf(name string) ([]Dependent, error) {
+	target, err := g.Find(name)
+	if err != nil {
+		return nil, err
+	}
+
+	// Search backwards along dependency relationships; next leads toward the target
+	next := map[string]string{target.ID: ""}
+	depth := map[string]int{target.ID: 0}
+	queue := []string{target.ID}
+	var dependents []Dependent
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+		for _, edge := range g.Edges {
+			if edge.To != current || !slices.Contains(dependencyTypes, edge.Type) {
+				continue
+			}
+			if _, seen := depth[edge.From]; seen {
+				continue
+			}
+			next[edge.From] = current
+			depth[edge.From] = depth[current] + 1
+			queue = append(queue, edge.From)
+
+			resources := []string{edge.From}
+			for node := current; node != ""; node = next[node] {
+				resources = append(resources, node)
+			}
+			dependents = append(dependents, Dependent{
+				Resource: edge.From,
+				Depth:    depth[edge.From],
+				Path:     g.path(resources, dependencyTypes),
+			})
+		}
+	}
+	return dependents, nil
+}
+
+// DependencyCycles returns one shortest cycle of each group of resources
+// that depend on each other, starting from the group's first resource by name
+func (g *Graph) DependencyCycles() []Path {
+	var cycles []Path
+	for _, component := range g.dependencyComponents() {
+		start := component[0]
+		distance := map[string]int{start: 0}
+		previous := make(map[string]string)
+		queue := []string{start}
+		var closing string
+		for len(queue) > 0 && closing == "" {
+			current := queue[0]
+			queue = queue[1:]
+			for _, neighbor := range g.neighbors(current, dependencyTypes) {
+				if !slices.Contains(component, neighbor) {
+					continue
+				}
+				if neighbor == start {
+					closing = current
+					break
+				}
+				if _, seen := distance[neighbor]; !seen {
+					distance[neighbor] = distance[current] + 1
+					previous[neighbor] = current
+					queue = append(queue, neighbor)
+				}
+			}
+		}
+
+		resources := []string{start}
+		for node := closing; node != start; node = previous[node] {
+			resources = append([]string{node}, resources...)
+		}
+		resources = append([]string{start}, resources...)
+		cycles = append(cycles, g.path(resources, dependencyTypes))
+	}
+	return cycles
+}
+
+// Orphans returns the resources without any relationship
+func (g *Graph) Orphans() []Node {
+	related := make(map[string]bool)
+	for _, edge := range g.Edges {
+		related[edge.From] = true
+		related[edge.To] = true
+	}
+	var orphans []Node
+	for _, node := range g.Nodes {
+		if !related[node.ID] {
+			orphans = append(orphans, node)
+		}
+	}
+	return orphans
+}
+
+// Degrees ranks resources by how many others they are related to, then by
+// how many depend on them, then by name
+func (g *Graph) Degrees() []Degree {
+	dependents := make(map[string]map[string]bool)
+	dependencies := make(map[string]map[string]bool)
+	neighbors := make(map[string]map[string]bool)
+	add := func(sets map[string]map[string]bool, key, value string) {
+		if sets[key] == nil {
+			sets[key] = make(map[string]bool)
+		}
+		sets[key][value] = true
+	}
+	for _, edge := range g.Edges {
+		add(neighbors, edge.From, edge.To)
+		add(neighbors, edge.To, edge.From)
+		if slices.Contains(dependencyTypes, edge.Type) {
+			add(dependencies, edge.From, edge.To)
+			add(dependents, edge.To, edge.From)
+		}
+	}
+
+	var degrees []Degree
+	for _, node := range g.Nodes {
+		degrees = append(degrees, Degree{
+			Resource:     node.ID,
+			Dependents:   len(dependents[node.ID]),
+			Dependencies: len(dependencies[node.ID]),
+			Neighbors:    len(neighbors[node.ID]),
+		})
+	}
+	slices.SortStableFunc(degrees, func(a, b Degree) int {
+		return cmp.Or(cmp.Compare(b.Neighbors, a.Neighbors), cmp.Compare(b.Dependents, a.Dependents))
+	})
+	return degrees
+}
+
+// neighbors returns the resources a resource has relationships to, in
+// order, optionally only through the given relationship types
+func (g *Graph) neighbors(id string, types []string) []string {
+	var result []string
+	for _, edge := range g.Edges {
+		if edge.From == id && (types == nil || slices.Contains(types, edge.Type)) && !slices.Contains(result, edge.To) {
+			result = append(result, edge.To)
+		}
+	}
+	return result
+}
+
+// dependencyComponents returns the groups of two or more resources that
+// depend on each other, found with Tarjan's algorithm, each sorted by name
+func (g *Graph) dependencyComponents() [][]string {
+	index := make(map[string]int)
+	lowlink := make(map[string]int)
+	onStack := make(map[string]bool)
+	var stack []string
+	var components [][]string
+
+	var connect func(node string)
+	connect = func(node string) {
+		index[node] = len(index)
+		lowlink[node] = index[node]
+		stack = append(stack, node)
+		onStack[node] = true
+
+		for _, neighbor := range g.neighbors(node, dependencyTypes) {
+			if _, visited := index[neighbor]; !visited {
+				connect(neighbor)
+				lowlink[node] = min(lowlink[node], lowlink[neighbor])
+			} else if onStack[neighbor] {
+				lowlink[node] = min(lowlink[node], index[neighbor])
+			}
+		}
+
+		if lowlink[node] == index[node] {
+			var component []string
+			for {
+				member := stack[len(stack)-1]
+				stack = stack[:len(stack)-1]
+				onStack[member] = false
+				component = append(component, member)
+				if member == node {
+					break
+				}
+			}
+			if len(component) > 1 {
+				slices.Sort(component)
+				components = append(components, component)
+			}
+		}
+	}
+	for _, node := range g.Nodes {
+		if _, visited := index[node.ID]; !visited {
+			connect(node.ID)
+		}
+	}
+
+	slices.SortFunc(components, func(a, b []string) int { return cmp.Compare(a[0], b[0]) })
+	return components
+}
+
+// path builds a path through resources, following the most confident
+// relationship of the given types at each hop, with its call chain: a read
+// of the first resource, then the call that follows each relationship
+func (g *Graph) path(resources []string, types []string) Path {
+	p := Path{Resources: resources, Edges: []Edge{}, Calls: []string{}}
+	addCall := func(call string) {
+		if call != "" && (len(p.Calls) == 0 || p.Calls[len(p.Calls)-1] != call) {
+			p.Calls = append(p.Calls, call)
+		}
+	}
+	if first, err := g.Find(resources[0]); err == nil {
+		addCall(first.Entry)
+	}
+
+	for i := 0; i+1 < len(resources); i++ {
+		var best *Edge
+		for j, edge := range g.Edges {
+			if edge.From != resources[i] || edge.To != resources[i+1] || types != nil && !slices.Contains(types, edge.Type) {
+				continue
+			}
+			if best == nil || edge.Confidence > best.Confidence {
+				best = &g.Edges[j]
+			}
+		}
+		if best != nil {
+			p.Edges = append(p.Edges, *best)
+			addCall(best.Operation)
+		}
+	}
+	return p
+}
+
+// Text formats a query result for reading in a terminal
+func (r *QueryResult) Text() string {
+	var sb strings.Builder
+	section := func(title string) {
+		if sb.Len() > 0 {
+			sb.WriteString("\n")
+		}
+		sb.WriteString(title + ":\n")
+	}
+
+	if r.Paths != nil {
+		section("SHORTEST PATHS")
+		if len(r.Paths) == 0 {
+			sb.WriteString("  (no path)\n")
+		}
+		for _, p := range r.Paths {
+			writePath(&sb, p)
+		}
+	}
+	if r.Dependents != nil {
+		section("DEPENDENTS")
+		if len(r.Dependents) == 0 {
+			sb.WriteString("  (none)\n")
+		}
+		for _, d := range r.Dependents {
+			sb.WriteString(fmt.Sprintf("- %s (depth %d)\n", d.Resource, d.Depth))
+			writePath(&sb, d.Path)
+		}
+	}
+	if r.Cycles != nil {
+		section("DEPENDENCY CYCLES")
+		if len(r.Cycles) == 0 {
+			sb.WriteString("  (none)\n")
+		}
+		for _, p := range r.Cycles {
+			writePath(&sb, p)
+		}
+	}
+	if r.Orphans != nil {
+		section("ORPHAN RESOURCES")
+		if len(r.Orphans) == 0 {
+			sb.WriteString("  (none)\n")
+		}
+		for _, name := range r.Orphans {
+			sb.WriteString(fmt.Sprintf("- %s\n", name))
+		}
+	}
+	if r.Degrees != nil {
+		section("MOST CONNECTED RESOURCES")
+		for i, d := range r.Degrees {
+			sb.WriteString(fmt.Sprintf("%d. %s: %d related, %d dependents, %d dependencies\n", i+1, d.Resource, d.Neighbors, d.Dependents, d.Dependencies))
+		}
+	}
+	return sb.String()
+}
+
+// writePath writes a path as "  users -[has_many]-> posts" followed by its calls
+func writePath(sb *strings.Builder, p Path) {
+	sb.WriteString("  " + p.Resources[0])
+	for i, edge := range p.Edges {
+		sb.WriteString(fmt.Sprintf(" -[%s]-> %s", edge.Type, p.Resources[i+1]))
+	}
+	sb.WriteString("\n")
+	for i, call := range p.Calls {
+		sb.WriteString(fmt.Sprintf("    %d. %s\n", i+1, call))
+	}
+}
@@ -0,0 +1,198 @@
+package graph
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/orchard9/api-godoc/pkg/models"
+)
+
+// queryGraph returns organizations with nested projects and invoices,
+// projects referring to users, teams and leads referring to each other and
+// a health resource without relationships
+func queryGraph(t *testing.T) *Graph {
+	get := func(path, kind string) models.Operation {
+		return models.Operation{Method: "GET", Path: path, Kind: kind}
+	}
+	rel := func(resource, relType, via, cardinality string, confidence float64) models.Relationship {
+		return models.Relationship{Resource: resource, Type: relType, Via: via, Cardinality: cardinality, Confidence: confidence}
+	}
+	analysis := &models.APIAnalysis{Resources: []models.Resource{
+		{
+			Name: "organizations", DisplayName: "Organization",
+			Operations: []models.Operation{get("/orgs", models.KindList), get("/orgs/{orgId}", models.KindGet)},
+			Relationships: []models.Relationship{
+				rel("projects", "has_many", "path hierarchy", models.CardinalityOneToMany, 0.96),
+			},
+		},
+		{
+			Name:       "projects",
+			Operations: []models.Operation{get("/orgs/{orgId}/projects", models.KindList), get("/orgs/{orgId}/projects/{projectId}", models.KindGet)},
+			Relationships: []models.Relationship{
+				rel("organizations", "belongs_to", "path hierarchy", models.CardinalityManyToOne, 0.96),
+				rel("invoices", "has_many", "path hierarchy", models.CardinalityOneToMany, 0.96),
+				rel("users", "references", "ownerId", models.CardinalityManyToOne, 0.5),
+			},
+		},
+		{
+			Name:       "invoices",
+			Operations: []models.Operation{get("/orgs/{orgId}/projects/{projectId}/invoices", models.KindList)},
+			Relationships: []models.Relationship{
+				rel("projects", "belongs_to", "path hierarchy", models.CardinalityManyToOne, 0.96),
+			},
+		},
+		{
+			Name:       "users",
+			Operations: []models.Operation{get("/users/{userId}", models.KindGet)},
+			Relationships: []models.Relationship{
+				rel("projects", "referenced_by", "ownerId", models.CardinalityOneToMany, 0.5),
+			},
+		},
+		{
+			Name:          "teams",
+			Operations:    []models.Operation{get("/teams/{teamId}", models.KindGet)},
+			Relationships: []models.Relationship{rel("leads", "references", "leadId", models.CardinalityManyToOne, 0.5)},
+		},
+		{
+			Name:          "leads",
+			Operations:    []models.Operation{get("/leads/{leadId}", models.KindGet)},
+			Relationships: []models.Relationship{rel("teams", "references", "teamId", models.CardinalityManyToOne, 0.5)},
+		},
+		{
+			Name:       "health",
+			Operations: []models.Operation{get("/health", "")},
+		},
+	}}
+
+	g, err := New(analysis, ClusterNone)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	return g
+}
+
+func TestShortestPaths(t *testing.T) {
+	g := queryGraph(t)
+
+	paths, err := g.ShortestPaths("Organization", "invoice")
+	if err != nil {
+		t.Fatalf("ShortestPaths() error = %v", err)
+	}
+	if len(paths) != 1 {
+		t.Fatalf("Expected one shortest path, got %+v", paths)
+	}
+	if expected := []string{"organizations", "projects", "invoices"}; !reflect.DeepEqual(paths[0].Resources, expected) {
+		t.Errorf("Expected resources %v, got %v", expected, paths[0].Resources)
+	}
+	expectedCalls := []string{"GET /orgs/{orgId}", "GET /orgs/{orgId}/projects", "GET /orgs/{orgId}/projects/{projectId}/invoices"}
+	if !reflect.DeepEqual(paths[0].Calls, expectedCalls) {
+		t.Errorf("Expected calls %v, got %v", expectedCalls, paths[0].Calls)
+	}
+
+	if paths, err := g.ShortestPaths("users", "teams"); err != nil || paths != nil {
+		t.Errorf("Expected no path between unrelated resources, got %+v, %v", paths, err)
+	}
+	if _, err := g.ShortestPaths("users", "payments"); err == nil {
+		t.Error("Expected an error for an unknown resource")
+	}
+}
+
+func TestDependentsOf(t *testing.T) {
+	g := queryGraph(t)
+
+	dependents, err := g.DependentsOf("organizations")
+	if err != nil {
+		t.Fatalf("DependentsOf() error = %v", err)
+	}
+	var names []string
+	for _, d := range dependents {
+		names = append(names, d.Resource)
+	}
+	if !reflect.DeepEqual(names, []string{"projects", "invoices"}) || dependents[1].Depth != 2 {
+		t.Fatalf("Expected projects then invoices at depth 2, got %+v", dependents)
+	}
+	if expected := []string{"invoices", "projects", "organizations"}; !reflect.DeepEqual(dependents[1].Path.Resources, expected) {
+		t.Errorf("Expected the chain %v, got %v", expected, dependents[1].Path.Resources)
+	}
+
+	// has_many and referenced_by are not dependencies
+	if dependents, _ := g.DependentsOf("invoices"); len(dependents) != 0 {
+		t.Errorf("Expected nothing to depend on invoices, got %+v", dependents)
+	}
+}
+
+func TestDependencyCycles(t *testing.T) {
+	cycles := queryGraph(t).DependencyCycles()
+	if len(cycles) != 1 {
+		t.Fatalf("Expected one cycle, got %+v", cycles)
+	}
+	if expected := []string{"leads", "teams", "leads"}; !reflect.DeepEqual(cycles[0].Resources, expected) {
+		t.Errorf("Expected cycle %v, got %v", expected, cycles[0].Resources)
+	}
+	if expected := []string{"GET /leads/{leadId}", "GET /teams/{teamId}", "GET /leads/{leadId}"}; !reflect.DeepEqual(cycles[0].Calls, expected) {
+		t.Errorf("Expected calls %v, got %v", expected, cycles[0].Calls)
+	}
+}
+
+func TestQueryRun(t *testing.T) {
+	g := queryGraph(t)
+
+	result, err := g.Run(Query{Orphans: true, Degrees: 2})
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	if !reflect.DeepEqual(result.Orphans, []string{"health"}) {
+		t.Errorf("Expected health to be the only orphan, got %v", result.Orphans)
+	}
+	expected := []Degree{
+		{Resource: "projects", Dependents: 1, Dependencies: 2, Neighbors: 3},
+		{Resource: "leads", Dependents: 1, Dependencies: 1, Neighbors: 1},
+	}
+	if !reflect.DeepEqual(result.Degrees, expected) {
+		t.Errorf("Expected degrees %+v, got %+v", expected, result.Degrees)
+	}
+
+	// Unasked questions stay out of the JSON, unanswered ones do not
+	result, err = g.Run(Query{Dependents: "invoices", Cycles: true})
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	data, err := json.Marshal(result)
+	if err != nil {
+		t.Fatalf("Marshal() error = %v", err)
+	}
+	if got := string(data); !strings.HasPrefix(got, `{"dependents":[],"cycles":[{`) {
+		t.Errorf("Unexpected JSON %s", got)
+	}
+
+	for _, q := range []Query{{}, {From: "users"}, {Dependents: "payments"}} {
+		if _, err := g.Run(q); err == nil {
+			t.Errorf("Expected an error for %+v", q)
+		}
+	}
+}
+
+func TestQueryResultText(t *testing.T) {
+	result, err := queryGraph(t).Run(Query{From: "organizations", To: "invoices", Orphans: true, Degrees: 1})
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+
+	expected := `SHORTEST PATHS:
+  organizations -[has_many]-> projects -[has_many]-> invoices
+    1. GET /orgs/{orgId}
+    2. GET /orgs/{orgId}/projects
+    3. GET /orgs/{orgId}/projects/{projectId}/invoices
+
+ORPHAN RESOURCES:
+- health
+
+MOST CONNECTED RESOURCES:
+1. projects: 3 related, 1 dependents, 2 dependencies
+`
+	if got := result.Text(); got != expected {
+		t.Errorf("Text() =\n%s\nwant\n%s", got, expected)
+	}
+}
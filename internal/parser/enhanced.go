@@ -32,9 +32,13 @@ func (p *enhancedParser) ParseFile(path string) (*OpenAPISpec, error) {
 		return p.fallbackParser().ParseFile(path)
 	}
 
-	// Validate that it's a supported OpenAPI version
+	// go-openapi only models Swagger 2.0; OpenAPI 3.x documents would load
+	// without their components, so the basic parser handles them
 	spec := doc.Spec()
-	if spec.Swagger != "" && spec.Swagger != "2.0" {
+	if spec.Swagger == "" {
+		return p.fallbackParser().ParseFile(path)
+	}
+	if spec.Swagger != "2.0" {
 		return nil, fmt.Errorf("unsupported Swagger version: %s", spec.Swagger)
 	}
 
@@ -50,6 +54,9 @@ func (p *enhancedParser) ParseURL(url string) (*OpenAPISpec, error) {
 		// Fall back to manual parsing with conversion
 		return p.fallbackParser().ParseURL(url)
 	}
+	if doc.Spec().Swagger == "" {
+		return p.fallbackParser().ParseURL(url)
+	}
 
 	return p.convertFromGoOpenAPI(doc.Spec()), nil
 }
@@ -73,9 +80,13 @@ func (p *enhancedParser) Parse(data []byte) (*OpenAPISpec, error) {
 		return p.fallbackParser().Parse(data)
 	}
 
-	// Validate that it's a supported OpenAPI version
+	// go-openapi only models Swagger 2.0; OpenAPI 3.x documents would load
+	// without their components, so the basic parser handles them
 	spec := doc.Spec()
-	if spec.Swagger != "" && spec.Swagger != "2.0" {
+	if spec.Swagger == "" {
+		return p.fallbackParser().Parse(data)
+	}
+	if spec.Swagger != "2.0" {
 		return nil, fmt.Errorf("unsupported Swagger version: %s", spec.Swagger)
 	}
 
@@ -136,15 +147,18 @@ func (p *enhancedParser) convertFromGoOpenAPI(s *spec.Swagger) *OpenAPISpec {
 	// Convert paths
 	if s.Paths != nil {
 		for path, pathItem := range s.Paths.Paths {
-			result.Paths[path] = p.convertPathItem(pathItem)
+			result.Paths[path] = p.convertPathItem(pathItem, s)
 		}
 	}
 
 	// Convert components/definitions
-	if s.Definitions != nil || s.Parameters != nil || s.Responses != nil {
+	if s.Definitions != nil || s.Parameters != nil || s.Responses != nil || s.SecurityDefinitions != nil {
 		result.Components = p.convertComponents(s)
 	}
 
+	// Convert global security requirements
+	result.Security = p.convertSecurity(s.Security)
+
 	// Convert tags
 	if len(s.Tags) > 0 {
 		result.Tags = make([]Tag, len(s.Tags))
@@ -197,38 +211,45 @@ func (p *enhancedParser) convertServers(s *spec.Swagger) []Server {
 }
 
 // convertPathItem converts spec.PathItem to our PathItem
-func (p *enhancedParser) convertPathItem(pathItem spec.PathItem) PathItem {
+func (p *enhancedParser) convertPathItem(pathItem spec.PathItem, s *spec.Swagger) PathItem {
 	result := PathItem{
 		// Note: PathItem in go-openapi doesn't have Summary/Description at path level
 	}
 
+	// Path-level parameters apply to every operation; body parameters cannot appear here in practice
+	for _, param := range pathItem.Parameters {
+		if param.In != "body" {
+			result.Parameters = append(result.Parameters, p.convertParameter(param, s))
+		}
+	}
+
 	if pathItem.Get != nil {
-		result.Get = p.convertOperation(pathItem.Get)
+		result.Get = p.convertOperation(pathItem.Get, s)
 	}
 	if pathItem.Post != nil {
-		result.Post = p.convertOperation(pathItem.Post)
+		result.Post = p.convertOperation(pathItem.Post, s)
 	}
 	if pathItem.Put != nil {
-		result.Put = p.convertOperation(pathItem.Put)
+		result.Put = p.convertOperation(pathItem.Put, s)
 	}
 	if pathItem.Delete != nil {
-		result.Delete = p.convertOperation(pathItem.Delete)
+		result.Delete = p.convertOperation(pathItem.Delete, s)
 	}
 	if pathItem.Options != nil {
-		result.Options = p.convertOperation(pathItem.Options)
+		result.Options = p.convertOperation(pathItem.Options, s)
 	}
 	if pathItem.Head != nil {
-		result.Head = p.convertOperation(pathItem.Head)
+		result.Head = p.convertOperation(pathItem.Head, s)
 	}
 	if pathItem.Patch != nil {
-		result.Patch = p.convertOperation(pathItem.Patch)
+		result.Patch = p.convertOperation(pathItem.Patch, s)
 	}
 
 	return result
 }
 
 // convertOperation converts spec.Operation to our Operation
-func (p *enhancedParser) convertOperation(op *spec.Operation) *Operation {
+func (p *enhancedParser) convertOperation(op *spec.Operation, s *spec.Swagger) *Operation {
 	result := &Operation{
 		Tags:        op.Tags,
 		Summary:     op.Summary,
@@ -236,15 +257,62 @@ func (p *enhancedParser) convertOperation(op *spec.Operation) *Operation {
 		OperationID: op.ID,
 		Deprecated:  op.Deprecated,
 		Responses:   make(map[string]Response),
+		Security:    p.convertSecurity(op.Security),
+	}
+
+	consumes := op.Consumes
+	if len(consumes) == 0 {
+		consumes = s.Consumes
+	}
+	produces := op.Produces
+	if len(produces) == 0 {
+		produces = s.Produces
+	}
+
+	// Convert parameters; body and form parameters become the request body
+	var formParams []spec.Parameter
+	for _, param := range op.Parameters {
+		switch {
+		case param.In == "body":
+			result.RequestBody = p.convertBodyParameter(param, consumes)
+		case param.In == "formData":
+			formParams = append(formParams, param)
+		case param.Ref.String() != "" && p.isBodyParameterRef(param.Ref.String(), s):
+			result.RequestBody = &RequestBody{
+				Ref: strings.Replace(param.Ref.String(), "#/parameters/", "#/components/requestBodies/", 1),
+			}
+		default:
+			result.Parameters = append(result.Parameters, p.convertParameter(param, s))
+		}
+	}
+	if len(formParams) > 0 && result.RequestBody == nil {
+		result.RequestBody = p.convertFormParameters(formParams, consumes)
+	}
+
+	// Convert vendor extensions with pattern meaning
+	for name, value := range op.Extensions {
+		if result.Extensions == nil {
+			result.Extensions = make(map[string]interface{})
+		}
+		result.Extensions[strings.ToLower(name)] = value
+	}
+	if lro, ok := op.Extensions.GetBool("x-ms-long-running-operation"); ok {
+		result.LongRunning = lro
+	}
+	if pageable, ok := op.Extensions["x-ms-pageable"].(map[string]interface{}); ok {
+		result.Pageable = &Pageable{}
+		result.Pageable.NextLinkName, _ = pageable["nextLinkName"].(string)
+		result.Pageable.ItemName, _ = pageable["itemName"].(string)
+		result.Pageable.OperationName, _ = pageable["operationName"].(string)
 	}
 
 	// Convert responses
 	if op.Responses != nil {
 		for code, response := range op.Responses.StatusCodeResponses {
-			result.Responses[fmt.Sprintf("%d", code)] = p.convertResponse(&response)
+			result.Responses[fmt.Sprintf("%d", code)] = p.convertResponse(&response, produces)
 		}
 		if op.Responses.Default != nil {
-			result.Responses["default"] = p.convertResponse(op.Responses.Default)
+			result.Responses["default"] = p.convertResponse(op.Responses.Default, produces)
 		}
 	}
 
@@ -252,23 +320,202 @@ func (p *enhancedParser) convertOperation(op *spec.Operation) *Operation {
 }
 
 // convertResponse converts spec.Response to our Response
-func (p *enhancedParser) convertResponse(resp *spec.Response) Response {
+func (p *enhancedParser) convertResponse(resp *spec.Response, produces []string) Response {
+	if ref := resp.Ref.String(); ref != "" {
+		return Response{Ref: strings.Replace(ref, "#/responses/", "#/components/responses/", 1)}
+	}
+
 	result := Response{
 		Description: resp.Description,
 	}
 
 	// Convert schema if present
 	if resp.Schema != nil {
-		result.Content = Content{
-			"application/json": MediaType{
-				Schema: p.convertSchema(resp.Schema),
-			},
+		result.Content = p.buildContent(p.convertSchema(resp.Schema), produces)
+	}
+
+	// Convert headers
+	if len(resp.Headers) > 0 {
+		result.Headers = make(map[string]Header)
+		for name, header := range resp.Headers {
+			result.Headers[name] = Header{
+				Description: header.Description,
+				Schema:      &Schema{Type: header.Type, Format: header.Format},
+			}
+		}
+	}
+
+	return result
+}
+
+// buildContent creates a content map with the same schema for each media type,
+// defaulting to application/json
+func (p *enhancedParser) buildContent(schema *Schema, mediaTypes []string) Content {
+	if len(mediaTypes) == 0 {
+		mediaTypes = []string{"application/json"}
+	}
+
+	content := make(Content, len(mediaTypes))
+	for _, mediaType := range mediaTypes {
+		content[mediaType] = MediaType{Schema: schema}
+	}
+	return content
+}
+
+// convertParameter converts a non-body spec.Parameter to our Parameter
+func (p *enhancedParser) convertParameter(param spec.Parameter, s *spec.Swagger) Parameter {
+	if ref := param.Ref.String(); ref != "" {
+		return Parameter{Ref: strings.Replace(ref, "#/parameters/", "#/components/parameters/", 1)}
+	}
+
+	result := Parameter{
+		Name:            param.Name,
+		In:              param.In,
+		Description:     param.Description,
+		Required:        param.Required,
+		AllowEmptyValue: param.AllowEmptyValue,
+		Example:         param.Example,
+		Schema:          p.convertSimpleSchema(param.SimpleSchema, param.CommonValidations),
+	}
+
+	if nullable, ok := param.Extensions.GetBool("x-nullable"); ok {
+		result.Schema.Nullable = nullable
+	}
+	if example, ok := param.Extensions["x-example"]; ok && result.Example == nil {
+		result.Example = example
+	}
+
+	return result
+}
+
+// convertSimpleSchema converts the inline type information of a 2.0 parameter or items object
+func (p *enhancedParser) convertSimpleSchema(simple spec.SimpleSchema, validations spec.CommonValidations) *Schema {
+	result := &Schema{
+		Type:      simple.Type,
+		Format:    simple.Format,
+		Default:   simple.Default,
+		Example:   simple.Example,
+		Nullable:  simple.Nullable,
+		Enum:      validations.Enum,
+		Pattern:   validations.Pattern,
+		Minimum:   validations.Minimum,
+		Maximum:   validations.Maximum,
+		MinLength: int64Ptr(validations.MinLength),
+		MaxLength: int64Ptr(validations.MaxLength),
+	}
+
+	if simple.Items != nil {
+		result.Items = p.convertSimpleSchema(simple.Items.SimpleSchema, simple.Items.CommonValidations)
+		if ref := simple.Items.Ref.String(); ref != "" {
+			result.Items = &Schema{Ref: strings.Replace(ref, "#/definitions/", "#/components/schemas/", 1)}
 		}
 	}
 
 	return result
 }
 
+// convertBodyParameter converts a 2.0 body parameter to a request body
+func (p *enhancedParser) convertBodyParameter(param spec.Parameter, consumes []string) *RequestBody {
+	return &RequestBody{
+		Description: param.Description,
+		Required:    param.Required,
+		Content:     p.buildContent(p.convertSchema(param.Schema), consumes),
+	}
+}
+
+// convertFormParameters folds 2.0 formData parameters into an object request body
+func (p *enhancedParser) convertFormParameters(params []spec.Parameter, consumes []string) *RequestBody {
+	schema := &Schema{
+		Type:       "object",
+		Properties: make(map[string]Schema),
+	}
+
+	for _, param := range params {
+		prop := p.convertSimpleSchema(param.SimpleSchema, param.CommonValidations)
+		if param.Type == "file" {
+			prop = &Schema{Type: "string", Format: "binary"}
+		}
+		prop.Description = param.Description
+		schema.Properties[param.Name] = *prop
+		if param.Required {
+			schema.Required = append(schema.Required, param.Name)
+		}
+	}
+
+	if len(consumes) == 0 {
+		consumes = []string{"application/x-www-form-urlencoded"}
+	}
+
+	return &RequestBody{
+		Required: len(schema.Required) > 0,
+		Content:  p.buildContent(schema, consumes),
+	}
+}
+
+// isBodyParameterRef reports whether a #/parameters/ reference targets a body parameter
+func (p *enhancedParser) isBodyParameterRef(ref string, s *spec.Swagger) bool {
+	name := strings.TrimPrefix(ref, "#/parameters/")
+	param, ok := s.Parameters[name]
+	return ok && param.In == "body"
+}
+
+// convertSecurity converts security requirements
+func (p *enhancedParser) convertSecurity(requirements []map[string][]string) []SecurityRequirement {
+	if requirements == nil {
+		return nil
+	}
+
+	result := make([]SecurityRequirement, 0, len(requirements))
+	for _, req := range requirements {
+		result = append(result, SecurityRequirement(req))
+	}
+	return result
+}
+
+// convertSecurityScheme converts a 2.0 security definition to a 3.x security scheme
+func (p *enhancedParser) convertSecurityScheme(def *spec.SecurityScheme) SecurityScheme {
+	result := SecurityScheme{
+		Type:        def.Type,
+		Description: def.Description,
+		Name:        def.Name,
+		In:          def.In,
+	}
+
+	switch def.Type {
+	case "basic":
+		result.Type = "http"
+		result.Scheme = "basic"
+	case "oauth2":
+		flow := &OAuthFlow{
+			AuthorizationUrl: def.AuthorizationURL,
+			TokenUrl:         def.TokenURL,
+			Scopes:           def.Scopes,
+		}
+		result.Flows = &OAuthFlows{}
+		switch def.Flow {
+		case "implicit":
+			result.Flows.Implicit = flow
+		case "password":
+			result.Flows.Password = flow
+		case "application":
+			result.Flows.ClientCredentials = flow
+		case "accessCode":
+			result.Flows.AuthorizationCode = flow
+		}
+	}
+
+	return result
+}
+
+// int64Ptr converts an optional int64 to an optional int
+func int64Ptr(v *int64) *int {
+	if v == nil {
+		return nil
+	}
+	i := int(*v)
+	return &i
+}
+
 // convertSchema converts spec.Schema to our Schema
 func (p *enhancedParser) convertSchema(s *spec.Schema) *Schema {
 	if s == nil {
@@ -284,6 +531,19 @@ func (p *enhancedParser) convertSchema(s *spec.Schema) *Schema {
 		ReadOnly:    s.ReadOnly,
 		Required:    s.Required,
 		Enum:        s.Enum,
+		Pattern:     s.Pattern,
+		Minimum:     s.Minimum,
+		Maximum:     s.Maximum,
+		MinLength:   int64Ptr(s.MinLength),
+		MaxLength:   int64Ptr(s.MaxLength),
+	}
+
+	// Map vendor extensions to native keywords
+	if nullable, ok := s.Extensions.GetBool("x-nullable"); ok {
+		result.Nullable = nullable
+	}
+	if example, ok := s.Extensions["x-example"]; ok && result.Example == nil {
+		result.Example = example
 	}
 
 	// Handle type (spec.Schema.Type is []string)
@@ -313,6 +573,11 @@ func (p *enhancedParser) convertSchema(s *spec.Schema) *Schema {
 		result.Items = p.convertSchema(s.Items.Schema)
 	}
 
+	// Handle composition
+	for _, sub := range s.AllOf {
+		result.AllOf = append(result.AllOf, *p.convertSchema(&sub))
+	}
+
 	return result
 }
 
@@ -329,5 +594,36 @@ func (p *enhancedParser) convertComponents(s *spec.Swagger) *Components {
 		}
 	}
 
+	// Convert shared parameters; body parameters become request bodies
+	for name, param := range s.Parameters {
+		if param.In == "body" {
+			if result.RequestBodies == nil {
+				result.RequestBodies = make(map[string]RequestBody)
+			}
+			result.RequestBodies[name] = *p.convertBodyParameter(param, s.Consumes)
+			continue
+		}
+		if result.Parameters == nil {
+			result.Parameters = make(map[string]Parameter)
+		}
+		result.Parameters[name] = p.convertParameter(param, s)
+	}
+
+	// Convert shared responses
+	for name, response := range s.Responses {
+		if result.Responses == nil {
+			result.Responses = make(map[string]Response)
+		}
+		result.Responses[name] = p.convertResponse(&response, s.Produces)
+	}
+
+	// Convert security definitions
+	for name, def := range s.SecurityDefinitions {
+		if result.SecuritySchemes == nil {
+			result.SecuritySchemes = make(map[string]SecurityScheme)
+		}
+		result.SecuritySchemes[name] = p.convertSecurityScheme(def)
+	}
+
 	return result
 }
@@ -0,0 +1,60 @@
+package parser
+
+import (
+	"encoding/json"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// operationFields has the fields of Operation without its custom decoders
+type operationFields Operation
+
+// UnmarshalJSON decodes an operation and collects its vendor extensions
+func (o *Operation) UnmarshalJSON(data []byte) error {
+	if err := json.Unmarshal(data, (*operationFields)(o)); err != nil {
+		return err
+	}
+
+	var raw map[string]interface{}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	o.Extensions = collectExtensions(raw)
+	return nil
+}
+
+// UnmarshalYAML decodes an operation and collects its vendor extensions
+func (o *Operation) UnmarshalYAML(node *yaml.Node) error {
+	if err := node.Decode((*operationFields)(o)); err != nil {
+		return err
+	}
+
+	var raw map[string]interface{}
+	if err := node.Decode(&raw); err != nil {
+		return err
+	}
+	o.Extensions = collectExtensions(raw)
+	return nil
+}
+
+// Extension returns a vendor extension by case-insensitive name
+func (o *Operation) Extension(name string) (interface{}, bool) {
+	value, ok := o.Extensions[strings.ToLower(name)]
+	return value, ok
+}
+
+// collectExtensions returns the x- keys of a decoded object, lowercased
+func collectExtensions(raw map[string]interface{}) map[string]interface{} {
+	var extensions map[string]interface{}
+	for key, value := range raw {
+		if !strings.HasPrefix(strings.ToLower(key), "x-") {
+			continue
+		}
+		if extensions == nil {
+			extensions = make(map[string]interface{})
+		}
+		extensions[strings.ToLower(key)] = value
+	}
+	return extensions
+}
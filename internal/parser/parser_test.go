@@ -117,6 +117,37 @@ paths: {}`,
 	}
 }
 
+func TestParseOpenAPI3Components(t *testing.T) {
+	data := []byte(`{
+		"openapi": "3.0.3",
+		"info": {"title": "Test API", "version": "1.0.0"},
+		"paths": {},
+		"components": {
+			"schemas": {
+				"Membership": {"type": "object", "properties": {"userId": {"type": "string"}}}
+			}
+		}
+	}`)
+	path := filepath.Join(t.TempDir(), "openapi.json")
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	fromBytes, err := New().Parse(data)
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	fromFile, err := New().ParseFile(path)
+	if err != nil {
+		t.Fatalf("ParseFile() error = %v", err)
+	}
+	for _, spec := range []*OpenAPISpec{fromBytes, fromFile} {
+		if spec.Components == nil || spec.Components.Schemas["Membership"].Properties["userId"].Type != "string" {
+			t.Errorf("Expected components to be preserved, got %+v", spec.Components)
+		}
+	}
+}
+
 func TestParseURL(t *testing.T) {
 	// Note: Actual HTTP testing would require a mock server
 	t.Run("URL parsing placeholder", func(t *testing.T) {
@@ -208,3 +239,135 @@ func TestParseRealWorldSpecs(t *testing.T) {
 		})
 	}
 }
+
+func TestParseVendorExtensions(t *testing.T) {
+	swagger := `{
+		"swagger": "2.0",
+		"info": {"title": "Test API", "version": "1.0.0"},
+		"paths": {
+			"/vaults": {
+				"get": {
+					"x-ms-pageable": {"nextLinkName": "nextLink"},
+					"x-resource": "vault",
+					"responses": {"200": {"description": "OK", "schema": {"$ref": "#/definitions/Vault"}}}
+				},
+				"put": {
+					"x-ms-long-running-operation": true,
+					"responses": {"202": {"description": "Accepted"}}
+				}
+			}
+		},
+		"definitions": {
+			"Vault": {
+				"type": "object",
+				"properties": {"nickname": {"type": "string", "x-nullable": true}}
+			}
+		}
+	}`
+
+	for name, p := range map[string]Parser{"enhanced": New(), "basic": NewBasic()} {
+		t.Run(name, func(t *testing.T) {
+			spec, err := p.Parse([]byte(swagger))
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+
+			vaults := spec.Paths["/vaults"]
+			if vaults.Get == nil || vaults.Get.Pageable == nil || vaults.Get.Pageable.NextLinkName != "nextLink" {
+				t.Errorf("Expected x-ms-pageable to be parsed, got %+v", vaults.Get)
+			}
+			if resource, _ := vaults.Get.Extension("X-Resource"); resource != "vault" {
+				t.Errorf("Expected x-resource extension to be kept, got %v", vaults.Get.Extensions)
+			}
+			if vaults.Put == nil || !vaults.Put.LongRunning {
+				t.Errorf("Expected x-ms-long-running-operation to be parsed, got %+v", vaults.Put)
+			}
+			if !spec.Components.Schemas["Vault"].Properties["nickname"].Nullable {
+				t.Error("Expected x-nullable to map to nullable")
+			}
+		})
+	}
+}
+
+func TestParseOperationDetails(t *testing.T) {
+	swagger := `{
+		"swagger": "2.0",
+		"info": {"title": "Test API", "version": "1.0.0"},
+		"consumes": ["application/json"],
+		"produces": ["application/json"],
+		"security": [{"apiKey": []}],
+		"securityDefinitions": {"apiKey": {"type": "apiKey", "name": "X-API-Key", "in": "header"}},
+		"paths": {
+			"/users/{id}": {
+				"parameters": [{"name": "id", "in": "path", "required": true, "type": "string"}],
+				"put": {
+					"parameters": [
+						{"name": "dryRun", "in": "query", "type": "boolean"},
+						{"name": "body", "in": "body", "required": true, "schema": {"$ref": "#/definitions/User"}}
+					],
+					"responses": {"200": {"description": "OK", "schema": {"$ref": "#/definitions/User"}}}
+				}
+			}
+		},
+		"definitions": {
+			"User": {"type": "object", "properties": {"id": {"type": "string"}}}
+		}
+	}`
+
+	for name, p := range map[string]Parser{"enhanced": New(), "basic": NewBasic()} {
+		t.Run(name, func(t *testing.T) {
+			spec, err := p.Parse([]byte(swagger))
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+
+			item := spec.Paths["/users/{id}"]
+			if len(item.Parameters) != 1 || item.Parameters[0].Name != "id" {
+				t.Errorf("Expected path-level id parameter, got %+v", item.Parameters)
+			}
+			put := item.Put
+			if put == nil {
+				t.Fatal("Expected PUT operation")
+			}
+			if len(put.Parameters) != 1 || put.Parameters[0].Name != "dryRun" || put.Parameters[0].Schema == nil {
+				t.Errorf("Expected dryRun query parameter with schema, got %+v", put.Parameters)
+			}
+			if put.RequestBody == nil || !put.RequestBody.Required {
+				t.Fatalf("Expected required request body, got %+v", put.RequestBody)
+			}
+			if schema := put.RequestBody.Content["application/json"].Schema; schema == nil || schema.Ref != "#/components/schemas/User" {
+				t.Errorf("Expected body schema ref to User, got %+v", schema)
+			}
+			if schema := put.Responses["200"].Content["application/json"].Schema; schema == nil || schema.Ref != "#/components/schemas/User" {
+				t.Errorf("Expected response schema ref to User, got %+v", put.Responses["200"])
+			}
+			if len(spec.Security) != 1 || spec.Components.SecuritySchemes["apiKey"].Type != "apiKey" {
+				t.Errorf("Expected global security and apiKey scheme, got %+v", spec.Security)
+			}
+		})
+	}
+}
+
+func TestParseOperationExtensionsYAML(t *testing.T) {
+	spec := `
+openapi: 3.0.0
+info:
+  title: Test API
+  version: 1.0.0
+paths:
+  /vaults:
+    get:
+      x-resource: vault
+      responses:
+        "200":
+          description: OK
+`
+
+	parsed, err := NewBasic().Parse([]byte(spec))
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	if resource, _ := parsed.Paths["/vaults"].Get.Extension("x-resource"); resource != "vault" {
+		t.Errorf("Expected x-resource extension, got %v", parsed.Paths["/vaults"].Get.Extensions)
+	}
+}
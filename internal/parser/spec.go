@@ -67,22 +67,39 @@ type Operation struct {
 	Deprecated   bool                  `json:"deprecated,omitempty" yaml:"deprecated,omitempty"`
 	Security     []SecurityRequirement `json:"security,omitempty" yaml:"security,omitempty"`
 	Servers      []Server              `json:"servers,omitempty" yaml:"servers,omitempty"`
+
+	// Vendor extensions with pattern meaning
+	Pageable    *Pageable `json:"x-ms-pageable,omitempty" yaml:"x-ms-pageable,omitempty"`
+	LongRunning bool      `json:"x-ms-long-running-operation,omitempty" yaml:"x-ms-long-running-operation,omitempty"`
+
+	// Extensions holds all vendor extensions keyed by lowercase name
+	Extensions map[string]interface{} `json:"-" yaml:"-"`
+}
+
+// Pageable describes an x-ms-pageable list operation
+type Pageable struct {
+	NextLinkName  string `json:"nextLinkName,omitempty" yaml:"nextLinkName,omitempty"`
+	ItemName      string `json:"itemName,omitempty" yaml:"itemName,omitempty"`
+	OperationName string `json:"operationName,omitempty" yaml:"operationName,omitempty"`
 }
 
 // Parameter represents an operation parameter
 type Parameter struct {
-	Name            string  `json:"name" yaml:"name"`
-	In              string  `json:"in" yaml:"in"`
-	Description     string  `json:"description,omitempty" yaml:"description,omitempty"`
-	Required        bool    `json:"required,omitempty" yaml:"required,omitempty"`
-	Deprecated      bool    `json:"deprecated,omitempty" yaml:"deprecated,omitempty"`
-	AllowEmptyValue bool    `json:"allowEmptyValue,omitempty" yaml:"allowEmptyValue,omitempty"`
-	Schema          *Schema `json:"schema,omitempty" yaml:"schema,omitempty"`
-	Content         Content `json:"content,omitempty" yaml:"content,omitempty"`
+	Ref             string      `json:"$ref,omitempty" yaml:"$ref,omitempty"`
+	Name            string      `json:"name" yaml:"name"`
+	In              string      `json:"in" yaml:"in"`
+	Description     string      `json:"description,omitempty" yaml:"description,omitempty"`
+	Required        bool        `json:"required,omitempty" yaml:"required,omitempty"`
+	Deprecated      bool        `json:"deprecated,omitempty" yaml:"deprecated,omitempty"`
+	AllowEmptyValue bool        `json:"allowEmptyValue,omitempty" yaml:"allowEmptyValue,omitempty"`
+	Schema          *Schema     `json:"schema,omitempty" yaml:"schema,omitempty"`
+	Content         Content     `json:"content,omitempty" yaml:"content,omitempty"`
+	Example         interface{} `json:"example,omitempty" yaml:"example,omitempty"`
 }
 
 // RequestBody represents a request body
 type RequestBody struct {
+	Ref         string  `json:"$ref,omitempty" yaml:"$ref,omitempty"`
 	Description string  `json:"description,omitempty" yaml:"description,omitempty"`
 	Content     Content `json:"content" yaml:"content"`
 	Required    bool    `json:"required,omitempty" yaml:"required,omitempty"`
@@ -90,6 +107,7 @@ type RequestBody struct {
 
 // Response represents an API response
 type Response struct {
+	Ref         string            `json:"$ref,omitempty" yaml:"$ref,omitempty"`
 	Description string            `json:"description" yaml:"description"`
 	Headers     map[string]Header `json:"headers,omitempty" yaml:"headers,omitempty"`
 	Content     Content           `json:"content,omitempty" yaml:"content,omitempty"`
@@ -221,6 +239,7 @@ type OAuthFlow struct {
 }
 
 type Link struct {
+	Ref          string                 `json:"$ref,omitempty" yaml:"$ref,omitempty"`
 	OperationRef string                 `json:"operationRef,omitempty" yaml:"operationRef,omitempty"`
 	OperationId  string                 `json:"operationId,omitempty" yaml:"operationId,omitempty"`
 	Parameters   map[string]interface{} `json:"parameters,omitempty" yaml:"parameters,omitempty"`
@@ -127,6 +127,23 @@ func TestGenerateMermaidDiagram(t *testing.T) {
 				"Project -.->|referenced_by| User",
 			},
 		},
+		{
+			name: "names that are not identifiers",
+			resources: []models.Resource{
+				{
+					Name: "api-keys",
+					Relationships: []models.Relationship{
+						{Type: "references", Resource: "tasks:batch-get", Strength: "strong"},
+					},
+				},
+				{Name: "tasks:batch-get"},
+			},
+			want: []string{
+				`    api_keys["api-keys"]`,
+				`    tasks_batch_get["tasks:batch-get"]`,
+				"api_keys -->|references| tasks_batch_get",
+			},
+		},
 		{
 			name:      "no relationships",
 			resources: []models.Resource{{Name: "User"}, {Name: "Post"}},
@@ -172,3 +189,50 @@ func TestMermaidRelationshipArrow(t *testing.T) {
 		})
 	}
 }
+
+func TestGenerateERDiagram(t *testing.T) {
+	r := &reporter{}
+	resources := []models.Resource{
+		{
+			Name: "orders",
+			Relationships: []models.Relationship{
+				{Resource: "customers", Type: "references", Via: "customer", Cardinality: models.CardinalityManyToOne, Required: true},
+				{Resource: "order-lines", Type: "has_many", Via: "path hierarchy", Cardinality: models.CardinalityOneToMany},
+				{Resource: "tags", Type: "references", Via: "tagIds", Cardinality: models.CardinalityManyToMany},
+				{Resource: "legacy", Type: "references"},
+			},
+		},
+		{
+			Name: "order-lines",
+			Relationships: []models.Relationship{
+				{Resource: "orders", Type: "belongs_to", Via: "path hierarchy", Cardinality: models.CardinalityManyToOne, Required: true},
+			},
+		},
+		{
+			Name: "users",
+			Relationships: []models.Relationship{
+				{Resource: "profile", Type: "has_one", Via: "path hierarchy", Cardinality: models.CardinalityOneToOne},
+			},
+		},
+		{
+			Name: "invoices",
+			Relationships: []models.Relationship{
+				{Resource: "orders", Type: "belongs_to", Via: "order", Cardinality: models.CardinalityManyToOne},
+			},
+		},
+	}
+
+	expected := "erDiagram\n" +
+		`    orders }o--|| customers : "customer"` + "\n" +
+		`    orders ||--o{ order_lines : "has_many"` + "\n" +
+		`    orders }o--o{ tags : "tagIds"` + "\n" +
+		`    users ||--o| profile : "has_one"` + "\n" +
+		`    invoices }o--o| orders : "order"` + "\n"
+	if got := r.generateERDiagram(resources); got != expected {
+		t.Errorf("Expected diagram:\n%s\ngot:\n%s", expected, got)
+	}
+
+	if got := r.generateERDiagram([]models.Resource{{Name: "orders"}}); got != "" {
+		t.Errorf("Expected no diagram without cardinalities, got %q", got)
+	}
+}
@@ -8,6 +8,7 @@ import (
 	"strings"
 	"unicode"
 
+	"github.com/orchard9/api-godoc/internal/graph"
 	"github.com/orchard9/api-godoc/pkg/models"
 )
 
@@ -15,6 +16,9 @@ import (
 type Reporter interface {
 	// Generate creates formatted output from analysis results
 	Generate(analysis *models.APIAnalysis, format string) (string, error)
+
+	// GenerateSystem creates formatted output from a multi-service analysis
+	GenerateSystem(system *models.SystemAnalysis, format string) (string, error)
 }
 
 // New creates a new reporter instance
@@ -61,10 +65,21 @@ func (r *reporter) generateMarkdown(analysis *models.APIAnalysis) (string, error
 	sb.WriteString(fmt.Sprintf("- **Total Operations**: %d\n", analysis.Summary.TotalOperations))
 	sb.WriteString(fmt.Sprintf("- **Total Endpoints**: %d\n", analysis.Summary.TotalEndpoints))
 	sb.WriteString(fmt.Sprintf("- **Resource Coverage**: %d%%\n\n", analysis.Summary.ResourceCoverage))
+	if len(analysis.Summary.Skipped) > 0 {
+		r.writeSkippedSegments(&sb, analysis.Summary.Skipped)
+	}
 
-	// Resources section
-	sb.WriteString("## Resources\n\n")
-	sb.WriteString("This section groups API endpoints by business resources for better understanding.\n\n")
+	// Hierarchy section
+	if r.hasHierarchy(analysis.Resources) {
+		sb.WriteString("## Resource Hierarchy\n\n")
+		r.writeHierarchySection(&sb, analysis.Resources)
+	}
+
+	// Capability matrix
+	if len(analysis.Capabilities) > 0 {
+		sb.WriteString("## Capability Matrix\n\n")
+		r.writeCapabilityMatrix(&sb, analysis.Capabilities)
+	}
 
 	// Sort resources by name for consistent output
 	sortedResources := make([]models.Resource, len(analysis.Resources))
@@ -73,8 +88,25 @@ func (r *reporter) generateMarkdown(analysis *models.APIAnalysis) (string, error
 		return sortedResources[i].Name < sortedResources[j].Name
 	})
 
-	for _, resource := range sortedResources {
-		r.writeResourceSection(&sb, resource)
+	// Resources section, split by bounded context for clustered APIs or by
+	// category once resources are classified
+	if len(analysis.Contexts) > 1 {
+		r.writeContexts(&sb, analysis.Contexts, sortedResources)
+	} else if categories := resourceCategories(sortedResources); len(categories) > 0 {
+		for _, category := range categories {
+			sb.WriteString(fmt.Sprintf("## %s Resources\n\n", titleCase(category)))
+			for _, resource := range sortedResources {
+				if resource.Category == category {
+					r.writeResourceSection(&sb, resource)
+				}
+			}
+		}
+	} else {
+		sb.WriteString("## Resources\n\n")
+		sb.WriteString("This section groups API endpoints by business resources for better understanding.\n\n")
+		for _, resource := range sortedResources {
+			r.writeResourceSection(&sb, resource)
+		}
 	}
 
 	// Relationships section
@@ -82,11 +114,22 @@ func (r *reporter) generateMarkdown(analysis *models.APIAnalysis) (string, error
 		sb.WriteString("## Resource Relationships\n\n")
 		sb.WriteString("This section shows how resources relate to each other.\n\n")
 
-		// Add Mermaid diagram
-		sb.WriteString("### Relationship Diagram\n\n")
-		sb.WriteString("```mermaid\n")
-		sb.WriteString(r.generateMermaidDiagram(analysis.Resources))
-		sb.WriteString("```\n\n")
+		// Add Mermaid diagram; clustered APIs have one per context instead
+		if len(analysis.Contexts) <= 1 {
+			sb.WriteString("### Relationship Diagram\n\n")
+			sb.WriteString("```mermaid\n")
+			sb.WriteString(r.generateMermaidDiagram(analysis.Resources))
+			sb.WriteString("```\n\n")
+		}
+
+		// The entity relationship diagram spans contexts
+		if er := r.generateERDiagram(analysis.Resources); er != "" {
+			sb.WriteString("### Entity Relationship Diagram\n\n")
+			sb.WriteString("Crow's-foot notation: `||` exactly one, `o|` zero or one, `o{` zero or more, `|{` one or more.\n\n")
+			sb.WriteString("```mermaid\n")
+			sb.WriteString(er)
+			sb.WriteString("```\n\n")
+		}
 
 		sb.WriteString("### Relationship Details\n\n")
 		r.writeRelationshipsSection(&sb, analysis.Resources)
@@ -111,12 +154,147 @@ func (r *reporter) generateMarkdown(analysis *models.APIAnalysis) (string, error
 		}
 	}
 
+	// Grouping section
+	if analysis.Grouping != nil {
+		r.writeGroupingSection(&sb, analysis.Grouping)
+	}
+
 	return sb.String(), nil
 }
 
+// groupingStrategies lists the strategies shown in the disagreement table
+var groupingStrategies = []string{"path", "tag", "operationId", "extension"}
+
+// writeSkippedSegments lists the path segments that were not treated as resources
+func (r *reporter) writeSkippedSegments(sb *strings.Builder, skipped []models.SkippedSegment) {
+	sb.WriteString("Path segments not treated as resources:\n\n")
+	sb.WriteString("| Segment | Reason | Paths |\n")
+	sb.WriteString("|---------|--------|-------|\n")
+	for _, s := range skipped {
+		reason := s.Reason
+		if s.Rule != "" && s.Rule != s.Segment {
+			reason = fmt.Sprintf("%s `%s`", s.Reason, s.Rule)
+		}
+		sb.WriteString(fmt.Sprintf("| `%s` | %s | %d |\n", s.Segment, reason, s.Paths))
+	}
+	sb.WriteString("\n")
+}
+
+// writeCapabilityMatrix writes a resource x capability table with the gaps of each resource
+func (r *reporter) writeCapabilityMatrix(sb *strings.Builder, matrix []models.Capabilities) {
+	mark := func(ok bool) string {
+		if ok {
+			return "✓"
+		}
+		return "✗"
+	}
+
+	sb.WriteString("| Resource | List | Get | Create | Update | Delete | Search | Actions | Gaps |\n")
+	sb.WriteString("|----------|------|-----|--------|--------|--------|--------|---------|------|\n")
+	for _, c := range matrix {
+		actions, gaps := "-", "-"
+		if len(c.Actions) > 0 {
+			actions = strings.Join(c.Actions, ", ")
+		}
+		if len(c.Gaps) > 0 {
+			gaps = strings.Join(c.Gaps, "; ")
+		}
+		sb.WriteString(fmt.Sprintf("| %s | %s | %s | %s | %s | %s | %s | %s | %s |\n",
+			c.Resource, mark(c.List), mark(c.Get), mark(c.Create), mark(c.Update), mark(c.Delete), mark(c.Search), actions, gaps))
+	}
+	sb.WriteString("\n")
+}
+
+// writeGroupingSection writes the grouping strategy and where strategies disagree
+func (r *reporter) writeGroupingSection(sb *strings.Builder, grouping *models.GroupingReport) {
+	sb.WriteString("## Resource Grouping\n\n")
+	sb.WriteString(fmt.Sprintf("Resources are grouped by **%s**.\n\n", grouping.Strategy))
+
+	if len(grouping.Disagreements) == 0 {
+		sb.WriteString("All grouping strategies agree on every operation.\n\n")
+		return
+	}
+
+	// Only show strategies that expressed an opinion somewhere
+	var columns []string
+	for _, strategy := range groupingStrategies {
+		for _, d := range grouping.Disagreements {
+			if _, ok := d.Assignments[strategy]; ok {
+				columns = append(columns, strategy)
+				break
+			}
+		}
+	}
+
+	sb.WriteString(fmt.Sprintf("Grouping strategies disagree on %d operations:\n\n", len(grouping.Disagreements)))
+	sb.WriteString("| Operation | " + strings.Join(columns, " | ") + " |\n")
+	sb.WriteString("|-----------|" + strings.Repeat("------|", len(columns)) + "\n")
+	for _, d := range grouping.Disagreements {
+		cells := make([]string, len(columns))
+		for i, strategy := range columns {
+			cells[i] = "-"
+			if resources := d.Assignments[strategy]; len(resources) > 0 {
+				cells[i] = strings.Join(resources, ", ")
+			}
+		}
+		sb.WriteString(fmt.Sprintf("| %s `%s` | %s |\n", d.Method, d.Path, strings.Join(cells, " | ")))
+	}
+	sb.WriteString("\n")
+}
+
+// hasHierarchy checks if any resources are nested under others
+func (r *reporter) hasHierarchy(resources []models.Resource) bool {
+	for _, resource := range resources {
+		if len(resource.Children) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// writeHierarchySection writes resources as a nested tree. Resources without
+// a parent are roots; a resource reachable both at the root and nested, or
+// nested under several parents, appears in every place.
+func (r *reporter) writeHierarchySection(sb *strings.Builder, resources []models.Resource) {
+	byName := make(map[string]models.Resource, len(resources))
+	var roots []string
+	for _, resource := range resources {
+		byName[resource.Name] = resource
+	}
+	for _, resource := range resources {
+		if _, ok := byName[resource.Parent]; !ok {
+			roots = append(roots, resource.Name)
+		}
+	}
+	sort.Strings(roots)
+
+	var writeNode func(name string, depth int, ancestors map[string]bool)
+	writeNode = func(name string, depth int, ancestors map[string]bool) {
+		resource := byName[name]
+		sb.WriteString(fmt.Sprintf("%s- **%s**", strings.Repeat("  ", depth), resource.Name))
+		if resource.PathPattern != "" {
+			sb.WriteString(fmt.Sprintf(" `%s`", resource.PathPattern))
+		}
+		sb.WriteString("\n")
+
+		ancestors[name] = true
+		for _, child := range resource.Children {
+			if _, ok := byName[child]; ok && !ancestors[child] {
+				writeNode(child, depth+1, ancestors)
+			}
+		}
+		delete(ancestors, name)
+	}
+
+	for _, root := range roots {
+		writeNode(root, 0, make(map[string]bool))
+	}
+	sb.WriteString("\n")
+}
+
 // writeResourceSection writes a resource and its operations
 func (r *reporter) writeResourceSection(sb *strings.Builder, resource models.Resource) {
-	sb.WriteString(fmt.Sprintf("### %s\n\n", titleCase(resource.Name)))
+	sb.WriteString(fmt.Sprintf("### %s\n\n", displayName(resource)))
 
 	if resource.Description != "" {
 		sb.WriteString(fmt.Sprintf("%s\n\n", resource.Description))
@@ -130,6 +308,25 @@ func (r *reporter) writeResourceSection(sb *strings.Builder, resource models.Res
 		sb.WriteString("**Type**: Collection Resource  \n")
 	}
 
+	if resource.PathPattern != "" {
+		sb.WriteString(fmt.Sprintf("**Path**: `%s`  \n", resource.PathPattern))
+	}
+
+	if resource.Parent != "" {
+		sb.WriteString(fmt.Sprintf("**Parent**: %s  \n", resource.Parent))
+	}
+
+	if len(resource.Aliases) > 0 {
+		sb.WriteString(fmt.Sprintf("**Aliases**: %s  \n", strings.Join(resource.Aliases, ", ")))
+	}
+
+	if resource.Schema != nil {
+		sb.WriteString(fmt.Sprintf("**Schema**: `%s` (%s)  \n", resource.Schema.Schema, bindingSources(resource.Schema.Evidence)))
+		if len(resource.Schema.Conflicts) > 0 {
+			sb.WriteString(fmt.Sprintf("**Conflicting Schemas**: %s  \n", strings.Join(resource.Schema.Conflicts, ", ")))
+		}
+	}
+
 	sb.WriteString(fmt.Sprintf("**Operations**: %d\n\n", len(resource.Operations)))
 
 	// Sort operations by method and path
@@ -163,6 +360,10 @@ func (r *reporter) writeResourceSection(sb *strings.Builder, resource models.Res
 
 	sb.WriteString("\n")
 
+	if resource.Lifecycle != nil {
+		r.writeLifecycle(sb, *resource.Lifecycle)
+	}
+
 	// Write detailed operation information
 	for _, op := range sortedOps {
 		r.writeOperationDetails(sb, op)
@@ -171,7 +372,7 @@ func (r *reporter) writeResourceSection(sb *strings.Builder, resource models.Res
 
 // writeOperationDetails writes detailed operation information
 func (r *reporter) writeOperationDetails(sb *strings.Builder, op models.Operation) {
-	if op.Description == "" && len(op.Parameters) == 0 && len(op.Responses) == 0 {
+	if op.Description == "" && len(op.Parameters) == 0 && op.RequestBody == nil && len(op.Responses) == 0 {
 		return // Skip if no additional details
 	}
 
@@ -181,6 +382,10 @@ func (r *reporter) writeOperationDetails(sb *strings.Builder, op models.Operatio
 		sb.WriteString(fmt.Sprintf("%s\n\n", op.Description))
 	}
 
+	if op.Kind != "" {
+		sb.WriteString(fmt.Sprintf("**Kind**: %s\n\n", op.Kind))
+	}
+
 	if len(op.Tags) > 0 {
 		sb.WriteString(fmt.Sprintf("**Tags**: %s\n\n", strings.Join(op.Tags, ", ")))
 	}
@@ -189,6 +394,10 @@ func (r *reporter) writeOperationDetails(sb *strings.Builder, op models.Operatio
 		sb.WriteString("⚠️ **Deprecated**\n\n")
 	}
 
+	if len(op.Security) > 0 {
+		sb.WriteString(fmt.Sprintf("**Security**: %s\n\n", strings.Join(op.Security, ", ")))
+	}
+
 	// Parameters
 	if len(op.Parameters) > 0 {
 		sb.WriteString("**Parameters**:\n\n")
@@ -219,6 +428,9 @@ func (r *reporter) writeOperationDetails(sb *strings.Builder, op models.Operatio
 			sb.WriteString(fmt.Sprintf("%s\n\n", op.RequestBody.Description))
 		}
 		sb.WriteString(fmt.Sprintf("- **Content Type**: %s\n", op.RequestBody.ContentType))
+		if op.RequestBody.Schema != nil {
+			sb.WriteString(fmt.Sprintf("- **Schema**: `%s`\n", op.RequestBody.Schema.Type))
+		}
 		if op.RequestBody.Required {
 			sb.WriteString("- **Required**: Yes\n")
 		}
@@ -258,11 +470,23 @@ func (r *reporter) writeRelationshipsSection(sb *strings.Builder, resources []mo
 			continue
 		}
 
-		sb.WriteString(fmt.Sprintf("### %s Relationships\n\n", titleCase(resource.Name)))
+		sb.WriteString(fmt.Sprintf("### %s Relationships\n\n", displayName(resource)))
 
 		for _, rel := range resource.Relationships {
-			sb.WriteString(fmt.Sprintf("- **%s** %s (%s strength", rel.Type, rel.Resource, rel.Strength))
-			if rel.Via != "" {
+			sb.WriteString(fmt.Sprintf("- **%s** %s (", rel.Type, rel.Resource))
+			if rel.Cardinality != "" {
+				sb.WriteString(rel.Cardinality + ", ")
+			}
+			if rel.Expandable {
+				sb.WriteString("expandable, ")
+			}
+			sb.WriteString(rel.Strength + " strength")
+			if rel.Confidence > 0 {
+				sb.WriteString(fmt.Sprintf(", confidence %.2f", rel.Confidence))
+			}
+			if rel.Association != "" {
+				sb.WriteString(fmt.Sprintf(" through `%s`", rel.Association))
+			} else if rel.Via != "" {
 				sb.WriteString(fmt.Sprintf(" via `%s`", rel.Via))
 			}
 			sb.WriteString(")\n")
@@ -270,6 +494,31 @@ func (r *reporter) writeRelationshipsSection(sb *strings.Builder, resources []mo
 			if rel.Description != "" {
 				sb.WriteString(fmt.Sprintf("  - %s\n", rel.Description))
 			}
+			if len(rel.Evidence) > 0 {
+				var evidence []string
+				for _, e := range rel.Evidence {
+					evidence = append(evidence, fmt.Sprintf("%s `%s` (%.2f)", strings.ReplaceAll(e.Kind, "_", " "), e.Source, e.Weight))
+				}
+				sb.WriteString(fmt.Sprintf("  - Evidence: %s\n", strings.Join(evidence, ", ")))
+			}
+			for _, operation := range rel.Operations {
+				sb.WriteString(fmt.Sprintf("  - `%s`\n", operation))
+			}
+			if len(rel.ExpandOperations) > 0 {
+				var operations []string
+				for _, operation := range rel.ExpandOperations {
+					operations = append(operations, fmt.Sprintf("`%s`", operation))
+				}
+				sb.WriteString(fmt.Sprintf("  - Expand with: %s\n", strings.Join(operations, ", ")))
+			}
+			if len(rel.Parameters) > 0 {
+				var parameters []string
+				for name, expression := range rel.Parameters {
+					parameters = append(parameters, fmt.Sprintf("`%s` = `%s`", name, expression))
+				}
+				sort.Strings(parameters)
+				sb.WriteString(fmt.Sprintf("  - Parameters: %s\n", strings.Join(parameters, ", ")))
+			}
 		}
 		sb.WriteString("\n")
 	}
@@ -290,9 +539,14 @@ func (r *reporter) generateMermaidDiagram(resources []models.Resource) string {
 	var sb strings.Builder
 	sb.WriteString("graph TD\n")
 
-	// First, list all resources as nodes
+	// First, list all resources as nodes, labelled with names such as
+	// api-keys that are not identifiers
 	for _, resource := range resources {
-		sb.WriteString(fmt.Sprintf("    %s\n", resource.Name))
+		if id := mermaidID(resource.Name); id != resource.Name {
+			sb.WriteString(fmt.Sprintf("    %s[\"%s\"]\n", id, resource.Name))
+		} else {
+			sb.WriteString(fmt.Sprintf("    %s\n", id))
+		}
 	}
 
 	// Then, add relationships as edges
@@ -300,13 +554,221 @@ func (r *reporter) generateMermaidDiagram(resources []models.Resource) string {
 		for _, rel := range resource.Relationships {
 			arrow := r.getMermaidArrow(rel.Strength)
 			sb.WriteString(fmt.Sprintf("    %s %s|%s| %s\n",
-				resource.Name, arrow, rel.Type, rel.Resource))
+				mermaidID(resource.Name), arrow, relationshipLabel(rel.Type, rel), mermaidID(rel.Resource)))
 		}
 	}
 
 	return sb.String()
 }
 
+// inverseRelationships are the reverse side of another relationship and are
+// only drawn in entity relationship diagrams when the forward side is missing
+var inverseRelationships = map[string]bool{"belongs_to": true, "referenced_by": true}
+
+// generateERDiagram creates a Mermaid entity relationship diagram with one
+// crow's-foot edge per related pair of resources
+func (r *reporter) generateERDiagram(resources []models.Resource) string {
+	sorted := make([]models.Resource, len(resources))
+	copy(sorted, resources)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Name < sorted[j].Name })
+
+	var lines []string
+	drawn := make(map[[2]string]bool)
+	for _, inverse := range []bool{false, true} {
+		for _, resource := range sorted {
+			for _, rel := range resource.Relationships {
+				if rel.Cardinality == "" || inverseRelationships[rel.Type] != inverse || rel.Resource == resource.Name {
+					continue
+				}
+				pair := [2]string{resource.Name, rel.Resource}
+				if pair[0] > pair[1] {
+					pair[0], pair[1] = pair[1], pair[0]
+				}
+				if drawn[pair] {
+					continue
+				}
+				drawn[pair] = true
+
+				label := rel.Via
+				if label == "" || label == "path hierarchy" {
+					label = rel.Type
+				}
+				lines = append(lines, fmt.Sprintf("    %s %s %s : \"%s\"\n",
+					mermaidID(resource.Name), crowsFoot(rel), mermaidID(rel.Resource), strings.ReplaceAll(relationshipLabel(label, rel), `"`, "'")))
+			}
+		}
+	}
+	if len(lines) == 0 {
+		return ""
+	}
+	return "erDiagram\n" + strings.Join(lines, "")
+}
+
+// relationshipLabel marks a diagram edge label for an expandable relationship
+func relationshipLabel(label string, rel models.Relationship) string {
+	if rel.Expandable {
+		return label + " (expandable)"
+	}
+	return label
+}
+
+// crowsFoot returns the Mermaid erDiagram edge for a relationship
+func crowsFoot(rel models.Relationship) string {
+	left, right := graph.CrowsFootEnds(rel.Cardinality, rel.Required)
+	return left + "--" + right
+}
+
+// writeContexts writes an overview of the bounded contexts followed by the
+// resources of each context with a diagram of their relationships
+func (r *reporter) writeContexts(sb *strings.Builder, contexts []models.BoundedContext, resources []models.Resource) {
+	sb.WriteString("## Bounded Contexts\n\n")
+	sb.WriteString("Resources are clustered into contexts by their relationships, shared tags and path prefixes.\n\n")
+	sb.WriteString("| Context | Resources | Related Contexts |\n")
+	sb.WriteString("|---------|-----------|------------------|\n")
+	for _, context := range contexts {
+		name := contextTitle(context.Name)
+		if context.Pinned {
+			name += " (pinned)"
+		}
+		var related []string
+		for _, link := range context.Links {
+			related = append(related, fmt.Sprintf("%s (%d)", contextTitle(link.Context), link.Relationships))
+		}
+		sb.WriteString(fmt.Sprintf("| %s | %d | %s |\n", name, len(context.Resources), strings.Join(related, ", ")))
+	}
+	sb.WriteString("\n")
+
+	sb.WriteString("```mermaid\n")
+	sb.WriteString(r.generateContextDiagram(contexts))
+	sb.WriteString("```\n\n")
+
+	byContext := make(map[string][]models.Resource)
+	for _, resource := range resources {
+		byContext[resource.Context] = append(byContext[resource.Context], resource)
+	}
+	for _, context := range contexts {
+		members := byContext[context.Name]
+		sb.WriteString(fmt.Sprintf("## %s Context\n\n", contextTitle(context.Name)))
+
+		// Only relationships within the context appear in its diagram
+		inContext := make(map[string]bool)
+		for _, resource := range members {
+			inContext[resource.Name] = true
+		}
+		internal := make([]models.Resource, len(members))
+		for i, resource := range members {
+			internal[i] = resource
+			internal[i].Relationships = nil
+			for _, rel := range resource.Relationships {
+				if inContext[rel.Resource] {
+					internal[i].Relationships = append(internal[i].Relationships, rel)
+				}
+			}
+		}
+		if r.hasRelationships(internal) {
+			sb.WriteString("```mermaid\n")
+			sb.WriteString(r.generateMermaidDiagram(internal))
+			sb.WriteString("```\n\n")
+		}
+
+		for _, resource := range members {
+			r.writeResourceSection(sb, resource)
+		}
+	}
+}
+
+// generateContextDiagram creates a Mermaid diagram of contexts linked by the
+// number of relationships between their resources
+func (r *reporter) generateContextDiagram(contexts []models.BoundedContext) string {
+	var sb strings.Builder
+	sb.WriteString("graph LR\n")
+
+	for _, context := range contexts {
+		sb.WriteString(fmt.Sprintf("    %s[\"%s (%d)\"]\n", mermaidID(context.Name), contextTitle(context.Name), len(context.Resources)))
+	}
+	// Links are symmetric, so each pair is drawn once
+	for _, context := range contexts {
+		for _, link := range context.Links {
+			if context.Name < link.Context {
+				sb.WriteString(fmt.Sprintf("    %s ---|%d| %s\n", mermaidID(context.Name), link.Relationships, mermaidID(link.Context)))
+			}
+		}
+	}
+
+	return sb.String()
+}
+
+// writeLifecycle writes a resource's state diagram and its transitions
+func (r *reporter) writeLifecycle(sb *strings.Builder, lifecycle models.Lifecycle) {
+	sb.WriteString("#### Lifecycle\n\n")
+	sb.WriteString(fmt.Sprintf("**States** (`%s`): %s\n\n", lifecycle.Field, strings.Join(lifecycle.States, ", ")))
+
+	sb.WriteString("```mermaid\n")
+	sb.WriteString(r.generateStateDiagram(lifecycle))
+	sb.WriteString("```\n\n")
+
+	sb.WriteString("| Action | From | To | Operation |\n")
+	sb.WriteString("|--------|------|----|-----------|\n")
+	for _, t := range lifecycle.Transitions {
+		from, to := "any", "?"
+		if len(t.From) > 0 {
+			from = strings.Join(t.From, ", ")
+		}
+		if t.To != "" {
+			to = t.To
+		}
+		sb.WriteString(fmt.Sprintf("| %s | %s | %s | `%s %s` |\n", t.Action, from, to, t.Method, t.Path))
+	}
+	sb.WriteString("\n")
+}
+
+// generateStateDiagram creates a Mermaid state diagram for a lifecycle.
+// Transitions from unknown states start at an "any" pseudo-state; transitions
+// to unknown states are only listed in the table.
+func (r *reporter) generateStateDiagram(lifecycle models.Lifecycle) string {
+	var sb strings.Builder
+	sb.WriteString("stateDiagram-v2\n")
+
+	for _, state := range lifecycle.States {
+		if id := mermaidID(state); id != state {
+			sb.WriteString(fmt.Sprintf("    state \"%s\" as %s\n", state, id))
+		}
+	}
+	if lifecycle.Initial != "" {
+		sb.WriteString(fmt.Sprintf("    [*] --> %s\n", mermaidID(lifecycle.Initial)))
+	}
+
+	anyDeclared := false
+	for _, t := range lifecycle.Transitions {
+		if t.To == "" {
+			continue
+		}
+		from := t.From
+		if len(from) == 0 {
+			if !anyDeclared {
+				sb.WriteString("    state \"any\" as any_state\n")
+				anyDeclared = true
+			}
+			from = []string{"any_state"}
+		}
+		for _, state := range from {
+			sb.WriteString(fmt.Sprintf("    %s --> %s : %s\n", mermaidID(state), mermaidID(t.To), t.Action))
+		}
+	}
+
+	return sb.String()
+}
+
+// mermaidID converts a name to a Mermaid identifier, e.g. "in-progress" to "in_progress"
+func mermaidID(state string) string {
+	return strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_' {
+			return r
+		}
+		return '_'
+	}, state)
+}
+
 // getMermaidArrow returns the appropriate arrow style based on relationship strength
 func (r *reporter) getMermaidArrow(strength string) string {
 	switch strength {
@@ -363,18 +825,50 @@ func (r *reporter) generateAIOptimized(analysis *models.APIAnalysis) (string, er
 		if len(resource.Relationships) > 0 {
 			var relTypes []string
 			for _, rel := range resource.Relationships {
-				relTypes = append(relTypes, fmt.Sprintf("%s:%s", rel.Type, rel.Resource))
+				entry := fmt.Sprintf("%s:%s", rel.Type, rel.Resource)
+				details := graph.ShortCardinality(rel.Cardinality)
+				if details != "" && rel.Association != "" {
+					details += " through " + rel.Association
+				}
+				if rel.Expandable {
+					details = strings.TrimPrefix(details+", expandable", ", ")
+				}
+				if details != "" {
+					entry += "(" + details + ")"
+				}
+				relTypes = append(relTypes, entry)
 			}
 			sb.WriteString(fmt.Sprintf(" -> %s", strings.Join(relTypes, ", ")))
 		}
 		sb.WriteString("\n")
 	}
 
+	if len(analysis.Contexts) > 1 {
+		sb.WriteString("\nCONTEXTS:\n")
+		for _, context := range analysis.Contexts {
+			sb.WriteString(fmt.Sprintf("- %s: %s\n", context.Name, strings.Join(context.Resources, ", ")))
+		}
+	}
+
+	var gaps []string
+	for _, c := range analysis.Capabilities {
+		if len(c.Gaps) > 0 {
+			gaps = append(gaps, fmt.Sprintf("- %s: %s\n", c.Resource, strings.Join(c.Gaps, "; ")))
+		}
+	}
+	if len(gaps) > 0 {
+		sb.WriteString("\nCAPABILITY GAPS:\n")
+		sb.WriteString(strings.Join(gaps, ""))
+	}
+
 	sb.WriteString("\nKEY OPERATIONS:\n")
 	for _, resource := range analysis.Resources {
 		for _, op := range resource.Operations {
 			if r.isKeyOperation(op) {
 				sb.WriteString(fmt.Sprintf("- %s %s", op.Method, op.Path))
+				if op.Kind != "" {
+					sb.WriteString(fmt.Sprintf(" [%s]", op.Kind))
+				}
 				if op.Summary != "" {
 					summary := op.Summary
 					if len(summary) > 60 {
@@ -392,12 +886,18 @@ func (r *reporter) generateAIOptimized(analysis *models.APIAnalysis) (string, er
 
 // isKeyOperation determines if an operation is important for AI summary
 func (r *reporter) isKeyOperation(op models.Operation) bool {
-	// Include CRUD operations and operations with clear business logic
-	if op.Method == "GET" || op.Method == "POST" || op.Method == "PUT" || op.Method == "DELETE" {
+	// Include classified CRUD operations
+	switch op.Kind {
+	case models.KindList, models.KindGet, models.KindCreate, models.KindReplace, models.KindUpdate, models.KindDelete:
 		return true
+	case "":
+		// Unclassified operations fall back to the HTTP method
+		if op.Method == "GET" || op.Method == "POST" || op.Method == "PUT" || op.Method == "DELETE" {
+			return true
+		}
 	}
 
-	// Include operations with meaningful summaries
+	// Include actions and other operations with meaningful summaries
 	if op.Summary != "" && len(op.Summary) > 10 {
 		return true
 	}
@@ -405,6 +905,57 @@ func (r *reporter) isKeyOperation(op models.Operation) bool {
 	return false
 }
 
+// categoryRank orders the built-in categories; custom categories follow alphabetically
+var categoryRank = map[string]int{
+	models.CategoryCore:        1,
+	models.CategoryAuth:        2,
+	models.CategoryAdmin:       3,
+	models.CategoryOps:         4,
+	models.CategoryIntegration: 5,
+	models.CategoryUtility:     6,
+}
+
+// resourceCategories returns the categories used by resources in display
+// order, or nil when no resource is classified
+func resourceCategories(resources []models.Resource) []string {
+	seen := make(map[string]bool)
+	var categories []string
+	for _, resource := range resources {
+		if !seen[resource.Category] {
+			seen[resource.Category] = true
+			categories = append(categories, resource.Category)
+		}
+	}
+	if len(categories) == 1 && categories[0] == "" {
+		return nil
+	}
+
+	rank := func(category string) int {
+		if r, ok := categoryRank[category]; ok {
+			return r
+		}
+		if category == "" {
+			return len(categoryRank) + 2 // unclassified last
+		}
+		return len(categoryRank) + 1
+	}
+	sort.SliceStable(categories, func(i, j int) bool {
+		if rank(categories[i]) != rank(categories[j]) {
+			return rank(categories[i]) < rank(categories[j])
+		}
+		return categories[i] < categories[j]
+	})
+	return categories
+}
+
+// displayName returns the resource display name, falling back to its title-cased name
+func displayName(resource models.Resource) string {
+	if resource.DisplayName != "" {
+		return resource.DisplayName
+	}
+	return titleCase(resource.Name)
+}
+
 // titleCase capitalizes the first letter of a string
 func titleCase(s string) string {
 	if s == "" {
@@ -414,3 +965,25 @@ func titleCase(s string) string {
 	runes[0] = unicode.ToUpper(runes[0])
 	return string(runes)
 }
+
+// contextTitle formats a context name for headings, e.g. "document-analysis" as "Document Analysis"
+func contextTitle(name string) string {
+	words := strings.Split(name, "-")
+	for i, word := range words {
+		words[i] = titleCase(word)
+	}
+	return strings.Join(words, " ")
+}
+
+// bindingSources summarizes binding evidence as "response, list-item"
+func bindingSources(evidence []models.BindingEvidence) string {
+	var sources []string
+	seen := make(map[string]bool)
+	for _, e := range evidence {
+		if !seen[e.Source] {
+			seen[e.Source] = true
+			sources = append(sources, e.Source)
+		}
+	}
+	return strings.Join(sources, ", ")
+}
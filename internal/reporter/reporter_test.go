@@ -199,8 +199,8 @@ func TestAIOptimizedGeneration(t *testing.T) {
 			{
 				Name: "users",
 				Operations: []models.Operation{
-					{Method: "GET", Path: "/users", Summary: "List users"},
-					{Method: "POST", Path: "/users", Summary: "Create user"},
+					{Method: "GET", Path: "/users", Summary: "List users", Kind: models.KindList},
+					{Method: "POST", Path: "/users", Summary: "Create user", Kind: models.KindCreate},
 				},
 				Relationships: []models.Relationship{
 					{Resource: "posts", Type: "has_many"},
@@ -229,9 +229,9 @@ func TestAIOptimizedGeneration(t *testing.T) {
 		"- users (2 ops) -> has_many:posts",
 		"- posts (1 ops)",
 		"KEY OPERATIONS:",
-		"- GET /users",
-		"- POST /users",
-		"- GET /posts",
+		"- GET /users [list] (List users)",
+		"- POST /users [create] (Create user)",
+		"- GET /posts (List posts)",
 	}
 
 	for _, content := range expectedContent {
@@ -352,6 +352,21 @@ func TestKeyOperationDetection(t *testing.T) {
 			isKey:     false,
 			name:      "Operation with short summary",
 		},
+		{
+			operation: models.Operation{Method: "PATCH", Path: "/users/{id}", Kind: models.KindUpdate},
+			isKey:     true,
+			name:      "Classified update",
+		},
+		{
+			operation: models.Operation{Method: "POST", Path: "/users/{id}:activate", Kind: models.KindAction},
+			isKey:     false,
+			name:      "Action without summary",
+		},
+		{
+			operation: models.Operation{Method: "POST", Path: "/tasks:batchGet", Kind: models.KindBatch, Summary: "Get several tasks at once"},
+			isKey:     true,
+			name:      "Batch with meaningful summary",
+		},
 	}
 
 	for _, tc := range testCases {
@@ -389,3 +404,471 @@ func TestEmptyAnalysis(t *testing.T) {
 		t.Error("Should not have relationships section for empty analysis")
 	}
 }
+
+func TestGroupingSection(t *testing.T) {
+	analysis := &models.APIAnalysis{
+		Title:   "Test API",
+		Version: "1.0.0",
+		Grouping: &models.GroupingReport{
+			Strategy: "hybrid",
+			Disagreements: []models.GroupingDisagreement{
+				{
+					Method:      "GET",
+					Path:        "/audits/{id}",
+					Assignments: map[string][]string{"path": {"audits"}, "tag": {"project"}},
+				},
+			},
+		},
+	}
+
+	result, err := New().Generate(analysis, "markdown")
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	expected := []string{
+		"## Resource Grouping",
+		"Resources are grouped by **hybrid**.",
+		"| Operation | path | tag |",
+		"| GET `/audits/{id}` | audits | project |",
+	}
+	for _, want := range expected {
+		if !strings.Contains(result, want) {
+			t.Errorf("Expected %q in markdown output", want)
+		}
+	}
+}
+
+func TestHierarchySection(t *testing.T) {
+	analysis := &models.APIAnalysis{
+		Title:   "Test API",
+		Version: "1.0.0",
+		Resources: []models.Resource{
+			{Name: "features", PathPattern: "/features/{featureId}", Children: []string{"features/tasks"}},
+			{Name: "features/tasks", PathPattern: "/features/{featureId}/tasks", Parent: "features"},
+			{Name: "tasks", PathPattern: "/tasks/{taskId}"},
+		},
+	}
+
+	result, err := New().Generate(analysis, "markdown")
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	expected := "## Resource Hierarchy\n\n" +
+		"- **features** `/features/{featureId}`\n" +
+		"  - **features/tasks** `/features/{featureId}/tasks`\n" +
+		"- **tasks** `/tasks/{taskId}`\n"
+	if !strings.Contains(result, expected) {
+		t.Errorf("Expected hierarchy tree:\n%s\ngot:\n%s", expected, result)
+	}
+	if !strings.Contains(result, "**Parent**: features") {
+		t.Error("Expected parent in resource section")
+	}
+
+	// A resource under several parents is a root and appears under each parent
+	shared := &models.APIAnalysis{
+		Title: "Shared",
+		Resources: []models.Resource{
+			{Name: "attachments", PathPattern: "/tasks/{taskId}/attachments/{id}"},
+			{Name: "features", PathPattern: "/features/{featureId}", Children: []string{"attachments"}},
+			{Name: "tasks", PathPattern: "/tasks/{taskId}", Children: []string{"attachments"}},
+		},
+	}
+	result, _ = New().Generate(shared, "markdown")
+	expected = "## Resource Hierarchy\n\n" +
+		"- **attachments** `/tasks/{taskId}/attachments/{id}`\n" +
+		"- **features** `/features/{featureId}`\n" +
+		"  - **attachments** `/tasks/{taskId}/attachments/{id}`\n" +
+		"- **tasks** `/tasks/{taskId}`\n" +
+		"  - **attachments** `/tasks/{taskId}/attachments/{id}`\n"
+	if !strings.Contains(result, expected) {
+		t.Errorf("Expected shared resource under each parent:\n%s\ngot:\n%s", expected, result)
+	}
+	if strings.Contains(result, "**Parent**:") {
+		t.Error("Expected no parent for a resource nested under several parents")
+	}
+
+	// Flat APIs have no hierarchy section
+	flat := &models.APIAnalysis{Title: "Flat", Resources: []models.Resource{{Name: "users"}}}
+	result, _ = New().Generate(flat, "markdown")
+	if strings.Contains(result, "## Resource Hierarchy") {
+		t.Error("Expected no hierarchy section without nested resources")
+	}
+}
+
+func TestSkippedSegments(t *testing.T) {
+	analysis := &models.APIAnalysis{
+		Title:   "Test API",
+		Version: "1.0.0",
+		Summary: models.AnalysisStat{
+			Skipped: []models.SkippedSegment{
+				{Segment: "v1", Reason: "version", Paths: 12},
+				{Segment: "internal", Reason: "pattern", Rule: "intern.*", Paths: 2},
+				{Segment: "/tenants/{tenant}", Reason: "prefix", Rule: "/tenants/{tenant}", Paths: 5},
+			},
+		},
+	}
+
+	result, err := New().Generate(analysis, "markdown")
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	expected := []string{
+		"| Segment | Reason | Paths |",
+		"| `v1` | version | 12 |",
+		"| `internal` | pattern `intern.*` | 2 |",
+		"| `/tenants/{tenant}` | prefix | 5 |",
+	}
+	for _, want := range expected {
+		if !strings.Contains(result, want) {
+			t.Errorf("Expected %q in markdown output", want)
+		}
+	}
+}
+
+func TestCapabilityMatrix(t *testing.T) {
+	analysis := &models.APIAnalysis{
+		Title:   "Test API",
+		Version: "1.0.0",
+		Capabilities: []models.Capabilities{
+			{Resource: "orders", List: true, Get: true, Create: true, Actions: []string{"cancel"}, Gaps: []string{"can create but not delete"}},
+			{Resource: "users", List: true, Get: true, Create: true, Update: true, Delete: true, Search: true},
+		},
+	}
+
+	markdown, err := New().Generate(analysis, "markdown")
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	for _, want := range []string{
+		"## Capability Matrix",
+		"| Resource | List | Get | Create | Update | Delete | Search | Actions | Gaps |",
+		"| orders | ✓ | ✓ | ✓ | ✗ | ✗ | ✗ | cancel | can create but not delete |",
+		"| users | ✓ | ✓ | ✓ | ✓ | ✓ | ✓ | - | - |",
+	} {
+		if !strings.Contains(markdown, want) {
+			t.Errorf("Expected %q in markdown output", want)
+		}
+	}
+
+	ai, err := New().Generate(analysis, "ai")
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if !strings.Contains(ai, "CAPABILITY GAPS:\n- orders: can create but not delete\n") {
+		t.Errorf("Expected capability gaps in AI output, got:\n%s", ai)
+	}
+	if strings.Contains(ai, "- users:") {
+		t.Error("Expected complete resources to be left out of capability gaps")
+	}
+}
+
+func TestResourcesGroupedByCategory(t *testing.T) {
+	analysis := &models.APIAnalysis{
+		Title:   "Test API",
+		Version: "1.0.0",
+		Resources: []models.Resource{
+			{Name: "health", Category: models.CategoryOps},
+			{Name: "users", Category: models.CategoryCore},
+			{Name: "api-keys", Category: models.CategoryAuth},
+			{Name: "invoices", Category: "billing"},
+		},
+	}
+
+	result, err := New().Generate(analysis, "markdown")
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	order := []string{"## Core Resources", "### Users", "## Auth Resources", "### Api-keys", "## Ops Resources", "### Health", "## Billing Resources", "### Invoices"}
+	last := -1
+	for _, heading := range order {
+		index := strings.Index(result, heading)
+		if index < 0 {
+			t.Fatalf("Expected %q in markdown output", heading)
+		}
+		if index < last {
+			t.Errorf("Expected %q after the previous heading", heading)
+		}
+		last = index
+	}
+	if strings.Contains(result, "## Resources\n") {
+		t.Error("Expected categorized resources to replace the single Resources section")
+	}
+}
+
+func TestResourceSchemaBinding(t *testing.T) {
+	analysis := &models.APIAnalysis{
+		Title:   "Test API",
+		Version: "1.0.0",
+		Resources: []models.Resource{
+			{
+				Name: "projects",
+				Schema: &models.SchemaBinding{
+					Schema: "v1Project",
+					Evidence: []models.BindingEvidence{
+						{Source: "response", Method: "GET", Path: "/projects/{id}"},
+						{Source: "list-item", Method: "GET", Path: "/projects"},
+						{Source: "response", Method: "PATCH", Path: "/projects/{id}"},
+					},
+					Conflicts: []string{"v1ProjectSummary"},
+				},
+			},
+		},
+	}
+
+	result, err := New().Generate(analysis, "markdown")
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	expected := []string{
+		"**Schema**: `v1Project` (response, list-item)",
+		"**Conflicting Schemas**: v1ProjectSummary",
+	}
+	for _, want := range expected {
+		if !strings.Contains(result, want) {
+			t.Errorf("Expected %q in markdown output", want)
+		}
+	}
+}
+
+func TestResourceLifecycle(t *testing.T) {
+	analysis := &models.APIAnalysis{
+		Title:   "Test API",
+		Version: "1.0.0",
+		Resources: []models.Resource{
+			{
+				Name: "orders",
+				Lifecycle: &models.Lifecycle{
+					Field:   "status",
+					States:  []string{"pending", "in-progress", "cancelled"},
+					Initial: "pending",
+					Transitions: []models.Transition{
+						{Action: "start", From: []string{"pending"}, To: "in-progress", Method: "POST", Path: "/orders/{id}:start"},
+						{Action: "cancel", To: "cancelled", Method: "POST", Path: "/orders/{id}:cancel"},
+						{Action: "refund", Method: "POST", Path: "/orders/{id}:refund"},
+					},
+				},
+			},
+		},
+	}
+
+	result, err := New().Generate(analysis, "markdown")
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	expected := []string{
+		"#### Lifecycle",
+		"**States** (`status`): pending, in-progress, cancelled",
+		"stateDiagram-v2",
+		"state \"in-progress\" as in_progress",
+		"[*] --> pending",
+		"pending --> in_progress : start",
+		"state \"any\" as any_state",
+		"any_state --> cancelled : cancel",
+		"| refund | any | ? | `POST /orders/{id}:refund` |",
+	}
+	for _, want := range expected {
+		if !strings.Contains(result, want) {
+			t.Errorf("Expected %q in markdown output", want)
+		}
+	}
+	if strings.Contains(result, ": refund") {
+		t.Error("Expected transitions without a target state to be left out of the diagram")
+	}
+}
+
+func TestBoundedContexts(t *testing.T) {
+	analysis := &models.APIAnalysis{
+		Title:   "Test API",
+		Version: "1.0.0",
+		Resources: []models.Resource{
+			{Name: "invoices", Context: "billing", Relationships: []models.Relationship{
+				{Resource: "payments", Type: "has_many", Strength: "strong", Cardinality: models.CardinalityOneToMany},
+				{Resource: "products", Type: "references", Strength: "weak", Cardinality: models.CardinalityManyToOne},
+			}},
+			{Name: "payments", Context: "billing"},
+			{Name: "products", Context: "product-catalog"},
+		},
+		Contexts: []models.BoundedContext{
+			{Name: "billing", Pinned: true, Resources: []string{"invoices", "payments"},
+				Links: []models.ContextLink{{Context: "product-catalog", Relationships: 1}}},
+			{Name: "product-catalog", Resources: []string{"products"},
+				Links: []models.ContextLink{{Context: "billing", Relationships: 1}}},
+		},
+	}
+
+	result, err := New().Generate(analysis, "markdown")
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	expected := []string{
+		"## Bounded Contexts",
+		"| Billing (pinned) | 2 | Product Catalog (1) |",
+		"graph LR",
+		"product_catalog[\"Product Catalog (1)\"]",
+		"billing ---|1| product_catalog",
+		"## Billing Context",
+		"invoices -->|has_many| payments",
+		"## Product Catalog Context",
+		// The entity relationship diagram still covers every context
+		"### Entity Relationship Diagram",
+		"invoices ||--o{ payments",
+		"invoices }o--o| products",
+	}
+	for _, want := range expected {
+		if !strings.Contains(result, want) {
+			t.Errorf("Expected %q in markdown output", want)
+		}
+	}
+
+	// Context diagrams only show relationships within the context
+	if strings.Contains(result, "invoices -.->|references| products") {
+		t.Error("Expected cross-context relationships to be left out of context diagrams")
+	}
+	if strings.Contains(result, "### Relationship Diagram") {
+		t.Error("Expected the global relationship diagram to be replaced by context diagrams")
+	}
+
+	ai, err := New().Generate(analysis, "ai")
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if !strings.Contains(ai, "- billing: invoices, payments") {
+		t.Errorf("Expected contexts in AI output, got:\n%s", ai)
+	}
+}
+
+func TestRelationshipAssociations(t *testing.T) {
+	operations := []string{"PUT /groups/{groupId}/members/{userId}", "DELETE /groups/{groupId}/members/{userId}"}
+	analysis := &models.APIAnalysis{
+		Title:   "Test API",
+		Version: "1.0.0",
+		Resources: []models.Resource{
+			{
+				Name: "groups",
+				Relationships: []models.Relationship{
+					{Resource: "users", Type: "associated_with", Via: "members", Strength: "strong", Cardinality: models.CardinalityManyToMany, Association: "members", Operations: operations},
+				},
+			},
+			{
+				Name: "users",
+				Relationships: []models.Relationship{
+					{Resource: "groups", Type: "associated_with", Via: "members", Strength: "strong", Cardinality: models.CardinalityManyToMany, Association: "members", Operations: operations},
+				},
+			},
+		},
+	}
+
+	tests := []struct {
+		format   string
+		expected []string
+	}{
+		{"markdown", []string{
+			"- **associated_with** users (many-to-many, strong strength through `members`)\n  - `PUT /groups/{groupId}/members/{userId}`\n  - `DELETE /groups/{groupId}/members/{userId}`\n",
+			`groups }o--o{ users : "members"`,
+		}},
+		{"ai", []string{"- groups (0 ops) -> associated_with:users(N:M through members)"}},
+	}
+
+	for _, tt := range tests {
+		result, err := New().Generate(analysis, tt.format)
+		if err != nil {
+			t.Fatalf("Generate(%s) error = %v", tt.format, err)
+		}
+		for _, content := range tt.expected {
+			if !strings.Contains(result, content) {
+				t.Errorf("Expected %s output to contain %q", tt.format, content)
+			}
+		}
+	}
+}
+
+func TestRelationshipEvidence(t *testing.T) {
+	analysis := &models.APIAnalysis{
+		Title:   "Test API",
+		Version: "1.0.0",
+		Resources: []models.Resource{
+			{
+				Name: "orders",
+				Relationships: []models.Relationship{
+					{
+						Resource: "customers", Type: "references", Via: "customerId", Strength: "medium", Confidence: 0.5,
+						Evidence: []models.Evidence{
+							{Kind: models.EvidenceNaming, Source: "#/components/schemas/Order/properties/customerId", Weight: 0.5},
+						},
+					},
+					{
+						Resource: "customers", Type: "navigable_via", Via: "customer", Strength: "strong", Confidence: 0.9,
+						Operations: []string{"GET /customers/{customerId}"},
+						Parameters: map[string]string{"customerId": "$response.body#/customerId"},
+					},
+				},
+			},
+		},
+	}
+
+	result, err := New().Generate(analysis, "markdown")
+	if err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+	for _, expected := range []string{
+		"- **references** customers (medium strength, confidence 0.50 via `customerId`)\n" +
+			"  - Evidence: naming `#/components/schemas/Order/properties/customerId` (0.50)\n",
+		"- **navigable_via** customers (strong strength, confidence 0.90 via `customer`)\n" +
+			"  - `GET /customers/{customerId}`\n" +
+			"  - Parameters: `customerId` = `$response.body#/customerId`\n",
+	} {
+		if !strings.Contains(result, expected) {
+			t.Errorf("Expected relationship details %q in output", expected)
+		}
+	}
+}
+
+func TestExpandableRelationships(t *testing.T) {
+	analysis := &models.APIAnalysis{
+		Title:   "Test API",
+		Version: "1.0.0",
+		Resources: []models.Resource{
+			{
+				Name: "charges",
+				Relationships: []models.Relationship{
+					{
+						Resource: "customers", Type: "references", Via: "customer", Strength: "strong", Cardinality: models.CardinalityManyToOne,
+						Expandable: true, ExpandOperations: []string{"GET /charges", "GET /charges/{chargeId}"},
+					},
+				},
+			},
+		},
+	}
+
+	tests := []struct {
+		format   string
+		expected []string
+	}{
+		{"markdown", []string{
+			"- **references** customers (many-to-one, expandable, strong strength via `customer`)\n",
+			"  - Expand with: `GET /charges`, `GET /charges/{chargeId}`\n",
+			"charges -->|references (expandable)| customers",
+			`charges }o--o| customers : "customer (expandable)"`,
+		}},
+		{"ai", []string{"- charges (0 ops) -> references:customers(N:1, expandable)"}},
+	}
+
+	for _, tt := range tests {
+		result, err := New().Generate(analysis, tt.format)
+		if err != nil {
+			t.Fatalf("Generate(%s) error = %v", tt.format, err)
+		}
+		for _, content := range tt.expected {
+			if !strings.Contains(result, content) {
+				t.Errorf("Expected %s output to contain %q", tt.format, content)
+			}
+		}
+	}
+}
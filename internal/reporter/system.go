@@ -0,0 +1,175 @@
+package reporter
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/orchard9/api-godoc/pkg/models"
+)
+
+// GenerateSystem creates formatted output for several services and the
+// dependencies between them
+func (r *reporter) GenerateSystem(system *models.SystemAnalysis, format string) (string, error) {
+	switch format {
+	case "json":
+		data, err := json.MarshalIndent(system, "", "  ")
+		if err != nil {
+			return "", fmt.Errorf("failed to marshal JSON: %w", err)
+		}
+		return string(data), nil
+	case "ai":
+		return r.generateSystemAI(system)
+	default: // markdown
+		return r.generateSystemMarkdown(system)
+	}
+}
+
+// generateSystemMarkdown creates a combined report: an overview of the
+// services and their dependencies followed by each service's own report
+func (r *reporter) generateSystemMarkdown(system *models.SystemAnalysis) (string, error) {
+	var sb strings.Builder
+
+	sb.WriteString("# System Overview\n\n")
+	sb.WriteString(fmt.Sprintf("- **Services**: %d\n", len(system.Services)))
+	sb.WriteString(fmt.Sprintf("- **Dependencies**: %d\n", len(system.Dependencies)))
+	sb.WriteString(fmt.Sprintf("- **Generated**: %s\n\n", system.GeneratedAt.Format("2006-01-02 15:04:05")))
+
+	sb.WriteString("## Services\n\n")
+	sb.WriteString("| Service | API | Version | Resources | Operations |\n")
+	sb.WriteString("|---------|-----|---------|-----------|------------|\n")
+	for _, service := range system.Services {
+		sb.WriteString(fmt.Sprintf("| %s | %s | %s | %d | %d |\n", serviceLink(service.Name), service.Analysis.Title, service.Analysis.Version,
+			service.Analysis.Summary.TotalResources, service.Analysis.Summary.TotalOperations))
+	}
+	sb.WriteString("\n")
+
+	if len(system.Dependencies) > 0 {
+		sb.WriteString("## Service Dependencies\n\n")
+		sb.WriteString("An arrow points from a service to the service that owns the resources it refers to.\n\n")
+		sb.WriteString("```mermaid\n")
+		sb.WriteString(r.generateServiceDiagram(system))
+		sb.WriteString("```\n\n")
+
+		for _, dependency := range system.Dependencies {
+			sb.WriteString(fmt.Sprintf("### %s → %s\n\n", dependency.From, dependency.To))
+			for _, ref := range dependency.References {
+				var sources []string
+				for _, e := range ref.Evidence {
+					sources = append(sources, fmt.Sprintf("`%s`", e.Source))
+				}
+				sb.WriteString(fmt.Sprintf("- %s `%s` → %s %s (%s)\n", ref.Resource, ref.Via, serviceLink(dependency.To), ref.Target, strings.Join(sources, ", ")))
+			}
+			sb.WriteString("\n")
+		}
+	}
+
+	for _, service := range system.Services {
+		report, err := r.generateMarkdown(service.Analysis)
+		if err != nil {
+			return "", fmt.Errorf("service %s: %w", service.Name, err)
+		}
+		title, body, _ := strings.Cut(demoteHeadings(report), "\n")
+
+		sb.WriteString("---\n\n")
+		sb.WriteString(fmt.Sprintf("<a id=\"service-%s\"></a>\n\n", service.Name))
+		sb.WriteString(title + "\n\n")
+		sb.WriteString(fmt.Sprintf("- **Service**: %s\n", service.Name))
+		if service.Source != "" {
+			sb.WriteString(fmt.Sprintf("- **Source**: %s\n", service.Source))
+		}
+		var dependsOn, usedBy []string
+		for _, dependency := range system.Dependencies {
+			if dependency.From == service.Name {
+				dependsOn = append(dependsOn, serviceLink(dependency.To))
+			}
+			if dependency.To == service.Name {
+				usedBy = append(usedBy, serviceLink(dependency.From))
+			}
+		}
+		if len(dependsOn) > 0 {
+			sb.WriteString(fmt.Sprintf("- **Depends on**: %s\n", strings.Join(dependsOn, ", ")))
+		}
+		if len(usedBy) > 0 {
+			sb.WriteString(fmt.Sprintf("- **Used by**: %s\n", strings.Join(usedBy, ", ")))
+		}
+		sb.WriteString(body)
+	}
+
+	return sb.String(), nil
+}
+
+// generateServiceDiagram creates a Mermaid diagram of services linked by the
+// number of cross-service references
+func (r *reporter) generateServiceDiagram(system *models.SystemAnalysis) string {
+	var sb strings.Builder
+	sb.WriteString("graph LR\n")
+
+	for _, service := range system.Services {
+		sb.WriteString(fmt.Sprintf("    %s[\"%s\"]\n", mermaidID(service.Name), service.Name))
+	}
+	for _, dependency := range system.Dependencies {
+		sb.WriteString(fmt.Sprintf("    %s -->|%d| %s\n", mermaidID(dependency.From), len(dependency.References), mermaidID(dependency.To)))
+	}
+
+	return sb.String()
+}
+
+// generateSystemAI creates condensed output: the services and dependencies,
+// then each service's own condensed output
+func (r *reporter) generateSystemAI(system *models.SystemAnalysis) (string, error) {
+	var sb strings.Builder
+
+	sb.WriteString(fmt.Sprintf("SYSTEM: %d services, %d dependencies\n\n", len(system.Services), len(system.Dependencies)))
+
+	sb.WriteString("SERVICES:\n")
+	for _, service := range system.Services {
+		sb.WriteString(fmt.Sprintf("- %s: %s v%s (%d resources)\n", service.Name, service.Analysis.Title, service.Analysis.Version, service.Analysis.Summary.TotalResources))
+	}
+	sb.WriteString("\n")
+
+	if len(system.Dependencies) > 0 {
+		sb.WriteString("DEPENDENCIES:\n")
+		for _, dependency := range system.Dependencies {
+			var refs []string
+			for _, ref := range dependency.References {
+				refs = append(refs, fmt.Sprintf("%s.%s->%s", ref.Resource, ref.Via, ref.Target))
+			}
+			sb.WriteString(fmt.Sprintf("- %s -> %s: %s\n", dependency.From, dependency.To, strings.Join(refs, ", ")))
+		}
+		sb.WriteString("\n")
+	}
+
+	for _, service := range system.Services {
+		output, err := r.generateAIOptimized(service.Analysis)
+		if err != nil {
+			return "", fmt.Errorf("service %s: %w", service.Name, err)
+		}
+		sb.WriteString(fmt.Sprintf("SERVICE %s:\n", service.Name))
+		sb.WriteString(output)
+		sb.WriteString("\n")
+	}
+
+	return sb.String(), nil
+}
+
+// serviceLink links to a service's section of the combined report
+func serviceLink(name string) string {
+	return fmt.Sprintf("[%s](#service-%s)", name, name)
+}
+
+// demoteHeadings moves every markdown heading outside code blocks one level
+// down so a report can be embedded under another
+func demoteHeadings(markdown string) string {
+	lines := strings.Split(markdown, "\n")
+	fenced := false
+	for i, line := range lines {
+		if strings.HasPrefix(line, "```") {
+			fenced = !fenced
+		}
+		if !fenced && strings.HasPrefix(line, "#") {
+			lines[i] = "#" + line
+		}
+	}
+	return strings.Join(lines, "\n")
+}
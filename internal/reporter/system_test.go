@@ -0,0 +1,75 @@
+package reporter
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/orchard9/api-godoc/pkg/models"
+)
+
+func TestGenerateSystem(t *testing.T) {
+	system := &models.SystemAnalysis{
+		Services: []models.Service{
+			{Name: "billing", Source: "specs/billing.yaml", Analysis: &models.APIAnalysis{
+				Title: "Billing API", Version: "1.0.0",
+				Summary:   models.AnalysisStat{TotalResources: 1, TotalOperations: 2},
+				Resources: []models.Resource{{Name: "billing-accounts"}},
+			}},
+			{Name: "invoicing", Analysis: &models.APIAnalysis{
+				Title: "Invoicing API", Version: "2.0.0",
+				Summary:   models.AnalysisStat{TotalResources: 1, TotalOperations: 1},
+				Resources: []models.Resource{{Name: "invoices"}},
+			}},
+		},
+		Dependencies: []models.ServiceDependency{
+			{From: "invoicing", To: "billing", References: []models.CrossReference{
+				{Resource: "invoices", Via: "billingAccountId", Target: "billing-accounts", Evidence: []models.Evidence{
+					{Kind: models.EvidenceNaming, Source: "#/components/schemas/Invoice/properties/billingAccountId", Weight: 0.5},
+				}},
+			}},
+		},
+	}
+
+	tests := []struct {
+		format   string
+		expected []string
+	}{
+		{"markdown", []string{
+			"# System Overview\n",
+			"| [billing](#service-billing) | Billing API | 1.0.0 | 1 | 2 |\n",
+			"    invoicing -->|1| billing\n",
+			"### invoicing → billing\n",
+			"- invoices `billingAccountId` → [billing](#service-billing) billing-accounts (`#/components/schemas/Invoice/properties/billingAccountId`)\n",
+			"<a id=\"service-billing\"></a>\n\n## Billing API\n\n- **Service**: billing\n- **Source**: specs/billing.yaml\n- **Used by**: [invoicing](#service-invoicing)\n",
+			"- **Depends on**: [billing](#service-billing)\n",
+			"### Overview\n",
+		}},
+		{"ai", []string{
+			"SYSTEM: 2 services, 1 dependencies\n",
+			"- billing: Billing API v1.0.0 (1 resources)\n",
+			"- invoicing -> billing: invoices.billingAccountId->billing-accounts\n",
+			"SERVICE invoicing:\nAPI: Invoicing API v2.0.0",
+		}},
+		{"json", []string{`"from": "invoicing"`, `"via": "billingAccountId"`, `"title": "Billing API"`}},
+	}
+
+	for _, tt := range tests {
+		result, err := New().GenerateSystem(system, tt.format)
+		if err != nil {
+			t.Fatalf("GenerateSystem(%s) error = %v", tt.format, err)
+		}
+		for _, content := range tt.expected {
+			if !strings.Contains(result, content) {
+				t.Errorf("Expected %s output to contain %q", tt.format, content)
+			}
+		}
+	}
+}
+
+func TestDemoteHeadings(t *testing.T) {
+	input := "# Title\n\n## Section\n\n```\n# not a heading\n```\n"
+	expected := "## Title\n\n### Section\n\n```\n# not a heading\n```\n"
+	if got := demoteHeadings(input); got != expected {
+		t.Errorf("demoteHeadings() = %q, want %q", got, expected)
+	}
+}
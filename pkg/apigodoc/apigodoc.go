@@ -0,0 +1,192 @@
+// Package apigodoc is the public Go API of api-godoc. It loads OpenAPI and
+// Swagger specifications, analyzes them into resources, relationships and
+// patterns through a staged pipeline, and renders the analysis as markdown, JSON or AI-optimized text.
+//
+//	spec, err := apigodoc.Load(ctx, "openapi.yaml")
+//	if err != nil {
+//		return err
+//	}
+//	analysis, err := apigodoc.Analyze(ctx, spec, apigodoc.WithGrouping(apigodoc.GroupByTag))
+//	if err != nil {
+//		return err
+//	}
+//	return apigodoc.Render(os.Stdout, analysis, apigodoc.FormatMarkdown)
+//
+// The package follows semantic versioning: exported identifiers of this
+// package and of pkg/models are not removed or changed incompatibly within a
+// major version. New options, detectors and fields may be added. Packages
+// under internal/ carry no such guarantee.
+package apigodoc
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/orchard9/api-godoc/internal/analyzer"
+	"github.com/orchard9/api-godoc/internal/parser"
+	"github.com/orchard9/api-godoc/internal/reporter"
+	"github.com/orchard9/api-godoc/pkg/models"
+)
+
+// Spec is a parsed API specification
+type Spec struct {
+	// Source is the file path or URL the specification was loaded from
+	Source string
+
+	spec *parser.OpenAPISpec
+}
+
+// Title returns the API title
+func (s *Spec) Title() string {
+	return s.spec.Info.Title
+}
+
+// Version returns the API version
+func (s *Spec) Version() string {
+	return s.spec.Info.Version
+}
+
+// Load reads and parses a specification from a file path or an http(s) URL.
+// Swagger 2.0 specifications are converted to OpenAPI 3.0.
+func Load(ctx context.Context, source string) (*Spec, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, newError("load", nil, err)
+	}
+
+	p := parser.New()
+	if isURL(source) {
+		data, err := fetch(ctx, source)
+		if err != nil {
+			return nil, err
+		}
+		spec, err := p.Parse(data)
+		if err != nil {
+			return nil, newError("load", ErrInvalidSpec, err)
+		}
+		return &Spec{Source: source, spec: spec}, nil
+	}
+
+	if _, err := os.Stat(source); err != nil {
+		return nil, newError("load", ErrLoad, err)
+	}
+	spec, err := p.ParseFile(source)
+	if err != nil {
+		return nil, newError("load", ErrInvalidSpec, err)
+	}
+	return &Spec{Source: source, spec: spec}, nil
+}
+
+// Parse parses a JSON or YAML specification
+func Parse(data []byte) (*Spec, error) {
+	spec, err := parser.New().Parse(data)
+	if err != nil {
+		return nil, newError("load", ErrInvalidSpec, err)
+	}
+	return &Spec{spec: spec}, nil
+}
+
+// isURL reports whether a source is an http(s) URL
+func isURL(source string) bool {
+	return strings.HasPrefix(source, "http://") || strings.HasPrefix(source, "https://")
+}
+
+// fetch downloads a specification
+func fetch(ctx context.Context, url string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, newError("load", ErrLoad, err)
+	}
+	resp, err := http.DefaultClient.Do(req) // #nosec G107 - caller controls URL
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil, newError("load", nil, ctx.Err())
+		}
+		return nil, newError("load", ErrLoad, fmt.Errorf("failed to fetch URL: %w", err))
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, newError("load", ErrLoad, fmt.Errorf("HTTP error: %s", resp.Status))
+	}
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, newError("load", ErrLoad, fmt.Errorf("failed to read response: %w", err))
+	}
+	return data, nil
+}
+
+// Analyze extracts resources, schemas, relationships and patterns from a
+// specification. The context is checked between analysis stages.
+func Analyze(ctx context.Context, spec *Spec, opts ...Option) (*models.APIAnalysis, error) {
+	if spec == nil || spec.spec == nil {
+		return nil, newError("analyze", ErrInvalidSpec, fmt.Errorf("no specification"))
+	}
+
+	o := defaultOptions()
+	for _, opt := range opts {
+		opt(&o)
+	}
+	if err := o.validate(); err != nil {
+		return nil, newError("analyze", ErrInvalidOption, err)
+	}
+
+	pipeline, err := analyzer.NewPipeline(o.pipelineOptions())
+	if err != nil {
+		return nil, newError("analyze", ErrInvalidOption, err)
+	}
+	for _, hook := range o.hooks {
+		_ = pipeline.AddHook("", func(ctx context.Context, stage string, state *analyzer.State) error {
+			timing := state.Timings[len(state.Timings)-1]
+			return hook(ctx, StageReport{Stage: stage, Duration: timing.Duration, Resources: state.Resources})
+		})
+	}
+
+	analysis, err := pipeline.Run(ctx, &analyzer.State{Spec: spec.spec})
+	if err != nil {
+		return nil, newError("analyze", nil, err)
+	}
+	return analysis, nil
+}
+
+// Format is an output format for Render
+type Format string
+
+// Output formats
+const (
+	FormatMarkdown Format = "markdown"
+	FormatJSON     Format = "json"
+	FormatAI       Format = "ai"
+	FormatText     Format = "text" // graph query answers only
+)
+
+// Render writes an analysis in the given format. An empty format means markdown.
+func Render(w io.Writer, analysis *models.APIAnalysis, format Format) error {
+	format, err := checkFormat(format)
+	if err != nil {
+		return err
+	}
+
+	output, err := reporter.New().Generate(analysis, string(format))
+	if err != nil {
+		return newError("render", nil, err)
+	}
+	if _, err := io.WriteString(w, output); err != nil {
+		return newError("render", nil, err)
+	}
+	return nil
+}
+
+// checkFormat validates an output format, defaulting to markdown
+func checkFormat(format Format) (Format, error) {
+	switch format {
+	case "":
+		return FormatMarkdown, nil
+	case FormatMarkdown, FormatJSON, FormatAI:
+		return format, nil
+	}
+	return "", newError("render", ErrUnsupportedFormat, fmt.Errorf("%s (expected markdown, json or ai)", format))
+}
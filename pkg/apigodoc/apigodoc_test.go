@@ -0,0 +1,438 @@
+package apigodoc
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io/fs"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const testSpec = `{
+	"openapi": "3.0.0",
+	"info": {"title": "Shop", "version": "2.1.0"},
+	"paths": {
+		"/orders": {
+			"get": {"tags": ["orders"], "responses": {"200": {"description": "OK"}}},
+			"post": {"tags": ["orders"], "responses": {"201": {"description": "Created"}}}
+		},
+		"/orders/{id}": {
+			"get": {"tags": ["orders"], "responses": {"200": {"description": "OK"}}}
+		},
+		"/users": {
+			"get": {"tags": ["users"], "responses": {"200": {"description": "OK"}}}
+		}
+	}
+}`
+
+func writeTestSpec(t *testing.T) string {
+	path := filepath.Join(t.TempDir(), "openapi.json")
+	if err := os.WriteFile(path, []byte(testSpec), 0644); err != nil {
+		t.Fatalf("Failed to write spec: %v", err)
+	}
+	return path
+}
+
+func TestLoad(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/openapi.json" {
+			http.NotFound(w, r)
+			return
+		}
+		_, _ = w.Write([]byte(testSpec))
+	}))
+	defer server.Close()
+
+	for _, source := range []string{writeTestSpec(t), server.URL + "/openapi.json"} {
+		spec, err := Load(context.Background(), source)
+		if err != nil {
+			t.Fatalf("Load(%s) error = %v", source, err)
+		}
+		if spec.Title() != "Shop" || spec.Version() != "2.1.0" || spec.Source != source {
+			t.Errorf("Unexpected spec from %s: %s %s %s", source, spec.Title(), spec.Version(), spec.Source)
+		}
+	}
+
+	if _, err := Load(context.Background(), server.URL+"/missing.json"); !errors.Is(err, ErrLoad) {
+		t.Errorf("Expected ErrLoad for a missing URL, got %v", err)
+	}
+}
+
+func TestLoadErrors(t *testing.T) {
+	invalid := filepath.Join(t.TempDir(), "invalid.json")
+	if err := os.WriteFile(invalid, []byte("not: [valid"), 0644); err != nil {
+		t.Fatalf("Failed to write spec: %v", err)
+	}
+	canceled, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	tests := []struct {
+		name   string
+		ctx    context.Context
+		source string
+		kinds  []error
+	}{
+		{"missing file", context.Background(), filepath.Join(t.TempDir(), "missing.json"), []error{ErrLoad, fs.ErrNotExist}},
+		{"invalid spec", context.Background(), invalid, []error{ErrInvalidSpec}},
+		{"canceled", canceled, invalid, []error{context.Canceled}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Load(tt.ctx, tt.source)
+			var apiErr *Error
+			if !errors.As(err, &apiErr) || apiErr.Op != "load" {
+				t.Fatalf("Expected a load *Error, got %v", err)
+			}
+			for _, kind := range tt.kinds {
+				if !errors.Is(err, kind) {
+					t.Errorf("Expected errors.Is(%v, %v)", err, kind)
+				}
+			}
+		})
+	}
+}
+
+func TestAnalyze(t *testing.T) {
+	spec, err := Parse([]byte(testSpec))
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+
+	analysis, err := Analyze(context.Background(), spec)
+	if err != nil {
+		t.Fatalf("Analyze() error = %v", err)
+	}
+	if analysis.Title != "Shop" || analysis.Summary.TotalResources != 2 {
+		t.Errorf("Unexpected analysis: %s with %d resources", analysis.Title, analysis.Summary.TotalResources)
+	}
+	if len(analysis.Capabilities) == 0 {
+		t.Error("Expected a capability matrix by default")
+	}
+
+	var stages []string
+	analysis, err = Analyze(context.Background(), spec,
+		WithStageHook(func(_ context.Context, report StageReport) error {
+			stages = append(stages, report.Stage)
+			return nil
+		}),
+		WithFilter(Filter{Include: []string{"orders"}}),
+		WithGrouping(GroupByTag),
+		WithSchemaLevel(SchemaEssential),
+		WithDetectors(DetectRelationships),
+	)
+	if err != nil {
+		t.Fatalf("Analyze() with options error = %v", err)
+	}
+	if len(analysis.Resources) != 1 || analysis.Resources[0].Name != "orders" {
+		t.Errorf("Expected only orders, got %+v", analysis.Resources)
+	}
+	if analysis.Grouping == nil || analysis.Grouping.Strategy != GroupByTag {
+		t.Errorf("Expected a tag grouping report, got %+v", analysis.Grouping)
+	}
+	if len(analysis.Capabilities) != 0 {
+		t.Error("Expected no capability matrix when the detector is not selected")
+	}
+	if len(stages) == 0 || stages[0] != StageParse || stages[len(stages)-1] != StageSummarize {
+		t.Errorf("Expected a report for every stage, got %v", stages)
+	}
+}
+
+func TestLoadConfig(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "api-godoc.yaml")
+	data := "naming:\n  displayNames:\n    orders: Purchase Orders\ncontexts:\n  - context: sales\n    resources: [orders]\n"
+	if err := os.WriteFile(path, []byte(data), 0600); err != nil {
+		t.Fatalf("Failed to write config: %v", err)
+	}
+
+	cfg, err := LoadConfig(path)
+	if err != nil {
+		t.Fatalf("LoadConfig() error = %v", err)
+	}
+	if len(cfg.Contexts) != 1 || cfg.Contexts[0].Context != "sales" {
+		t.Errorf("Expected the sales context rule, got %+v", cfg.Contexts)
+	}
+
+	spec, err := Parse([]byte(testSpec))
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	analysis, err := Analyze(context.Background(), spec, WithConfig(cfg), WithFilter(Filter{Include: []string{"orders"}}))
+	if err != nil {
+		t.Fatalf("Analyze() error = %v", err)
+	}
+	if len(analysis.Resources) != 1 || analysis.Resources[0].DisplayName != "Purchase Orders" || analysis.Resources[0].Context != "sales" {
+		t.Errorf("Expected the config to apply to orders, got %+v", analysis.Resources)
+	}
+
+	if _, err := LoadConfig(filepath.Join(t.TempDir(), "missing.yaml")); !errors.Is(err, ErrInvalidOption) {
+		t.Errorf("Expected ErrInvalidOption for a missing file, got %v", err)
+	}
+}
+
+func TestAnalyzeErrors(t *testing.T) {
+	spec, err := Parse([]byte(testSpec))
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	canceled, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	tests := []struct {
+		name string
+		ctx  context.Context
+		spec *Spec
+		opts []Option
+		kind error
+	}{
+		{"no spec", context.Background(), nil, nil, ErrInvalidSpec},
+		{"schema level", context.Background(), spec, []Option{WithSchemaLevel("everything")}, ErrInvalidOption},
+		{"detector", context.Background(), spec, []Option{WithDetectors("sentiment")}, ErrInvalidOption},
+		{"grouping", context.Background(), spec, []Option{WithGrouping("bogus")}, ErrInvalidOption},
+		{"filter", context.Background(), spec, []Option{WithFilter(Filter{Pattern: "[invalid"})}, ErrInvalidOption},
+		{"min confidence", context.Background(), spec, []Option{WithMinConfidence(1.5)}, ErrInvalidOption},
+		{"config", context.Background(), spec, []Option{WithConfig(&Config{Contexts: []ContextRule{{Resources: []string{"orders"}}}})}, ErrInvalidOption},
+		{"canceled", canceled, spec, nil, context.Canceled},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Analyze(tt.ctx, tt.spec, tt.opts...)
+			if !errors.Is(err, tt.kind) {
+				t.Errorf("Expected %v, got %v", tt.kind, err)
+			}
+		})
+	}
+}
+
+func TestRender(t *testing.T) {
+	spec, err := Parse([]byte(testSpec))
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	analysis, err := Analyze(context.Background(), spec)
+	if err != nil {
+		t.Fatalf("Analyze() error = %v", err)
+	}
+
+	tests := []struct {
+		format   Format
+		expected string
+	}{
+		{"", "# Shop"},
+		{FormatMarkdown, "# Shop"},
+		{FormatJSON, `"title": "Shop"`},
+		{FormatAI, "Shop"},
+	}
+	for _, tt := range tests {
+		var buf bytes.Buffer
+		if err := Render(&buf, analysis, tt.format); err != nil {
+			t.Fatalf("Render(%q) error = %v", tt.format, err)
+		}
+		if !strings.Contains(buf.String(), tt.expected) {
+			t.Errorf("Render(%q) output missing %q", tt.format, tt.expected)
+		}
+	}
+
+	if err := Render(&bytes.Buffer{}, analysis, "pdf"); !errors.Is(err, ErrUnsupportedFormat) {
+		t.Errorf("Expected ErrUnsupportedFormat, got %v", err)
+	}
+}
+
+func TestAnalyzeServices(t *testing.T) {
+	billing, err := Parse([]byte(`{
+		"openapi": "3.0.0",
+		"info": {"title": "Billing", "version": "1.0.0"},
+		"paths": {
+			"/billing-accounts/{billingAccountId}": {"get": {"responses": {"200": {"description": "OK"}}}}
+		}
+	}`))
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	billing.Source = "specs/billing.yaml"
+	invoicing, err := Parse([]byte(`{
+		"openapi": "3.0.0",
+		"info": {"title": "Invoicing API", "version": "1.0.0"},
+		"paths": {
+			"/invoices": {"get": {"responses": {"200": {"description": "OK"}}}}
+		},
+		"components": {
+			"schemas": {
+				"Invoice": {"type": "object", "properties": {"billingAccountId": {"type": "string"}}}
+			}
+		}
+	}`))
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+
+	system, err := AnalyzeServices(context.Background(), []*Spec{billing, invoicing})
+	if err != nil {
+		t.Fatalf("AnalyzeServices() error = %v", err)
+	}
+	if len(system.Services) != 2 || system.Services[0].Name != "billing" || system.Services[1].Name != "invoicing-api" {
+		t.Errorf("Expected services billing and invoicing-api, got %+v", system.Services)
+	}
+	if len(system.Dependencies) != 1 || system.Dependencies[0].From != "invoicing-api" || system.Dependencies[0].To != "billing" {
+		t.Fatalf("Expected invoicing-api to depend on billing, got %+v", system.Dependencies)
+	}
+	if ref := system.Dependencies[0].References[0]; ref.Resource != "invoices" || ref.Via != "billingAccountId" || ref.Target != "billing-accounts" {
+		t.Errorf("Unexpected reference %+v", ref)
+	}
+
+	var buf bytes.Buffer
+	if err := RenderSystem(&buf, system, FormatMarkdown); err != nil {
+		t.Fatalf("RenderSystem() error = %v", err)
+	}
+	if !strings.Contains(buf.String(), "invoicing_api -->|1| billing") {
+		t.Errorf("Expected a service dependency diagram, got:\n%s", buf.String())
+	}
+	if err := RenderSystem(&bytes.Buffer{}, system, "pdf"); !errors.Is(err, ErrUnsupportedFormat) {
+		t.Errorf("Expected ErrUnsupportedFormat, got %v", err)
+	}
+
+	if _, err := AnalyzeServices(context.Background(), nil); !errors.Is(err, ErrInvalidSpec) {
+		t.Errorf("Expected ErrInvalidSpec without specifications, got %v", err)
+	}
+	_, err = AnalyzeServices(context.Background(), []*Spec{billing}, WithGrouping("bogus"))
+	if !errors.Is(err, ErrInvalidOption) || !strings.Contains(err.Error(), "service billing") {
+		t.Errorf("Expected ErrInvalidOption naming the service, got %v", err)
+	}
+}
+
+func TestExportGraph(t *testing.T) {
+	spec, err := Parse([]byte(testSpec))
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	analysis, err := Analyze(context.Background(), spec)
+	if err != nil {
+		t.Fatalf("Analyze() error = %v", err)
+	}
+
+	tests := []struct {
+		format   GraphFormat
+		cluster  string
+		expected string
+	}{
+		{GraphDOT, "", `subgraph "cluster_core"`},
+		{GraphPlantUML, ClusterTag, `package "orders"`},
+		{GraphD2, ClusterNone, `"orders": {`},
+		{GraphML, "", `<graphml xmlns="http://graphml.graphdrawing.org/xmlns">`},
+		{GraphCytoscape, "", `"elements"`},
+		{GraphMermaidER, "", `orders["Orders"]`},
+		{GraphMermaidClass, "", `class users["Users"]`},
+	}
+	for _, tt := range tests {
+		var buf bytes.Buffer
+		if err := ExportGraph(&buf, analysis, tt.format, tt.cluster); err != nil {
+			t.Fatalf("ExportGraph(%s) error = %v", tt.format, err)
+		}
+		if !strings.Contains(buf.String(), tt.expected) {
+			t.Errorf("ExportGraph(%s) output missing %q:\n%s", tt.format, tt.expected, buf.String())
+		}
+	}
+
+	if err := ExportGraph(&bytes.Buffer{}, analysis, "svg", ""); !errors.Is(err, ErrUnsupportedFormat) {
+		t.Errorf("Expected ErrUnsupportedFormat, got %v", err)
+	}
+	if err := ExportGraph(&bytes.Buffer{}, analysis, GraphDOT, "owner"); !errors.Is(err, ErrInvalidOption) {
+		t.Errorf("Expected ErrInvalidOption, got %v", err)
+	}
+
+	var buf bytes.Buffer
+	if err := ExportGraph(&buf, analysis, GraphMermaidClass, "", WithFocus("Order", 0), WithMaxFields(2)); err != nil {
+		t.Fatalf("ExportGraph() error = %v", err)
+	}
+	if output := buf.String(); !strings.Contains(output, "class orders") || strings.Contains(output, "users") {
+		t.Errorf("Expected only orders with the focus on Order:\n%s", output)
+	}
+	for _, opt := range []GraphOption{WithFocus("payments", 1), WithFocus("orders", -1), WithMaxNodes(-1)} {
+		if err := ExportGraph(&bytes.Buffer{}, analysis, GraphDOT, "", opt); !errors.Is(err, ErrInvalidOption) {
+			t.Errorf("Expected ErrInvalidOption, got %v", err)
+		}
+	}
+}
+
+func TestQueryGraph(t *testing.T) {
+	spec, err := Parse([]byte(`{
+		"openapi": "3.0.0",
+		"info": {"title": "Shop", "version": "1.0.0"},
+		"paths": {
+			"/customers/{customerId}": {"get": {"responses": {"200": {"description": "OK"}}}},
+			"/customers/{customerId}/orders": {"get": {"responses": {"200": {"description": "OK"}}}},
+			"/tags": {"get": {"responses": {"200": {"description": "OK"}}}}
+		}
+	}`))
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	analysis, err := Analyze(context.Background(), spec)
+	if err != nil {
+		t.Fatalf("Analyze() error = %v", err)
+	}
+
+	result, err := QueryGraph(analysis, GraphQuery{From: "Customer", To: "order", Orphans: true, Cycles: true})
+	if err != nil {
+		t.Fatalf("QueryGraph() error = %v", err)
+	}
+	if len(result.Paths) != 1 || strings.Join(result.Paths[0].Calls, ", ") != "GET /customers/{customerId}, GET /customers/{customerId}/orders" {
+		t.Errorf("Expected the call chain from customers to orders, got %+v", result.Paths)
+	}
+
+	tests := []struct {
+		format   Format
+		expected string
+	}{
+		{"", "ORPHAN RESOURCES:\n- tags\n"},
+		{FormatText, "SHORTEST PATHS:\n  customers -[has_many]-> orders\n"},
+		{FormatText, "DEPENDENCY CYCLES:\n  (none)\n"},
+		{FormatJSON, `"orphans": [`},
+	}
+	for _, tt := range tests {
+		var buf bytes.Buffer
+		if err := RenderGraphQuery(&buf, result, tt.format); err != nil {
+			t.Fatalf("RenderGraphQuery(%q) error = %v", tt.format, err)
+		}
+		if !strings.Contains(buf.String(), tt.expected) {
+			t.Errorf("RenderGraphQuery(%q) output missing %q:\n%s", tt.format, tt.expected, buf.String())
+		}
+	}
+
+	if err := RenderGraphQuery(&bytes.Buffer{}, result, FormatMarkdown); !errors.Is(err, ErrUnsupportedFormat) {
+		t.Errorf("Expected ErrUnsupportedFormat, got %v", err)
+	}
+	if _, err := QueryGraph(analysis, GraphQuery{Dependents: "payments"}); !errors.Is(err, ErrInvalidOption) {
+		t.Errorf("Expected ErrInvalidOption for an unknown resource, got %v", err)
+	}
+}
+
+func TestConvert(t *testing.T) {
+	conversion, err := Convert(context.Background(), []byte(testSpec), Version31)
+	if err != nil {
+		t.Fatalf("Convert() error = %v", err)
+	}
+	output, err := conversion.Marshal("json")
+	if err != nil {
+		t.Fatalf("Marshal() error = %v", err)
+	}
+	if !strings.Contains(string(output), `"openapi": "3.1.0"`) {
+		t.Errorf("Expected an OpenAPI 3.1 document, got %s", output)
+	}
+
+	if _, err := conversion.Marshal("xml"); !errors.Is(err, ErrUnsupportedFormat) {
+		t.Errorf("Expected ErrUnsupportedFormat for xml, got %v", err)
+	}
+	if _, err := Convert(context.Background(), []byte(testSpec), "4.0"); !errors.Is(err, ErrUnsupportedFormat) {
+		t.Errorf("Expected ErrUnsupportedFormat for 4.0, got %v", err)
+	}
+	if _, err := Convert(context.Background(), []byte("not: [valid"), Version30); !errors.Is(err, ErrInvalidSpec) {
+		t.Errorf("Expected ErrInvalidSpec, got %v", err)
+	}
+}
@@ -0,0 +1,73 @@
+package apigodoc
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/orchard9/api-godoc/internal/converter"
+)
+
+// Target versions for Convert
+const (
+	Version20 = converter.Version20
+	Version30 = converter.Version30
+	Version31 = converter.Version31
+)
+
+// Conversion is a specification converted to another OpenAPI version
+type Conversion struct {
+	// Warnings lists constructs that could not be converted exactly
+	Warnings []string
+
+	doc   map[string]interface{}
+	order *converter.KeyOrder
+}
+
+// Convert converts a JSON or YAML specification to OpenAPI 2.0, 3.0 or 3.1
+func Convert(ctx context.Context, data []byte, target string) (*Conversion, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, newError("convert", nil, err)
+	}
+	switch target {
+	case Version20, Version30, Version31:
+	default:
+		return nil, newError("convert", ErrUnsupportedFormat, fmt.Errorf("target version %s (expected 2.0, 3.0 or 3.1)", target))
+	}
+
+	c := converter.NewVersionConverter()
+	doc, err := c.ConvertVersion(data, target)
+	if err != nil {
+		return nil, newError("convert", ErrInvalidSpec, err)
+	}
+	return &Conversion{Warnings: c.Warnings(), doc: doc, order: converter.ReadKeyOrder(data)}, nil
+}
+
+// Marshal encodes the converted specification as "json" or "yaml". Paths,
+// properties and other keys keep their input order; keys the conversion
+// added follow a stable conventional order.
+func (c *Conversion) Marshal(format string) ([]byte, error) {
+	if format != "json" && format != "yaml" {
+		return nil, newError("convert", ErrUnsupportedFormat, fmt.Errorf("%s (expected json or yaml)", format))
+	}
+	output, err := converter.Marshal(c.doc, format, c.order)
+	if err != nil {
+		return nil, newError("convert", nil, err)
+	}
+	return output, nil
+}
+
+// Read returns the raw bytes of a specification file or http(s) URL
+func Read(ctx context.Context, source string) ([]byte, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, newError("load", nil, err)
+	}
+	if isURL(source) {
+		return fetch(ctx, source)
+	}
+	data, err := os.ReadFile(source) // #nosec G304 - caller controls file path
+	if err != nil {
+		return nil, newError("load", ErrLoad, err)
+	}
+	return data, nil
+}
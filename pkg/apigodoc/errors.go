@@ -0,0 +1,53 @@
+package apigodoc
+
+import "errors"
+
+// Error kinds, matched with errors.Is
+var (
+	// ErrLoad means a specification could not be read or fetched
+	ErrLoad = errors.New("cannot load specification")
+	// ErrInvalidSpec means a specification could not be parsed or converted
+	ErrInvalidSpec = errors.New("invalid specification")
+	// ErrInvalidOption means an option or configuration value is invalid
+	ErrInvalidOption = errors.New("invalid option")
+	// ErrUnsupportedFormat means an output format or target version is not supported
+	ErrUnsupportedFormat = errors.New("unsupported format")
+)
+
+// Error is the error type returned by every function in this package.
+// errors.Is matches both its Kind and the underlying cause, so callers can
+// test for ErrLoad as well as fs.ErrNotExist or context.Canceled.
+type Error struct {
+	Op   string // load, analyze, query, render or convert
+	Kind error  // one of the Err values above; nil when the context was done
+	Err  error  // underlying cause
+}
+
+// newError creates an Error
+func newError(op string, kind, err error) *Error {
+	return &Error{Op: op, Kind: kind, Err: err}
+}
+
+// Error implements the error interface
+func (e *Error) Error() string {
+	msg := "apigodoc: " + e.Op
+	if e.Kind != nil {
+		msg += ": " + e.Kind.Error()
+	}
+	if e.Err != nil {
+		msg += ": " + e.Err.Error()
+	}
+	return msg
+}
+
+// Unwrap returns the kind and the cause
+func (e *Error) Unwrap() []error {
+	var errs []error
+	if e.Kind != nil {
+		errs = append(errs, e.Kind)
+	}
+	if e.Err != nil {
+		errs = append(errs, e.Err)
+	}
+	return errs
+}
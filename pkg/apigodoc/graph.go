@@ -0,0 +1,252 @@
+package apigodoc
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+
+	"github.com/orchard9/api-godoc/internal/graph"
+	"github.com/orchard9/api-godoc/pkg/models"
+)
+
+// GraphFormat is an export format for the resource relationship graph
+type GraphFormat string
+
+// Graph export formats
+const (
+	GraphDOT       GraphFormat = graph.FormatDOT
+	GraphPlantUML  GraphFormat = graph.FormatPlantUML
+	GraphD2        GraphFormat = graph.FormatD2
+	GraphML        GraphFormat = graph.FormatGraphML
+	GraphCytoscape GraphFormat = graph.FormatCytoscape
+
+	GraphMermaidER    GraphFormat = graph.FormatMermaidER
+	GraphMermaidClass GraphFormat = graph.FormatMermaidClass
+)
+
+// Graph cluster modes
+const (
+	ClusterCategory = graph.ClusterCategory
+	ClusterTag      = graph.ClusterTag
+	ClusterNone     = graph.ClusterNone
+)
+
+// GraphOption sizes an exported graph
+type GraphOption func(*graphOptions)
+
+// graphOptions holds the settings of one graph export
+type graphOptions struct {
+	focus     string
+	hops      int
+	maxNodes  int
+	maxFields int
+}
+
+// WithFocus keeps a resource and the resources within hops relationships of
+// it. The resource may be named by name or display name.
+func WithFocus(resource string, hops int) GraphOption {
+	return func(o *graphOptions) { o.focus, o.hops = resource, hops }
+}
+
+// WithMaxNodes keeps at most n resources, the nearest to the focus resource
+// first and then the most connected. Zero keeps every resource.
+func WithMaxNodes(n int) GraphOption {
+	return func(o *graphOptions) { o.maxNodes = n }
+}
+
+// WithMaxFields keeps at most n fields per resource in formats that show
+// fields, the identifier and relationship fields first. Zero keeps every
+// field.
+func WithMaxFields(n int) GraphOption {
+	return func(o *graphOptions) { o.maxFields = n }
+}
+
+// ExportGraph writes the resource relationship graph of an analysis for
+// graph tools. Nodes carry the operation count, category and tag of their
+// resource, edges the relationship type, cardinality and confidence; the
+// Mermaid formats show the fields of each resource as well. Resources are
+// clustered by category, by tag or, with ClusterNone, not at all; an empty
+// cluster mode means by category.
+func ExportGraph(w io.Writer, analysis *models.APIAnalysis, format GraphFormat, cluster string, opts ...GraphOption) error {
+	if !slices.Contains(graph.Formats, string(format)) {
+		return newError("render", ErrUnsupportedFormat, fmt.Errorf("%s (expected %s)", format, strings.Join(graph.Formats, ", ")))
+	}
+	if cluster == "" {
+		cluster = ClusterCategory
+	}
+	var o graphOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+	if o.maxNodes < 0 || o.maxFields < 0 {
+		return newError("render", ErrInvalidOption, fmt.Errorf("node and field limits must not be negative"))
+	}
+
+	g, err := graph.New(analysis, cluster)
+	if err != nil {
+		return newError("render", ErrInvalidOption, err)
+	}
+	if o.focus != "" {
+		if err := g.Focus(o.focus, o.hops); err != nil {
+			return newError("render", ErrInvalidOption, err)
+		}
+	}
+	g.Limit(o.maxNodes)
+	g.LimitFields(o.maxFields)
+	output, err := graph.Export(g, string(format))
+	if err != nil {
+		return newError("render", nil, err)
+	}
+	if _, err := io.WriteString(w, output); err != nil {
+		return newError("render", nil, err)
+	}
+	return nil
+}
+
+// GraphQuery selects the questions QueryGraph answers: the shortest paths
+// between two resources, the resources that depend on one, dependency
+// cycles, orphan resources and the most connected resources
+type GraphQuery struct {
+	From       string // shortest paths from this resource...
+	To         string // ...to this one
+	Dependents string // resources that depend on this one, directly or not
+	Cycles     bool   // dependency cycles
+	Orphans    bool   // resources without relationships
+	Degrees    int    // the most connected resources, this many
+}
+
+// GraphQueryResult holds the answers to a GraphQuery; unasked questions are
+// nil. Every path lists the calls that walk it, from a read of its first
+// resource on.
+type GraphQueryResult struct {
+	Paths      []GraphPath      `json:"paths,omitzero"`
+	Dependents []GraphDependent `json:"dependents,omitzero"`
+	Cycles     []GraphPath      `json:"cycles,omitzero"`
+	Orphans    []string         `json:"orphans,omitzero"`
+	Degrees    []GraphDegree    `json:"degrees,omitzero"`
+}
+
+// GraphPath is a walk through resources with the calls that follow it
+type GraphPath struct {
+	Resources []string    `json:"resources"`
+	Edges     []GraphEdge `json:"edges"`
+	Calls     []string    `json:"calls"` // e.g. GET /orgs/{orgId}, GET /orgs/{orgId}/invoices
+}
+
+// GraphEdge is a relationship walked by a GraphPath
+type GraphEdge struct {
+	From        string  `json:"from"`
+	To          string  `json:"to"`
+	Type        string  `json:"type"` // has_many, belongs_to, references, ...
+	Via         string  `json:"via,omitempty"`
+	Cardinality string  `json:"cardinality,omitempty"`
+	Confidence  float64 `json:"confidence"`
+	Operation   string  `json:"operation,omitempty"` // call that follows the edge, e.g. GET /users/{userId}/posts
+}
+
+// GraphDependent is a resource that depends on another through a path of
+// dependency relationships
+type GraphDependent struct {
+	Resource string    `json:"resource"`
+	Depth    int       `json:"depth"` // 1 for direct dependents
+	Path     GraphPath `json:"path"`
+}
+
+// GraphDegree counts the resources a resource is connected to
+type GraphDegree struct {
+	Resource     string `json:"resource"`
+	Dependents   int    `json:"dependents"`   // resources that depend on it
+	Dependencies int    `json:"dependencies"` // resources it depends on
+	Neighbors    int    `json:"neighbors"`    // resources related in either direction
+}
+
+// QueryGraph answers questions about the resource relationship graph of an
+// analysis. Resources may be named by name or display name in any case or
+// number, e.g. Organization for organizations.
+func QueryGraph(analysis *models.APIAnalysis, query GraphQuery) (*GraphQueryResult, error) {
+	g, err := graph.New(analysis, ClusterNone)
+	if err != nil {
+		return nil, newError("query", nil, err)
+	}
+	result, err := g.Run(graph.Query(query))
+	if err != nil {
+		return nil, newError("query", ErrInvalidOption, err)
+	}
+	return &GraphQueryResult{
+		Paths: convertAll(result.Paths, fromGraphPath),
+		Dependents: convertAll(result.Dependents, func(d graph.Dependent) GraphDependent {
+			return GraphDependent{Resource: d.Resource, Depth: d.Depth, Path: fromGraphPath(d.Path)}
+		}),
+		Cycles:  convertAll(result.Cycles, fromGraphPath),
+		Orphans: result.Orphans,
+		Degrees: convertAll(result.Degrees, func(d graph.Degree) GraphDegree { return GraphDegree(d) }),
+	}, nil
+}
+
+// RenderGraphQuery writes graph query answers as text or JSON. An empty
+// format means text.
+func RenderGraphQuery(w io.Writer, result *GraphQueryResult, format Format) error {
+	var output string
+	switch format {
+	case "", FormatText:
+		output = result.internal().Text()
+	case FormatJSON:
+		data, err := json.MarshalIndent(result, "", "  ")
+		if err != nil {
+			return newError("render", nil, err)
+		}
+		output = string(data) + "\n"
+	default:
+		return newError("render", ErrUnsupportedFormat, fmt.Errorf("%s (expected text or json)", format))
+	}
+	if _, err := io.WriteString(w, output); err != nil {
+		return newError("render", nil, err)
+	}
+	return nil
+}
+
+// internal converts query answers back for the graph package's text output
+func (r *GraphQueryResult) internal() *graph.QueryResult {
+	return &graph.QueryResult{
+		Paths: convertAll(r.Paths, toGraphPath),
+		Dependents: convertAll(r.Dependents, func(d GraphDependent) graph.Dependent {
+			return graph.Dependent{Resource: d.Resource, Depth: d.Depth, Path: toGraphPath(d.Path)}
+		}),
+		Cycles:  convertAll(r.Cycles, toGraphPath),
+		Orphans: r.Orphans,
+		Degrees: convertAll(r.Degrees, func(d GraphDegree) graph.Degree { return graph.Degree(d) }),
+	}
+}
+
+// fromGraphPath converts a path found by the graph package
+func fromGraphPath(p graph.Path) GraphPath {
+	return GraphPath{
+		Resources: p.Resources,
+		Edges:     convertAll(p.Edges, func(e graph.Edge) GraphEdge { return GraphEdge(e) }),
+		Calls:     p.Calls,
+	}
+}
+
+// toGraphPath converts a path for the graph package
+func toGraphPath(p GraphPath) graph.Path {
+	return graph.Path{
+		Resources: p.Resources,
+		Edges:     convertAll(p.Edges, func(e GraphEdge) graph.Edge { return graph.Edge(e) }),
+		Calls:     p.Calls,
+	}
+}
+
+// convertAll converts every element of a slice, keeping nil slices nil so
+// that unasked questions stay apart from empty answers
+func convertAll[T, U any](items []T, convert func(T) U) []U {
+	if items == nil {
+		return nil
+	}
+	result := make([]U, len(items))
+	for i, item := range items {
+		result[i] = convert(item)
+	}
+	return result
+}
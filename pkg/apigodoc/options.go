@@ -0,0 +1,287 @@
+package apigodoc
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/orchard9/api-godoc/internal/analyzer"
+	"github.com/orchard9/api-godoc/internal/config"
+	"github.com/orchard9/api-godoc/pkg/models"
+)
+
+// Config holds project settings that override built-in heuristics: resource
+// naming, path segment rules, category rules and pinned contexts. It has the
+// same YAML layout as the CLI's --config file.
+type Config struct {
+	Naming     NamingConfig   `yaml:"naming"`
+	Segments   SegmentConfig  `yaml:"segments"`
+	Categories []CategoryRule `yaml:"categories"`
+	Contexts   []ContextRule  `yaml:"contexts"`
+}
+
+// NamingConfig overrides how resource names are singularized, merged and displayed
+type NamingConfig struct {
+	Irregular    map[string]string `yaml:"irregular"`    // singular -> plural, e.g. person: people
+	Uncountable  []string          `yaml:"uncountable"`  // words with no plural form, e.g. metadata
+	Aliases      map[string]string `yaml:"aliases"`      // alias -> canonical resource name
+	DisplayNames map[string]string `yaml:"displayNames"` // canonical resource name -> display name
+}
+
+// SegmentConfig controls which path segments are treated as resources
+type SegmentConfig struct {
+	Skip          []string `yaml:"skip"`          // regular expressions matching whole segments that are never resources
+	StripPrefixes []string `yaml:"stripPrefixes"` // path prefixes ignored during analysis, e.g. /tenants/{tenant}
+}
+
+// CategoryRule assigns a category to the resources it matches
+type CategoryRule struct {
+	Category  string   `yaml:"category"`
+	Resources []string `yaml:"resources"` // regular expressions matching whole resource names
+	Paths     []string `yaml:"paths"`     // path prefixes, e.g. /admin
+	Tags      []string `yaml:"tags"`      // operation tags
+}
+
+// ContextRule pins the resources it matches to a bounded context
+type ContextRule struct {
+	Context   string   `yaml:"context"`
+	Resources []string `yaml:"resources"` // regular expressions matching whole resource names
+	Paths     []string `yaml:"paths"`     // path prefixes, e.g. /billing
+	Tags      []string `yaml:"tags"`      // operation tags
+}
+
+// LoadConfig reads a YAML configuration file
+func LoadConfig(path string) (*Config, error) {
+	cfg, err := config.Load(path)
+	if err != nil {
+		return nil, newError("load", ErrInvalidOption, err)
+	}
+	return fromInternalConfig(cfg), nil
+}
+
+// fromInternalConfig converts a loaded configuration to the public type
+func fromInternalConfig(cfg *config.Config) *Config {
+	result := &Config{
+		Naming:   NamingConfig(cfg.Naming),
+		Segments: SegmentConfig(cfg.Segments),
+	}
+	for _, rule := range cfg.Categories {
+		result.Categories = append(result.Categories, CategoryRule(rule))
+	}
+	for _, rule := range cfg.Contexts {
+		result.Contexts = append(result.Contexts, ContextRule(rule))
+	}
+	return result
+}
+
+// toInternalConfig converts a configuration for the analysis pipeline
+func toInternalConfig(cfg *Config) *config.Config {
+	result := &config.Config{
+		Naming:   config.NamingConfig(cfg.Naming),
+		Segments: config.SegmentConfig(cfg.Segments),
+	}
+	for _, rule := range cfg.Categories {
+		result.Categories = append(result.Categories, config.CategoryRule(rule))
+	}
+	for _, rule := range cfg.Contexts {
+		result.Contexts = append(result.Contexts, config.ContextRule(rule))
+	}
+	return result
+}
+
+// SchemaLevel controls how many fields of each resource schema are reported
+type SchemaLevel string
+
+// Schema levels
+const (
+	SchemaEssential SchemaLevel = "essential" // identifiers, names and required fields
+	SchemaStandard  SchemaLevel = "standard"  // the default
+	SchemaFull      SchemaLevel = "full"      // every field
+)
+
+// Grouping modes decide which operations form a resource
+const (
+	GroupByPath        = analyzer.GroupByPath
+	GroupByTag         = analyzer.GroupByTag
+	GroupByOperationID = analyzer.GroupByOperationID
+	GroupByExtension   = analyzer.GroupByExtension
+	GroupByHybrid      = analyzer.GroupByHybrid
+
+	// DefaultGroupExtension is the vendor extension read by extension grouping
+	DefaultGroupExtension = analyzer.DefaultResourceExtension
+)
+
+// Detector names an optional analysis step
+type Detector string
+
+// Detectors
+const (
+	DetectRelationships Detector = analyzer.DetectorRelationships // links between resources
+	DetectPatterns      Detector = analyzer.DetectorPatterns      // pagination, authentication and other API patterns
+	DetectLifecycles    Detector = analyzer.DetectorLifecycles    // state machines from status enums and actions
+	DetectContexts      Detector = analyzer.DetectorContexts      // bounded contexts
+	DetectCapabilities  Detector = analyzer.DetectorCapabilities  // capability matrix and gaps
+)
+
+// Analysis stages, in run order
+const (
+	StageParse         = analyzer.StageParse
+	StageNormalize     = analyzer.StageNormalize
+	StageExtract       = analyzer.StageExtract
+	StageFilter        = analyzer.StageFilter
+	StageFields        = analyzer.StageFields
+	StageRelationships = analyzer.StageRelationships
+	StagePatterns      = analyzer.StagePatterns
+	StageSummarize     = analyzer.StageSummarize
+)
+
+// StageReport describes a completed analysis stage
+type StageReport struct {
+	Stage     string
+	Duration  time.Duration
+	Resources []models.Resource // resources as of this stage
+}
+
+// StageHook is called after each analysis stage; an error stops the analysis
+type StageHook func(ctx context.Context, report StageReport) error
+
+// Filter selects the resources to analyze. Empty fields do not filter.
+type Filter struct {
+	Include           []string // resource names to keep
+	Exclude           []string // resource names to drop
+	Pattern           string   // regular expression matching resource names
+	Incomplete        bool     // only keep resources with capability gaps
+	IncludeCategories []string // only keep resources in these categories
+	ExcludeCategories []string // drop resources in these categories
+}
+
+// isEmpty reports whether the filter keeps every resource
+func (f Filter) isEmpty() bool {
+	return len(f.Include) == 0 && len(f.Exclude) == 0 && f.Pattern == "" && !f.Incomplete &&
+		len(f.IncludeCategories) == 0 && len(f.ExcludeCategories) == 0
+}
+
+// Option configures Analyze
+type Option func(*options)
+
+// options holds the settings of one analysis
+type options struct {
+	schemaLevel    SchemaLevel
+	filter         Filter
+	groupBy        string
+	groupExtension string
+	separateNested bool
+	config         *config.Config
+	detectors      []Detector // nil runs every detector
+	minConfidence  float64
+	hooks          []StageHook
+}
+
+// defaultOptions returns the settings used when no option is given
+func defaultOptions() options {
+	return options{
+		schemaLevel: SchemaStandard,
+		groupBy:     GroupByPath,
+		config:      &config.Config{},
+	}
+}
+
+// WithSchemaLevel sets how many fields of each resource schema are reported
+func WithSchemaLevel(level SchemaLevel) Option {
+	return func(o *options) { o.schemaLevel = level }
+}
+
+// WithFilter keeps only the resources selected by a filter
+func WithFilter(filter Filter) Option {
+	return func(o *options) { o.filter = filter }
+}
+
+// WithGrouping sets how operations are grouped into resources: path, tag,
+// operationId, extension or hybrid
+func WithGrouping(mode string) Option {
+	return func(o *options) { o.groupBy = mode }
+}
+
+// WithGroupExtension sets the vendor extension read by extension grouping
+func WithGroupExtension(name string) Option {
+	return func(o *options) { o.groupExtension = name }
+}
+
+// WithSeparateNested keeps nested resources such as /features/{id}/tasks
+// apart from top-level resources with the same name
+func WithSeparateNested(separate bool) Option {
+	return func(o *options) { o.separateNested = separate }
+}
+
+// WithConfig applies project settings; a nil config restores the defaults
+func WithConfig(cfg *Config) Option {
+	return func(o *options) {
+		if cfg == nil {
+			cfg = &Config{}
+		}
+		o.config = toInternalConfig(cfg)
+	}
+}
+
+// WithDetectors runs only the given optional analysis steps
+func WithDetectors(detectors ...Detector) Option {
+	return func(o *options) { o.detectors = append([]Detector{}, detectors...) }
+}
+
+// WithMinConfidence drops relationships whose confidence, from 0 to 1, is
+// below a threshold
+func WithMinConfidence(confidence float64) Option {
+	return func(o *options) { o.minConfidence = confidence }
+}
+
+// WithStageHook calls a hook after each analysis stage
+func WithStageHook(hook StageHook) Option {
+	return func(o *options) { o.hooks = append(o.hooks, hook) }
+}
+
+// WithLogger logs each analysis stage with its duration
+func WithLogger(logger *log.Logger) Option {
+	return WithStageHook(func(_ context.Context, report StageReport) error {
+		logger.Printf("Stage %s finished in %s (%d resources)", report.Stage, report.Duration, len(report.Resources))
+		return nil
+	})
+}
+
+// validate checks option values that the pipeline does not check
+func (o options) validate() error {
+	switch o.schemaLevel {
+	case "", SchemaEssential, SchemaStandard, SchemaFull:
+		return nil
+	}
+	return fmt.Errorf("unknown schema level: %s (expected essential, standard or full)", o.schemaLevel)
+}
+
+// pipelineOptions converts the options for the analysis pipeline
+func (o options) pipelineOptions() analyzer.PipelineOptions {
+	opts := analyzer.PipelineOptions{
+		Config:         o.config,
+		GroupBy:        o.groupBy,
+		GroupExtension: o.groupExtension,
+		SeparateNested: o.separateNested,
+		SchemaLevel:    string(o.schemaLevel),
+		MinConfidence:  o.minConfidence,
+	}
+	if !o.filter.isEmpty() {
+		opts.Filter = &analyzer.ResourceFilter{
+			Include:           o.filter.Include,
+			Exclude:           o.filter.Exclude,
+			Pattern:           o.filter.Pattern,
+			Incomplete:        o.filter.Incomplete,
+			IncludeCategories: o.filter.IncludeCategories,
+			ExcludeCategories: o.filter.ExcludeCategories,
+		}
+	}
+	if o.detectors != nil {
+		opts.Detectors = []string{}
+		for _, d := range o.detectors {
+			opts.Detectors = append(opts.Detectors, string(d))
+		}
+	}
+	return opts
+}
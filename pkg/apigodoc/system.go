@@ -0,0 +1,91 @@
+package apigodoc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/orchard9/api-godoc/internal/analyzer"
+	"github.com/orchard9/api-godoc/internal/reporter"
+	"github.com/orchard9/api-godoc/pkg/models"
+)
+
+// AnalyzeServices analyzes each specification as its own service with the
+// same options, then finds the fields and parameters of one service that
+// identify resources another service owns. Services are named after their
+// source file, e.g. billing for specs/billing.yaml, or their title.
+func AnalyzeServices(ctx context.Context, specs []*Spec, opts ...Option) (*models.SystemAnalysis, error) {
+	if len(specs) == 0 {
+		return nil, newError("analyze", ErrInvalidSpec, fmt.Errorf("no specifications"))
+	}
+
+	o := defaultOptions()
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	system := &models.SystemAnalysis{GeneratedAt: time.Now()}
+	var services []analyzer.ServiceSpec
+	for i, name := range serviceNames(specs) {
+		analysis, err := Analyze(ctx, specs[i], opts...)
+		if err != nil {
+			var e *Error
+			if errors.As(err, &e) {
+				e.Err = fmt.Errorf("service %s: %w", name, e.Err)
+			}
+			return nil, err
+		}
+		system.Services = append(system.Services, models.Service{Name: name, Source: specs[i].Source, Analysis: analysis})
+		services = append(services, analyzer.ServiceSpec{Name: name, Spec: specs[i].spec, Analysis: analysis})
+	}
+
+	detector := analyzer.NewRelationshipDetector()
+	detector.SetNameNormalizer(analyzer.NewNameNormalizer(o.config.Naming))
+	system.Dependencies = detector.DetectServiceDependencies(services)
+	return system, nil
+}
+
+// RenderSystem writes a multi-service analysis in the given format. An
+// empty format means markdown.
+func RenderSystem(w io.Writer, system *models.SystemAnalysis, format Format) error {
+	format, err := checkFormat(format)
+	if err != nil {
+		return err
+	}
+
+	output, err := reporter.New().GenerateSystem(system, string(format))
+	if err != nil {
+		return newError("render", nil, err)
+	}
+	if _, err := io.WriteString(w, output); err != nil {
+		return newError("render", nil, err)
+	}
+	return nil
+}
+
+// serviceNames names each specification after its source file without
+// extensions, or its title, numbering repeated names
+func serviceNames(specs []*Spec) []string {
+	names := make([]string, len(specs))
+	count := make(map[string]int)
+	for i, spec := range specs {
+		name, _, _ := strings.Cut(path.Base(strings.ReplaceAll(spec.Source, "\\", "/")), ".")
+		if spec.Source == "" || name == "" {
+			name = strings.Join(strings.Fields(strings.ToLower(spec.Title())), "-")
+		}
+		if name == "" {
+			name = "service"
+		}
+		count[name]++
+		if count[name] > 1 {
+			name += "-" + strconv.Itoa(count[name])
+		}
+		names[i] = name
+	}
+	return names
+}
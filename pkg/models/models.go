@@ -5,35 +5,182 @@ import "time"
 
 // APIAnalysis represents the complete analysis of an OpenAPI specification
 type APIAnalysis struct {
-	Title         string       `json:"title"`
-	Version       string       `json:"version"`
-	Description   string       `json:"description,omitempty"`
-	BaseURL       string       `json:"baseUrl,omitempty"`
-	Resources     []Resource   `json:"resources"`
-	Patterns      []Pattern    `json:"patterns,omitempty"`
-	Summary       AnalysisStat `json:"summary"`
-	GeneratedAt   time.Time    `json:"generatedAt"`
-	SpecType      string       `json:"specType"` // OpenAPI 3.x, Swagger 2.0
-	OriginalPaths int          `json:"originalPaths"`
+	Title         string           `json:"title"`
+	Version       string           `json:"version"`
+	Description   string           `json:"description,omitempty"`
+	BaseURL       string           `json:"baseUrl,omitempty"`
+	Resources     []Resource       `json:"resources"`
+	Patterns      []Pattern        `json:"patterns,omitempty"`
+	Summary       AnalysisStat     `json:"summary"`
+	GeneratedAt   time.Time        `json:"generatedAt"`
+	SpecType      string           `json:"specType"` // OpenAPI 3.x, Swagger 2.0
+	OriginalPaths int              `json:"originalPaths"`
+	Grouping      *GroupingReport  `json:"grouping,omitempty"`
+	Capabilities  []Capabilities   `json:"capabilities,omitempty"`
+	Contexts      []BoundedContext `json:"contexts,omitempty"`
+}
+
+// SystemAnalysis is the combined analysis of several APIs, each analyzed as
+// its own service, and the dependencies between them
+type SystemAnalysis struct {
+	Services     []Service           `json:"services"`
+	Dependencies []ServiceDependency `json:"dependencies,omitempty"`
+	GeneratedAt  time.Time           `json:"generatedAt"`
+}
+
+// Service is one API of a system
+type Service struct {
+	Name     string       `json:"name"`             // unique name, e.g. billing for billing.yaml
+	Source   string       `json:"source,omitempty"` // file path or URL of the specification
+	Analysis *APIAnalysis `json:"analysis"`
+}
+
+// ServiceDependency records that one service refers to resources another
+// service owns
+type ServiceDependency struct {
+	From       string           `json:"from"` // dependent service
+	To         string           `json:"to"`   // service that owns the resources
+	References []CrossReference `json:"references"`
+}
+
+// CrossReference is a field or parameter of one service that identifies a
+// resource of another
+type CrossReference struct {
+	Resource string     `json:"resource"`           // resource or schema of the dependent service, e.g. invoices
+	Via      string     `json:"via"`                // field or parameter, e.g. billingAccountId
+	Target   string     `json:"target"`             // resource of the owning service, e.g. billing-accounts
+	Evidence []Evidence `json:"evidence,omitempty"` // where the reference was observed
 }
 
 // AnalysisStat provides high-level statistics about the API
 type AnalysisStat struct {
-	TotalResources   int `json:"totalResources"`
-	TotalOperations  int `json:"totalOperations"`
-	TotalEndpoints   int `json:"totalEndpoints"`
-	ResourceCoverage int `json:"resourceCoverage"` // percentage of paths that map to resources
+	TotalResources   int              `json:"totalResources"`
+	TotalOperations  int              `json:"totalOperations"`
+	TotalEndpoints   int              `json:"totalEndpoints"`
+	ResourceCoverage int              `json:"resourceCoverage"`  // percentage of paths that map to resources
+	Skipped          []SkippedSegment `json:"skipped,omitempty"` // path segments not treated as resources
+}
+
+// SkippedSegment records a path segment that was not treated as a resource
+type SkippedSegment struct {
+	Segment string `json:"segment"`
+	Reason  string `json:"reason"`         // version, reserved, pattern, prefix
+	Rule    string `json:"rule,omitempty"` // configured pattern or prefix that matched
+	Paths   int    `json:"paths"`          // number of paths containing the segment
+}
+
+// Capabilities is one row of the capability matrix: the standard operations a
+// resource supports and the gaps between them
+type Capabilities struct {
+	Resource string   `json:"resource"`
+	List     bool     `json:"list"`
+	Get      bool     `json:"get"`
+	Create   bool     `json:"create"`
+	Update   bool     `json:"update"` // replace or partial update
+	Delete   bool     `json:"delete"`
+	Search   bool     `json:"search"`
+	Actions  []string `json:"actions,omitempty"` // custom and batch operations
+	Gaps     []string `json:"gaps,omitempty"`    // e.g. "can create but not delete"
+}
+
+// GroupingReport describes how operations were grouped into resources
+type GroupingReport struct {
+	Strategy      string                 `json:"strategy"` // path, tag, operationId, extension, hybrid
+	Disagreements []GroupingDisagreement `json:"disagreements,omitempty"`
+}
+
+// GroupingDisagreement records an operation that grouping strategies place differently
+type GroupingDisagreement struct {
+	Method      string              `json:"method"`
+	Path        string              `json:"path"`
+	Assignments map[string][]string `json:"assignments"` // strategy name -> resources
 }
 
 // Resource represents a business resource extracted from the API
 type Resource struct {
 	Name          string         `json:"name"`
+	DisplayName   string         `json:"displayName,omitempty"` // human-readable canonical name
+	Aliases       []string       `json:"aliases,omitempty"`     // raw names merged into this resource
 	Description   string         `json:"description,omitempty"`
 	Operations    []Operation    `json:"operations"`
 	Relationships []Relationship `json:"relationships,omitempty"`
 	Fields        []Field        `json:"fields,omitempty"`
-	Category      string         `json:"category,omitempty"` // core, admin, utility, etc.
+	Category      string         `json:"category,omitempty"` // core, admin, auth, utility, ops, integration
+	Context       string         `json:"context,omitempty"`  // bounded context the resource belongs to
 	IsCollection  bool           `json:"isCollection"`       // true if this represents a collection resource
+
+	// Hierarchy derived from nested paths such as /features/{featureId}/tasks
+	Parent      string               `json:"parent,omitempty"`      // enclosing resource, if always nested under one
+	Children    []string             `json:"children,omitempty"`    // resources nested under this one
+	PathPattern string               `json:"pathPattern,omitempty"` // canonical path, e.g. /features/{featureId}/tasks/{taskId}
+	Identifiers []ResourceIdentifier `json:"identifiers,omitempty"` // identifier parameter per level, outermost first
+
+	Schema    *SchemaBinding `json:"schema,omitempty"`    // schema that represents the resource
+	Lifecycle *Lifecycle     `json:"lifecycle,omitempty"` // state machine inferred from a status enum
+}
+
+// Lifecycle is the state machine of a resource: the states of its status enum
+// and the action operations that move an item between them
+type Lifecycle struct {
+	Field       string       `json:"field"`             // property holding the state, e.g. status
+	States      []string     `json:"states"`            // enum values without prefixes and placeholders such as UNSPECIFIED
+	Initial     string       `json:"initial,omitempty"` // default state, or the first one
+	Transitions []Transition `json:"transitions,omitempty"`
+}
+
+// Transition is an action operation that changes the state of an item
+type Transition struct {
+	Action  string   `json:"action"`         // custom method or path segment, e.g. cancel
+	From    []string `json:"from,omitempty"` // source states, empty when not known
+	To      string   `json:"to,omitempty"`   // target state, empty when not known
+	Method  string   `json:"method"`
+	Path    string   `json:"path"`
+	Summary string   `json:"summary,omitempty"`
+}
+
+// SchemaBinding links a resource to the schema its operations return and accept
+type SchemaBinding struct {
+	Schema    string            `json:"schema"`              // component name, or "inline" for inline schemas
+	Evidence  []BindingEvidence `json:"evidence"`            // what supports the binding
+	Conflicts []string          `json:"conflicts,omitempty"` // other schemas returned for the resource
+}
+
+// BindingEvidence records an operation or naming match supporting a schema binding
+type BindingEvidence struct {
+	Source string `json:"source"` // response, list-item, request, name
+	Method string `json:"method,omitempty"`
+	Path   string `json:"path,omitempty"`
+}
+
+// BoundedContext is a cluster of closely related resources, detected from
+// relationships, shared tags and path prefixes or pinned in config
+type BoundedContext struct {
+	Name      string        `json:"name"`
+	Resources []string      `json:"resources"`
+	Pinned    bool          `json:"pinned,omitempty"` // defined by a config rule
+	Links     []ContextLink `json:"links,omitempty"`  // relationships to other contexts
+}
+
+// ContextLink counts the relationships between resources of two contexts
+type ContextLink struct {
+	Context       string `json:"context"`
+	Relationships int    `json:"relationships"`
+}
+
+// Resource categories
+const (
+	CategoryCore        = "core"        // business resources
+	CategoryAdmin       = "admin"       // administration and management
+	CategoryAuth        = "auth"        // authentication, sessions and credentials
+	CategoryUtility     = "utility"     // search, uploads, lookups and other helpers
+	CategoryOps         = "ops"         // health, metrics and diagnostics
+	CategoryIntegration = "integration" // webhooks, callbacks and third-party connectors
+)
+
+// ResourceIdentifier names the path parameter that identifies an item at one hierarchy level
+type ResourceIdentifier struct {
+	Resource  string `json:"resource"`
+	Parameter string `json:"parameter"`
 }
 
 // Operation represents an API operation (HTTP method + path)
@@ -49,9 +196,22 @@ type Operation struct {
 	Responses    []Response   `json:"responses,omitempty"`
 	Security     []string     `json:"security,omitempty"`
 	Deprecated   bool         `json:"deprecated,omitempty"`
-	IsResourceOp bool         `json:"isResourceOp"` // true if this is a standard CRUD operation
+	Kind         string       `json:"kind,omitempty"` // list, get, create, replace, update, delete, action, batch
+	IsResourceOp bool         `json:"isResourceOp"`   // true for list, get, create, replace, update and delete kinds
 }
 
+// Operation kinds
+const (
+	KindList    = "list"    // read a collection
+	KindGet     = "get"     // read a single item
+	KindCreate  = "create"  // add an item to a collection
+	KindReplace = "replace" // replace an item (PUT)
+	KindUpdate  = "update"  // partially update an item (PATCH)
+	KindDelete  = "delete"  // remove an item
+	KindAction  = "action"  // custom method such as :cancel or /activate
+	KindBatch   = "batch"   // operate on many items at once
+)
+
 // Parameter represents operation parameters
 type Parameter struct {
 	Name        string `json:"name"`
@@ -110,9 +270,49 @@ type Relationship struct {
 	Type        string `json:"type"`                  // has_many, belongs_to, references, etc.
 	Via         string `json:"via,omitempty"`         // field or parameter that creates the relationship
 	Description string `json:"description,omitempty"` // human-readable relationship description
-	Strength    string `json:"strength"`              // strong, weak, inferred
+	Strength    string `json:"strength"`              // strong, medium or weak, derived from the confidence
+	Cardinality string `json:"cardinality,omitempty"` // one-to-one, one-to-many, many-to-one or many-to-many
+	Required    bool   `json:"required,omitempty"`    // every source has at least one target
+
+	// Expandable references are returned as an identifier unless an
+	// operation is asked to embed the object (expand[]=customer, include=author)
+	Expandable       bool     `json:"expandable,omitempty"`
+	ExpandOperations []string `json:"expandOperations,omitempty"` // operations that can expand it, e.g. GET /charges/{charge}
+
+	// Many-to-many associations through a join resource or link path, and
+	// navigation through links
+	Association string            `json:"association,omitempty"` // join resource, e.g. members
+	Operations  []string          `json:"operations,omitempty"`  // endpoints that manage the association or follow the link, e.g. PUT /groups/{groupId}/members/{userId}
+	Parameters  map[string]string `json:"parameters,omitempty"`  // link parameter mappings, e.g. userId: $response.body#/authorId
+
+	Confidence float64    `json:"confidence"`         // 0 to 1, combined from the evidence
+	Evidence   []Evidence `json:"evidence,omitempty"` // observations supporting the relationship
+}
+
+// Evidence is one observation that supports a relationship
+type Evidence struct {
+	Kind   string  `json:"kind"`   // path_hierarchy, parameter, schema_ref, link or naming
+	Source string  `json:"source"` // where it was observed, e.g. GET /orders/{orderId} or #/components/schemas/Order/properties/customer
+	Weight float64 `json:"weight"` // 0 to 1
 }
 
+// Relationship evidence kinds
+const (
+	EvidencePathHierarchy = "path_hierarchy" // nested path such as /users/{userId}/posts
+	EvidenceParameter     = "parameter"      // path parameter such as userId
+	EvidenceSchemaRef     = "schema_ref"     // $ref between schemas
+	EvidenceLink          = "link"           // OpenAPI link or hypermedia link
+	EvidenceNaming        = "naming"         // property named after a resource, such as customerId
+)
+
+// Relationship cardinalities, read from the source resource to the target
+const (
+	CardinalityOneToOne   = "one-to-one"
+	CardinalityOneToMany  = "one-to-many"
+	CardinalityManyToOne  = "many-to-one"
+	CardinalityManyToMany = "many-to-many"
+)
+
 // Pattern represents a detected API pattern
 type Pattern struct {
 	Type        string   `json:"type"`        // pagination, authentication, versioning, etc.